@@ -0,0 +1,115 @@
+// shelly-exporter exposes the status of a fleet of Shelly Plus devices as Prometheus metrics.
+//
+// The devices are read from a YAML file:
+//
+//	password: secret
+//	devices:
+//	  - name: kitchen
+//	    hostname: 192.168.1.20
+//
+// The password can also be set with the SHELLY_PASSWORD environment variable.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gopkg.in/yaml.v2"
+
+	"github.com/jodydadescott/shelly-go-sdk"
+	"github.com/jodydadescott/shelly-go-sdk/plus/collector"
+	"github.com/jodydadescott/shelly-go-sdk/plus/metrics"
+)
+
+type config struct {
+	Password string `yaml:"password"`
+	Devices  []*struct {
+		Name     string `yaml:"name"`
+		Hostname string `yaml:"hostname"`
+	} `yaml:"devices"`
+}
+
+func main() {
+
+	configFile := flag.String("config", "devices.yaml", "YAML file listing the devices")
+	listen := flag.String("listen", ":9101", "address to serve metrics on")
+	timeout := flag.Duration("timeout", time.Duration(10)*time.Second, "timeout for reading the status of a device")
+	flag.Parse()
+
+	err := run(*configFile, *listen, *timeout)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(configFile, listen string, timeout time.Duration) error {
+
+	b, err := os.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	c := &config{}
+	err = yaml.Unmarshal(b, c)
+	if err != nil {
+		return fmt.Errorf("%s: %w", configFile, err)
+	}
+
+	if password := os.Getenv("SHELLY_PASSWORD"); password != "" {
+		c.Password = password
+	}
+
+	if len(c.Devices) == 0 {
+		return errors.New("no devices configured")
+	}
+
+	var devices []*collector.Device
+
+	clientMetrics := metrics.NewPrometheus()
+
+	for _, v := range c.Devices {
+
+		client, err := shelly.New(&shelly.Config{
+			Hostname:    v.Hostname,
+			Password:    c.Password,
+			SendTimeout: timeout,
+			Metrics:     clientMetrics,
+		}).PlusClient()
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", v.Name, err)
+		}
+
+		defer client.Close()
+
+		name := v.Name
+		if name == "" {
+			name = v.Hostname
+		}
+
+		devices = append(devices, &collector.Device{
+			Name:   name,
+			Client: client.Shelly(),
+		})
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(clientMetrics)
+	registry.MustRegister(collector.New(&collector.Config{
+		Devices: devices,
+		Timeout: timeout,
+	}))
+
+	http.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	log.Printf("serving metrics for %d devices on %s", len(devices), listen)
+
+	return http.ListenAndServe(listen, nil)
+}
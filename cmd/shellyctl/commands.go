@@ -0,0 +1,459 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+func (t *cli) statusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of all components",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := t.plus()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := t.context(cmd)
+			defer cancel()
+
+			status, err := client.Shelly().GetStatus(ctx)
+			if err != nil {
+				return err
+			}
+
+			return t.print(status, "json")
+		},
+	}
+}
+
+func (t *cli) configCommand() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get or set the config of all components",
+	}
+
+	var markup bool
+
+	get := &cobra.Command{
+		Use:   "get",
+		Short: "Show the config of all components",
+		Long: "Show the config of all components. With --markup (the default) write only fields such as passwords are\n" +
+			"replaced by placeholders so that the output can be edited and passed to config set.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := t.plus()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := t.context(cmd)
+			defer cancel()
+
+			config, err := client.Shelly().GetConfig(ctx, markup)
+			if err != nil {
+				return err
+			}
+
+			return t.print(config, "yaml")
+		},
+	}
+
+	get.Flags().BoolVar(&markup, "markup", true, "replace write only fields with placeholders")
+
+	var file string
+	options := &types.ShellySetConfigOptions{}
+
+	set := &cobra.Command{
+		Use:   "set",
+		Short: "Set the config from a YAML or JSON file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			config := &types.ShellyConfig{}
+			err := decodeFile(file, config)
+			if err != nil {
+				return err
+			}
+
+			client, err := t.plus()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := t.context(cmd)
+			if options.Reboot {
+				ctx, cancel = t.waitContext(cmd, options.RebootTimeout)
+			}
+			defer cancel()
+
+			report := client.Shelly().SetConfigWithOptions(ctx, config, options)
+
+			err = report.Error()
+			if err != nil {
+				return err
+			}
+
+			if report.RebootRequired() {
+				warn("config set; a reboot is required for some changes to take effect")
+				return nil
+			}
+
+			ok("config set")
+			return nil
+		},
+	}
+
+	set.Flags().StringVarP(&file, "file", "f", "", "YAML or JSON file with the config; files ending in .json are read as JSON")
+	set.Flags().BoolVar(&options.Reboot, "reboot", false, "reboot the device if a component requires it")
+	set.Flags().BoolVar(&options.Verify, "verify", false, "read the config back and verify it was applied")
+	set.Flags().DurationVar(&options.RebootTimeout, "wait-timeout", defaultRebootWaitTimeout, "maximum time to wait for the device to come back after the reboot")
+	set.MarkFlagRequired("file")
+
+	cmd.AddCommand(get, set)
+	return cmd
+}
+
+// parseID returns the component id from the first argument or 0 if there is none
+func parseID(args []string) (int, error) {
+
+	if len(args) == 0 {
+		return 0, nil
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("id %s is not a number", args[0])
+	}
+
+	return id, nil
+}
+
+func (t *cli) switchCommand() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "switch",
+		Short: "Turn a switch on or off",
+	}
+
+	set := func(use, short string, on *bool) *cobra.Command {
+		return &cobra.Command{
+			Use:   use + " [id]",
+			Short: short,
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+
+				id, err := parseID(args)
+				if err != nil {
+					return err
+				}
+
+				client, err := t.plus()
+				if err != nil {
+					return err
+				}
+
+				ctx, cancel := t.context(cmd)
+				defer cancel()
+
+				if on == nil {
+					err = client.Switch().Toggle(ctx, id)
+				} else {
+					err = client.Switch().Set(ctx, id, on)
+				}
+
+				if err != nil {
+					return err
+				}
+
+				status, err := client.Switch().GetStatus(ctx, id)
+				if err != nil {
+					return err
+				}
+
+				if status.Output {
+					ok("switch %d is on", id)
+				} else {
+					ok("switch %d is off", id)
+				}
+
+				return nil
+			},
+		}
+	}
+
+	on := true
+	off := false
+
+	cmd.AddCommand(
+		set("on", "Turn a switch on", &on),
+		set("off", "Turn a switch off", &off),
+		set("toggle", "Toggle a switch", nil),
+	)
+
+	return cmd
+}
+
+func (t *cli) lightCommand() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "light",
+		Short: "Control a light",
+	}
+
+	var on, off bool
+	var brightness float64
+
+	set := &cobra.Command{
+		Use:   "set [id]",
+		Short: "Turn a light on or off and set its brightness",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			id, err := parseID(args)
+			if err != nil {
+				return err
+			}
+
+			if on && off {
+				return errors.New("--on and --off are mutually exclusive")
+			}
+
+			var output *bool
+			if on || off {
+				output = &on
+			}
+
+			var level *float64
+			if cmd.Flags().Changed("brightness") {
+				if brightness < 0 || brightness > 100 {
+					return errors.New("brightness must be between 0 and 100")
+				}
+				level = &brightness
+			}
+
+			if output == nil && level == nil {
+				return errors.New("one of --on, --off or --brightness is required")
+			}
+
+			client, err := t.plus()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := t.context(cmd)
+			defer cancel()
+
+			err = client.Light().Set(ctx, id, output, level)
+			if err != nil {
+				return err
+			}
+
+			status, err := client.Light().GetStatus(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			return t.print(status, "json")
+		},
+	}
+
+	set.Flags().BoolVar(&on, "on", false, "turn the light on")
+	set.Flags().BoolVar(&off, "off", false, "turn the light off")
+	set.Flags().Float64VarP(&brightness, "brightness", "b", 0, "brightness in percent, 0 to 100")
+
+	cmd.AddCommand(set)
+	return cmd
+}
+
+func (t *cli) rebootCommand() *cobra.Command {
+
+	var wait bool
+	var waitTimeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "reboot",
+		Short: "Reboot the device",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := t.plus()
+			if err != nil {
+				return err
+			}
+
+			if !wait {
+				ctx, cancel := t.context(cmd)
+				defer cancel()
+
+				err = client.Shelly().Reboot(ctx)
+				if err != nil {
+					return err
+				}
+				ok("reboot started")
+				return nil
+			}
+
+			ctx, cancel := t.waitContext(cmd, waitTimeout)
+			defer cancel()
+
+			err = client.Shelly().RebootAndWait(ctx, waitTimeout)
+			if err != nil {
+				return err
+			}
+
+			ok("device rebooted")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "wait for the device to come back")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", defaultRebootWaitTimeout, "maximum time to wait for the device to come back")
+	return cmd
+}
+
+func (t *cli) updateCommand() *cobra.Command {
+
+	var check bool
+	options := &types.ShellyUpdateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the firmware and wait for the device to come back with the new version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := t.plus()
+			if err != nil {
+				return err
+			}
+
+			if check {
+				ctx, cancel := t.context(cmd)
+				defer cancel()
+
+				updates, err := client.Shelly().CheckForUpdate(ctx)
+				if err != nil {
+					return err
+				}
+				return t.print(updates, "json")
+			}
+
+			ctx, cancel := t.waitContext(cmd, options.Timeout)
+			defer cancel()
+
+			options.Progress = func(progress *types.UpdateProgress) {
+				if progress.Percent != nil {
+					fmt.Printf("%s %d%%\n", progress.Event, *progress.Percent)
+					return
+				}
+				fmt.Println(progress.Event)
+			}
+
+			result := client.Shelly().UpdateAndWaitWithOptions(ctx, options)
+
+			err = result.Error()
+			if err != nil {
+				return err
+			}
+
+			if result.Status == types.UpdateStatusSkipped {
+				warn("update skipped: %s", result.Reason)
+				return nil
+			}
+
+			ok("updated from %s to %s", stringValue(result.FromVersion), stringValue(result.ToVersion))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Stage, "stage", "s", "stable", "stage of the firmware, stable or beta")
+	cmd.Flags().BoolVar(&check, "check", false, "only show the available updates")
+	cmd.Flags().DurationVar(&options.Timeout, "wait-timeout", defaultUpdateWaitTimeout, "maximum time to wait for the update and the device to come back")
+	return cmd
+}
+
+func (t *cli) wifiCommand() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "wifi",
+		Short: "WiFi commands",
+	}
+
+	scan := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan for WiFi networks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := t.plus()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := t.context(cmd)
+			defer cancel()
+
+			results, err := client.Wifi().Scan(ctx)
+			if err != nil {
+				return err
+			}
+
+			return t.print(results, "json")
+		},
+	}
+
+	cmd.AddCommand(scan)
+	return cmd
+}
+
+func (t *cli) methodsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "methods",
+		Short: "List the RPC methods of the device",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := t.plus()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := t.context(cmd)
+			defer cancel()
+
+			methods, err := client.Shelly().ListMethods(ctx)
+			if err != nil {
+				return err
+			}
+
+			if t.output != "" {
+				return t.print(methods, t.output)
+			}
+
+			for _, method := range methods.Methods {
+				fmt.Println(method)
+			}
+
+			return nil
+		},
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return "unknown"
+	}
+	return *s
+}
@@ -0,0 +1,150 @@
+// shellyctl controls a Shelly Plus device from the command line.
+//
+//	shellyctl --hostname 192.168.1.20 status
+//	shellyctl config get > device.yaml
+//	shellyctl config set -f device.yaml --reboot
+//	shellyctl switch toggle 0
+//	shellyctl shell
+//
+// The hostname and password can also be set with the SHELLY and SHELLY_PASSWORD environment variables.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-go-sdk"
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+var (
+	hostnameEnvVar = types.ShellyEnvVar
+	passwordEnvVar = types.ShellyEnvVar + "_PASSWORD"
+)
+
+const (
+	defaultRebootWaitTimeout = time.Duration(2) * time.Minute
+	defaultUpdateWaitTimeout = time.Duration(10) * time.Minute
+)
+
+// cli holds the global flags and the client shared by the commands
+type cli struct {
+	hostname string
+	password string
+	timeout  time.Duration
+	output   string
+	debug    bool
+	client   *shelly.Client
+	out      io.Writer
+}
+
+func main() {
+
+	c := &cli{
+		out: os.Stdout,
+	}
+
+	root := &cobra.Command{
+		Use:           "shellyctl",
+		Short:         "Control a Shelly Plus device",
+		Version:       shelly.Version,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.init()
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if c.client != nil {
+				c.client.Close()
+			}
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVarP(&c.hostname, "hostname", "H", "", "hostname or IP of the device (env "+hostnameEnvVar+")")
+	flags.StringVarP(&c.password, "password", "p", "", "password of the device (env "+passwordEnvVar+")")
+	flags.DurationVarP(&c.timeout, "timeout", "t", time.Duration(30)*time.Second, "timeout for the command")
+	flags.StringVarP(&c.output, "output", "o", "", "output format, json or yaml")
+	flags.BoolVar(&c.debug, "debug", false, "enable debug logging")
+
+	root.AddCommand(
+		c.statusCommand(),
+		c.configCommand(),
+		c.switchCommand(),
+		c.lightCommand(),
+		c.rebootCommand(),
+		c.updateCommand(),
+		c.wifiCommand(),
+		c.methodsCommand(),
+		c.shellCommand(),
+	)
+
+	err := root.Execute()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, color.RedString("Error: %v", err))
+		os.Exit(1)
+	}
+}
+
+func (t *cli) init() error {
+
+	if t.debug {
+		logger, err := zap.NewDevelopment()
+		if err != nil {
+			return err
+		}
+		zap.ReplaceGlobals(logger)
+	}
+
+	// The environment is read here rather than used as flag default so that the password is not shown
+	// in the help
+	if t.hostname == "" {
+		t.hostname = os.Getenv(hostnameEnvVar)
+	}
+
+	if t.password == "" {
+		t.password = os.Getenv(passwordEnvVar)
+	}
+
+	if t.hostname == "" {
+		return errors.New("hostname is required; use --hostname or set " + hostnameEnvVar)
+	}
+
+	switch t.output {
+	case "", "json", "yaml":
+	default:
+		return fmt.Errorf("output %s is not supported; use json or yaml", t.output)
+	}
+
+	t.client = shelly.New(&shelly.Config{
+		Hostname:     t.hostname,
+		Password:     t.password,
+		DebugEnabled: t.debug,
+		SendTimeout:  t.timeout,
+	})
+
+	return nil
+}
+
+func (t *cli) plus() (*plus.Client, error) {
+	return t.client.PlusClient()
+}
+
+func (t *cli) context(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(cmd.Context(), t.timeout)
+}
+
+// waitContext returns a context for commands that wait for the device, such as a reboot or an update. They
+// take much longer than a single RPC so the timeout is separate from --timeout.
+func (t *cli) waitContext(cmd *cobra.Command, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(cmd.Context(), timeout)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	prettyjson "github.com/hokaccha/go-prettyjson"
+	"gopkg.in/yaml.v2"
+)
+
+// print writes v to the output of the command in the format set with --output or in format if it is
+// not set. JSON is colored when stdout is a terminal.
+func (t *cli) print(v interface{}, format string) error {
+
+	if t.output != "" {
+		format = t.output
+	}
+
+	switch format {
+
+	case "yaml":
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = t.out.Write(b)
+		return err
+
+	default:
+		formatter := prettyjson.NewFormatter()
+		formatter.DisabledColor = color.NoColor
+		b, err := formatter.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(t.out, string(b))
+		return err
+	}
+}
+
+// ok prints a success message
+func ok(format string, args ...interface{}) {
+	fmt.Println(color.GreenString(format, args...))
+}
+
+// warn prints a warning
+func warn(format string, args ...interface{}) {
+	fmt.Fprintln(os.Stderr, color.YellowString(format, args...))
+}
+
+// decodeFile reads a JSON or YAML file into v. Files ending in .json are read as JSON, all other files
+// as YAML.
+func decodeFile(file string, v interface{}) error {
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		err = json.Unmarshal(b, v)
+	} else {
+		err = yaml.Unmarshal(b, v)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	return nil
+}
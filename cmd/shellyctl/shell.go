@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+)
+
+const shellHelp = `Enter a method followed by optional params, for example:
+
+  Shelly.GetDeviceInfo
+  Switch.GetStatus {"id": 0}
+  Switch.Set id=0 on=true
+
+Params are either a JSON object or key=value pairs; values are parsed as JSON and otherwise sent as
+strings. Press tab to complete a method. Other commands:
+
+  methods [filter]  list the methods of the device
+  help              show this help
+  exit              leave the shell
+`
+
+func (t *cli) shellCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Send arbitrary RPC methods from an interactive shell",
+		Long: "Send arbitrary RPC methods from an interactive shell. Methods are completed with tab from the methods\n" +
+			"the device reports. If stdin is not a terminal one call is read per line.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			client, err := t.plus()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := t.context(cmd)
+			methods, err := client.Shelly().ListMethods(ctx)
+			cancel()
+
+			if err != nil {
+				return err
+			}
+
+			s := &shell{
+				cli:     t,
+				cmd:     cmd,
+				client:  client,
+				methods: methods.Methods,
+			}
+
+			sort.Strings(s.methods)
+
+			fd := int(os.Stdin.Fd())
+			if !term.IsTerminal(fd) {
+				return s.runScript(os.Stdin)
+			}
+
+			state, err := term.MakeRaw(fd)
+			if err != nil {
+				return err
+			}
+
+			defer term.Restore(fd, state)
+
+			return s.runTerminal()
+		},
+	}
+}
+
+// shell reads method calls and prints the results
+type shell struct {
+	cli      *cli
+	cmd      *cobra.Command
+	client   *plus.Client
+	methods  []string
+	terminal *term.Terminal
+}
+
+func (t *shell) runTerminal() error {
+
+	t.terminal = term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "shelly> ")
+
+	t.terminal.AutoCompleteCallback = t.complete
+
+	// The terminal translates newlines for the raw mode
+	t.cli.out = t.terminal
+
+	fmt.Fprintf(t.terminal, "Connected to %s; %d methods available. Type help for help.\n", t.cli.hostname, len(t.methods))
+
+	for {
+
+		line, err := t.terminal.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if !t.execute(line) {
+			return nil
+		}
+	}
+}
+
+func (t *shell) runScript(r io.Reader) error {
+
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		if !t.execute(scanner.Text()) {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+// execute runs a line and returns false if the shell should exit
+func (t *shell) execute(line string) bool {
+
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return true
+	}
+
+	method, rest, _ := strings.Cut(line, " ")
+
+	switch method {
+
+	case "exit", "quit":
+		return false
+
+	case "help":
+		fmt.Fprint(t.cli.out, shellHelp)
+		return true
+
+	case "methods":
+		filter := strings.ToLower(strings.TrimSpace(rest))
+		for _, v := range t.methods {
+			if strings.Contains(strings.ToLower(v), filter) {
+				fmt.Fprintln(t.cli.out, v)
+			}
+		}
+		return true
+	}
+
+	params, err := parseParams(rest)
+	if err != nil {
+		t.printError(err)
+		return true
+	}
+
+	ctx, cancel := t.cli.context(t.cmd)
+	defer cancel()
+
+	result, err := t.client.Call(ctx, method, params)
+	if err != nil {
+		t.printError(err)
+		return true
+	}
+
+	if result == nil {
+		fmt.Fprintln(t.cli.out, color.GreenString("ok"))
+		return true
+	}
+
+	var v interface{}
+	err = json.Unmarshal(result, &v)
+	if err != nil {
+		t.printError(err)
+		return true
+	}
+
+	err = t.cli.print(v, "json")
+	if err != nil {
+		t.printError(err)
+	}
+
+	return true
+}
+
+func (t *shell) printError(err error) {
+	fmt.Fprintln(t.cli.out, color.RedString("Error: %v", err))
+}
+
+// parseParams parses a JSON object or key=value pairs. It returns nil if s is empty.
+func parseParams(s string) (json.RawMessage, error) {
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(s, "{") {
+		if !json.Valid([]byte(s)) {
+			return nil, fmt.Errorf("params are not valid JSON")
+		}
+		return json.RawMessage(s), nil
+	}
+
+	params := map[string]interface{}{}
+
+	for _, pair := range strings.Fields(s) {
+
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("%s is not a key=value pair", pair)
+		}
+
+		var value interface{}
+		if json.Unmarshal([]byte(v), &value) != nil {
+			value = v
+		}
+
+		params[k] = value
+	}
+
+	return json.Marshal(params)
+}
+
+// complete completes the method at the start of the line. If the method is ambiguous it is completed
+// to the longest common prefix and the candidates are listed.
+func (t *shell) complete(line string, pos int, key rune) (string, int, bool) {
+
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	prefix := line[:pos]
+	if strings.Contains(prefix, " ") {
+		return "", 0, false
+	}
+
+	var matches []string
+	for _, v := range append([]string{"exit", "help", "methods"}, t.methods...) {
+		if strings.HasPrefix(strings.ToLower(v), strings.ToLower(prefix)) {
+			matches = append(matches, v)
+		}
+	}
+
+	switch len(matches) {
+
+	case 0:
+		return "", 0, false
+
+	case 1:
+		completed := matches[0] + " "
+		return completed + line[pos:], len(completed), true
+	}
+
+	common := matches[0]
+	for _, v := range matches[1:] {
+		for !strings.HasPrefix(strings.ToLower(v), strings.ToLower(common)) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	if len(common) > len(prefix) {
+		return common + line[pos:], len(common), true
+	}
+
+	// Nothing to complete; list the candidates above the prompt
+	fmt.Fprintln(t.terminal, strings.Join(matches, "  "))
+	return line, pos, true
+}
@@ -7,6 +7,12 @@ type Config struct {
 	Password     string
 	DebugEnabled bool
 	SendTimeout  time.Duration
+	// RetryPolicy retries read-only and idempotent RPCs that fail with a transient error. Optional
+	RetryPolicy *RetryPolicy
+	// Interceptors are called for every RPC; the first is the outermost. Optional
+	Interceptors []Interceptor
+	// Metrics receives RPC latency, error, auth challenge, reconnect and queue depth measurements. Optional
+	Metrics Metrics
 }
 
 func (t *Config) GetHostname() string {
@@ -24,3 +30,15 @@ func (t *Config) IsDebugEnabled() bool {
 func (t *Config) GetSendTimeout() time.Duration {
 	return t.SendTimeout
 }
+
+func (t *Config) GetRetryPolicy() *RetryPolicy {
+	return t.RetryPolicy
+}
+
+func (t *Config) GetInterceptors() []Interceptor {
+	return t.Interceptors
+}
+
+func (t *Config) GetMetrics() Metrics {
+	return t.Metrics
+}
@@ -1,5 +0,0 @@
-package bluetooth
-
-const (
-	Component = "BLE"
-)
@@ -0,0 +1,70 @@
+package plus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Call sends a request for any method, including methods without a typed client, and decodes the
+// result into T. Params is marshalled as JSON and may be nil; use json.RawMessage to send params that
+// are already JSON. Authentication is handled by the message handler of the client. A device error is
+// returned as *types.Error and a missing or undecodable result as types.ErrMalformedResponse. Call is
+// safe for concurrent use.
+//
+//	status, err := plus.Call[types.SwitchStatus](ctx, client, "Switch.GetStatus", map[string]any{"id": 0})
+func Call[T any](ctx context.Context, client *Client, method string, params any) (*T, error) {
+
+	result, err := CallRaw(ctx, client, method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
+	}
+
+	var v T
+	err = json.Unmarshal(result, &v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+	}
+
+	return &v, nil
+}
+
+// CallRaw sends a request for any method like Call and returns the undecoded result. The result is nil
+// if the method has no result. Each call uses its own message handler so that concurrent calls have
+// their own request ID and response.
+func CallRaw(ctx context.Context, client *Client, method string, params any) (json.RawMessage, error) {
+
+	handle := client.NewHandle()
+	defer handle.Close()
+
+	respBytes, err := handle.Send(ctx, &types.Request{
+		Method: &method,
+		Params: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &types.RawResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	// A JSON null result is decoded as the literal null rather than nil
+	if string(response.Result) == "null" {
+		return nil, nil
+	}
+
+	return response.Result, nil
+}
@@ -0,0 +1,97 @@
+package plus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
+	"github.com/jodydadescott/shelly-go-sdk/plus/simulator"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+func TestCallConcurrent(t *testing.T) {
+
+	device := simulator.New(&simulator.Config{Password: "secret"})
+	err := device.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer device.Close()
+
+	client, err := New(&msghandlers.Config{
+		Hostname: device.Hostname(),
+		Username: types.ShellyUser,
+		Password: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		method string
+		check  func(t *testing.T) error
+	}{
+		{
+			method: "Shelly.GetDeviceInfo",
+			check: func(t *testing.T) error {
+				info, err := Call[types.DeviceInfo](ctx, client, "Shelly.GetDeviceInfo", nil)
+				if err != nil {
+					return err
+				}
+				if info.Model == nil || *info.Model != simulator.ModelPlus1PM.Model {
+					t.Errorf("device info has model %v; got the response of another call", info.Model)
+				}
+				return nil
+			},
+		},
+		{
+			method: "Sys.GetConfig",
+			check: func(t *testing.T) error {
+				config, err := Call[types.SystemConfig](ctx, client, "Sys.GetConfig", nil)
+				if err != nil {
+					return err
+				}
+				if config.Device == nil {
+					t.Errorf("sys config is missing the device; got the response of another call")
+				}
+				return nil
+			},
+		},
+		{
+			method: "Switch.GetStatus",
+			check: func(t *testing.T) error {
+				status, err := Call[types.SwitchStatus](ctx, client, "Switch.GetStatus", map[string]any{"id": 0})
+				if err != nil {
+					return err
+				}
+				if status.Source == nil {
+					t.Errorf("switch status is missing the source; got the response of another call")
+				}
+				return nil
+			},
+		},
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		for _, tt := range tests {
+			wg.Add(1)
+			go func(method string, check func(t *testing.T) error) {
+				defer wg.Done()
+				err := check(t)
+				if err != nil {
+					t.Errorf("%s: %v", method, err)
+				}
+			}(tt.method, tt.check)
+		}
+	}
+
+	wg.Wait()
+}
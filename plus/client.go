@@ -1,23 +1,18 @@
 package plus
 
+//go:generate go run gen_components.go
+
 import (
+	"context"
+	"encoding/json"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
 
-	"github.com/jodydadescott/shelly-go-sdk/plus/bluetooth"
-	"github.com/jodydadescott/shelly-go-sdk/plus/cloud"
-	"github.com/jodydadescott/shelly-go-sdk/plus/ethernet"
-	"github.com/jodydadescott/shelly-go-sdk/plus/input"
-	"github.com/jodydadescott/shelly-go-sdk/plus/light"
-	"github.com/jodydadescott/shelly-go-sdk/plus/mqtt"
 	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
 	"github.com/jodydadescott/shelly-go-sdk/plus/shelly"
-	"github.com/jodydadescott/shelly-go-sdk/plus/switchx"
-	"github.com/jodydadescott/shelly-go-sdk/plus/system"
 	"github.com/jodydadescott/shelly-go-sdk/plus/types"
-	"github.com/jodydadescott/shelly-go-sdk/plus/websocket"
-	"github.com/jodydadescott/shelly-go-sdk/plus/wifi"
 )
 
 type Config interface {
@@ -28,163 +23,150 @@ type Config interface {
 }
 
 type Client struct {
-	_system    *system.Client
-	_shelly    *shelly.Client
-	_wifi      *wifi.Client
-	_bluetooth *bluetooth.Client
-	_mqtt      *mqtt.Client
-	_cloud     *cloud.Client
-	_switch    *switchx.Client
-	_light     *light.Client
-	_input     *input.Client
-	_websocket *websocket.Client
-	_ethernet  *ethernet.Client
+	components
+	_shelly      *shelly.Client
+	retry        types.Interceptor
+	mutex        sync.RWMutex
+	interceptors []types.Interceptor
 	types.MessageHandlerFactory
 }
 
 func New(config Config) (*Client, error) {
 
-	messageHandlerFactory, err := msghandlers.NewWS(&msghandlers.Config{
+	wsConfig := &msghandlers.Config{
 		Hostname:     config.GetHostname(),
 		Password:     config.GetPassword(),
 		Username:     types.ShellyUser,
 		DebugEnabled: config.IsDebugEnabled(),
 		SendTimeout:  config.GetSendTimeout(),
-	})
+	}
+
+	if metricsConfig, ok := config.(types.MetricsConfig); ok {
+		wsConfig.Metrics = metricsConfig.GetMetrics()
+	}
+
+	messageHandlerFactory, err := msghandlers.NewWS(wsConfig)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{
+	client := &Client{
 		MessageHandlerFactory: messageHandlerFactory,
-	}, nil
-}
+	}
 
-func (t *Client) System() *system.Client {
-	if t._system == nil {
-		t._system = system.New(t)
+	if retryConfig, ok := config.(types.RetryConfig); ok {
+		if policy := retryConfig.GetRetryPolicy(); policy != nil {
+			client.retry = msghandlers.RetryInterceptor(policy)
+		}
 	}
-	return t._system
-}
 
-func (t *Client) Shelly() *shelly.Client {
-	if t._shelly == nil {
-		t._shelly = shelly.New(t)
+	if interceptorConfig, ok := config.(types.InterceptorConfig); ok {
+		client.interceptors = interceptorConfig.GetInterceptors()
 	}
-	return t._shelly
+
+	return client, nil
 }
 
-func (t *Client) Bluetooth() *bluetooth.Client {
-	if t._bluetooth == nil {
-		t._bluetooth = bluetooth.New(t)
+// NewFromFactory returns a client that sends requests with messageHandlerFactory instead of a websocket,
+// for example a recording or replay factory. Interceptors can be added with Use.
+func NewFromFactory(messageHandlerFactory types.MessageHandlerFactory) *Client {
+	return &Client{
+		MessageHandlerFactory: messageHandlerFactory,
 	}
-	return t._bluetooth
 }
 
-func (t *Client) Mqtt() *mqtt.Client {
-	if t._mqtt == nil {
-		t._mqtt = mqtt.New(t)
-	}
-	return t._mqtt
+// Use registers interceptors that are called for every request sent by the component clients. The
+// interceptors are applied when a request is sent, so they also apply to handles created earlier.
+func (t *Client) Use(interceptors ...types.Interceptor) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.interceptors = append(t.interceptors, interceptors...)
 }
 
-func (t *Client) Ethernet() *ethernet.Client {
-	if t._ethernet == nil {
-		t._ethernet = ethernet.New(t)
+// NewHandle returns a message handler from the message handler factory that passes every request
+// through the interceptors. If a retry policy is configured it is applied inside the interceptors so
+// that they see a single call per request.
+func (t *Client) NewHandle() types.MessageHandler {
+	return &handle{
+		MessageHandler: t.MessageHandlerFactory.NewHandle(),
+		client:         t,
 	}
-	return t._ethernet
 }
 
-func (t *Client) Wifi() *wifi.Client {
-	if t._wifi == nil {
-		t._wifi = wifi.New(t)
+func (t *Client) getInterceptors() []types.Interceptor {
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	interceptors := append([]types.Interceptor{}, t.interceptors...)
+
+	if t.retry != nil {
+		interceptors = append(interceptors, t.retry)
 	}
-	return t._wifi
+
+	return interceptors
 }
 
-func (t *Client) Cloud() *cloud.Client {
-	if t._cloud == nil {
-		t._cloud = cloud.New(t)
-	}
-	return t._cloud
+type handle struct {
+	types.MessageHandler
+	client *Client
 }
 
-func (t *Client) Switch() *switchx.Client {
-	if t._switch == nil {
-		t._switch = switchx.New(t)
+func (t *handle) Send(ctx context.Context, request *types.Request) ([]byte, error) {
+	return msghandlers.Chain(t.MessageHandler.Send, t.client.getInterceptors()...)(ctx, request)
+}
+
+// ConnectCount returns the number of times a connection to the device has been established. If the
+// message handler factory does not keep a persistent connection 0 is returned.
+func (t *Client) ConnectCount() int {
+	if notifier, ok := t.MessageHandlerFactory.(types.ConnectionNotifier); ok {
+		return notifier.ConnectCount()
 	}
-	return t._switch
+	return 0
 }
 
-func (t *Client) Light() *light.Client {
-	if t._light == nil {
-		t._light = light.New(t)
+// WaitConnect blocks until a connection has been established more than count times or ctx is done.
+// If the message handler factory does not keep a persistent connection it returns immediately.
+func (t *Client) WaitConnect(ctx context.Context, count int) error {
+	if notifier, ok := t.MessageHandlerFactory.(types.ConnectionNotifier); ok {
+		return notifier.WaitConnect(ctx, count)
 	}
-	return t._light
+	return nil
 }
 
-func (t *Client) Input() *input.Client {
-	if t._input == nil {
-		t._input = input.New(t)
+// Subscribe registers fn to be called for every notification sent by the device and returns a function
+// that removes the subscription. If the message handler factory does not support notifications fn is
+// never called.
+func (t *Client) Subscribe(fn func(*types.Notification)) func() {
+	if notifier, ok := t.MessageHandlerFactory.(types.NotificationNotifier); ok {
+		return notifier.Subscribe(fn)
 	}
-	return t._input
+	return func() {}
 }
 
-func (t *Client) Websocket() *websocket.Client {
-	if t._websocket == nil {
-		t._websocket = websocket.New(t)
+// Call sends a request for any method and returns the undecoded result. It is safe for concurrent use.
+// See CallRaw.
+func (t *Client) Call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	return CallRaw(ctx, t, method, params)
+}
+
+func (t *Client) Shelly() *shelly.Client {
+	if t._shelly == nil {
+		t._shelly = shelly.New(t)
 	}
-	return t._websocket
+	return t._shelly
 }
 
 func (t *Client) Close() {
 
 	zap.L().Debug("(*Client) Close()")
 
-	if t._system != nil {
-		t._system.Close()
-	}
-
 	if t._shelly != nil {
 		t._shelly.Close()
 	}
 
-	if t._wifi != nil {
-		t._wifi.Close()
-	}
-
-	if t._bluetooth != nil {
-		t._bluetooth.Close()
-	}
-
-	if t._mqtt != nil {
-		t._mqtt.Close()
-	}
-
-	if t._cloud != nil {
-		t._cloud.Close()
-	}
-
-	if t._switch != nil {
-		t._switch.Close()
-	}
-
-	if t._light != nil {
-		t._light.Close()
-	}
-
-	if t._input != nil {
-		t._input.Close()
-	}
-
-	if t._websocket != nil {
-		t._websocket.Close()
-	}
-
-	if t._ethernet != nil {
-		t._ethernet.Close()
-	}
+	t.components.close()
 
 	t.MessageHandlerFactory.Close()
 }
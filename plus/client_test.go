@@ -0,0 +1,77 @@
+package plus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
+	"github.com/jodydadescott/shelly-go-sdk/plus/simulator"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+func TestUse(t *testing.T) {
+
+	device := simulator.New(&simulator.Config{})
+	err := device.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer device.Close()
+
+	client, err := New(&msghandlers.Config{
+		Hostname:    device.Hostname(),
+		SendTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The component client creates its handle before the interceptor is registered
+	_, err = client.Switch().GetStatus(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var count int32
+	counter := func(ctx context.Context, request *types.Request, next types.SendFunc) ([]byte, error) {
+		atomic.AddInt32(&count, 1)
+		return next(ctx, request)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.Use(counter)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := client.Call(ctx, "Sys.GetStatus", nil)
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	atomic.StoreInt32(&count, 0)
+
+	_, err = client.Switch().GetStatus(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atomic.LoadInt32(&count); got != 5 {
+		t.Errorf("interceptor calls = %d, want 5", got)
+	}
+}
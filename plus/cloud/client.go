@@ -1,141 +0,0 @@
-package cloud
-
-import (
-	"context"
-	"encoding/json"
-	"fmt"
-)
-
-func New(messageHandlerFactory MessageHandlerFactory) *Client {
-	return &Client{
-		MessageHandlerFactory: messageHandlerFactory,
-	}
-}
-
-// Client the component client
-type Client struct {
-	MessageHandlerFactory
-	_messageHandler MessageHandler
-}
-
-func (t *Client) getMessageHandler() MessageHandler {
-	if t._messageHandler != nil {
-		return t._messageHandler
-	}
-
-	t._messageHandler = t.NewHandle()
-	return t._messageHandler
-}
-
-// GetStatus returns status for component or error
-func (t *Client) GetStatus(ctx context.Context) (*Status, error) {
-
-	method := Component + ".GetStatus"
-
-	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
-		Method: &method,
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	response := &GetStatusResponse{}
-	err = json.Unmarshal(respBytes, response)
-	if err != nil {
-		return nil, err
-	}
-
-	if response.Error != nil {
-		return nil, response.Error
-	}
-
-	if response.Result == nil {
-		return nil, fmt.Errorf("Result is missing from response")
-	}
-
-	return response.Result, nil
-}
-
-// GetConfig returns component config or error
-func (t *Client) GetConfig(ctx context.Context) (*Config, error) {
-
-	method := Component + ".GetConfig"
-
-	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
-		Method: &method,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	response := &GetConfigResponse{}
-	err = json.Unmarshal(respBytes, response)
-	if err != nil {
-		return nil, err
-	}
-
-	if response.Error != nil {
-		return nil, response.Error
-	}
-
-	if response.Result == nil {
-		return nil, fmt.Errorf("Result is missing from response")
-	}
-
-	response.Result.Markup()
-
-	return response.Result, nil
-}
-
-// SetConfig applies config to device component. Returns reboot required or error.
-// If reboot is requred true is returned otherwise false.
-func (t *Client) SetConfig(ctx context.Context, config *Config) (*bool, error) {
-
-	method := Component + ".SetConfig"
-
-	config = config.Clone()
-	config.Sanatize()
-
-	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
-		Method: &method,
-		Params: &Params{
-			Config: config,
-		},
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	response := &SetConfigResponse{}
-	err = json.Unmarshal(respBytes, response)
-	if err != nil {
-		return nil, err
-	}
-
-	if response.Error != nil {
-		return nil, response.Error
-	}
-
-	if response.Result == nil {
-		return nil, fmt.Errorf("Result is missing from response")
-	}
-
-	rebootRequired := false
-
-	if response.Result.RestartRequired != nil {
-		if *response.Result.RestartRequired {
-			rebootRequired = true
-		}
-	}
-
-	return &rebootRequired, nil
-}
-
-// Close closes messange handler
-func (t *Client) Close() {
-	if t._messageHandler != nil {
-		t._messageHandler.Close()
-	}
-}
@@ -1,5 +0,0 @@
-package cloud
-
-const (
-	Component = "Cloud"
-)
@@ -0,0 +1,217 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// StatusClient returns the status of a device. It is implemented by the Shelly component client.
+type StatusClient interface {
+	GetStatus(ctx context.Context) (*ShellyStatus, error)
+}
+
+// Device a device scraped by the collector
+type Device struct {
+	// Name of the device, used as the device label
+	Name string
+	// Client used to get the status. The client is not called concurrently; overlapping scrapes wait
+	// for each other.
+	Client StatusClient
+}
+
+// Config config for the collector
+type Config struct {
+	// Devices to scrape
+	Devices []*Device
+	// Timeout for getting the status of a single device. Default is 10s
+	Timeout time.Duration
+}
+
+// Collector is a prometheus.Collector that gets the status of every device on each scrape and exposes
+// switch readings, WiFi signal strength, system resources and the MQTT connection state labeled by
+// device and component ID.
+type Collector struct {
+	devices []*Device
+	timeout time.Duration
+	// mutexes serialize the scrapes of a device as the handle of a client must not be used concurrently
+	mutexes map[*Device]*sync.Mutex
+
+	up             *prometheus.Desc
+	switchOutput   *prometheus.Desc
+	switchPower    *prometheus.Desc
+	switchVoltage  *prometheus.Desc
+	switchCurrent  *prometheus.Desc
+	switchPF       *prometheus.Desc
+	switchEnergy   *prometheus.Desc
+	switchTemp     *prometheus.Desc
+	wifiRSSI       *prometheus.Desc
+	sysUptime      *prometheus.Desc
+	sysRAMSize     *prometheus.Desc
+	sysRAMFree     *prometheus.Desc
+	sysFsSize      *prometheus.Desc
+	sysFsFree      *prometheus.Desc
+	mqttConnected  *prometheus.Desc
+	scrapeDuration *prometheus.Desc
+}
+
+// New returns a new collector
+func New(config *Config) *Collector {
+
+	if config == nil {
+		config = &Config{}
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	deviceLabels := []string{"device"}
+	switchLabels := []string{"device", "id"}
+
+	desc := func(subsystem, name, help string, labels []string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, name), help, labels, nil)
+	}
+
+	mutexes := map[*Device]*sync.Mutex{}
+	for _, v := range config.Devices {
+		mutexes[v] = &sync.Mutex{}
+	}
+
+	return &Collector{
+		devices: config.Devices,
+		timeout: timeout,
+		mutexes: mutexes,
+
+		up:             desc("", "up", "1 if the status of the device could be read, 0 otherwise", deviceLabels),
+		switchOutput:   desc("switch", "output", "1 if the output channel is on, 0 otherwise", switchLabels),
+		switchPower:    desc("switch", "apower_watts", "Last measured instantaneous active power", switchLabels),
+		switchVoltage:  desc("switch", "voltage_volts", "Last measured voltage", switchLabels),
+		switchCurrent:  desc("switch", "current_amperes", "Last measured current", switchLabels),
+		switchPF:       desc("switch", "power_factor", "Last measured power factor", switchLabels),
+		switchEnergy:   desc("switch", "aenergy_total_watthours", "Total energy consumed", switchLabels),
+		switchTemp:     desc("switch", "temperature_celsius", "Temperature of the switch", switchLabels),
+		wifiRSSI:       desc("wifi", "rssi_dbm", "Strength of the WiFi signal", deviceLabels),
+		sysUptime:      desc("sys", "uptime_seconds", "Time since last reboot", deviceLabels),
+		sysRAMSize:     desc("sys", "ram_size_bytes", "Total size of the RAM", deviceLabels),
+		sysRAMFree:     desc("sys", "ram_free_bytes", "Size of the free RAM", deviceLabels),
+		sysFsSize:      desc("sys", "fs_size_bytes", "Total size of the file system", deviceLabels),
+		sysFsFree:      desc("sys", "fs_free_bytes", "Size of the free file system", deviceLabels),
+		mqttConnected:  desc("mqtt", "connected", "1 if the device is connected to the MQTT broker, 0 otherwise", deviceLabels),
+		scrapeDuration: desc("", "scrape_duration_seconds", "Time it took to get the status of the device", deviceLabels),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (t *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, v := range []*prometheus.Desc{
+		t.up, t.switchOutput, t.switchPower, t.switchVoltage, t.switchCurrent, t.switchPF, t.switchEnergy,
+		t.switchTemp, t.wifiRSSI, t.sysUptime, t.sysRAMSize, t.sysRAMFree, t.sysFsSize, t.sysFsFree,
+		t.mqttConnected, t.scrapeDuration,
+	} {
+		ch <- v
+	}
+}
+
+// Collect implements prometheus.Collector. The devices are scraped concurrently; concurrent scrapes of
+// the same device wait for each other.
+func (t *Collector) Collect(ch chan<- prometheus.Metric) {
+
+	var wg sync.WaitGroup
+
+	for _, device := range t.devices {
+
+		wg.Add(1)
+
+		go func(device *Device) {
+			defer wg.Done()
+			t.collectDevice(ch, device)
+		}(device)
+	}
+
+	wg.Wait()
+}
+
+func (t *Collector) collectDevice(ch chan<- prometheus.Metric, device *Device) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+
+	start := time.Now()
+	status, err := t.getStatus(ctx, device)
+	ch <- prometheus.MustNewConstMetric(t.scrapeDuration, prometheus.GaugeValue, time.Since(start).Seconds(), device.Name)
+
+	if err != nil {
+		zap.L().Debug(fmt.Sprintf("status of %s: %v", device.Name, err))
+		ch <- prometheus.MustNewConstMetric(t.up, prometheus.GaugeValue, 0, device.Name)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(t.up, prometheus.GaugeValue, 1, device.Name)
+
+	gauge := func(desc *prometheus.Desc, value *float64, labels ...string) {
+		if value != nil {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, *value, labels...)
+		}
+	}
+
+	for _, v := range status.Switch {
+
+		id := strconv.Itoa(v.ID)
+
+		ch <- prometheus.MustNewConstMetric(t.switchOutput, prometheus.GaugeValue, boolValue(v.Output), device.Name, id)
+
+		gauge(t.switchPower, v.Apower, device.Name, id)
+		gauge(t.switchVoltage, v.Voltage, device.Name, id)
+		gauge(t.switchCurrent, v.Current, device.Name, id)
+		gauge(t.switchPF, v.PowerFactor, device.Name, id)
+
+		if v.Aenergy != nil && v.Aenergy.Total != nil {
+			ch <- prometheus.MustNewConstMetric(t.switchEnergy, prometheus.CounterValue, *v.Aenergy.Total, device.Name, id)
+		}
+
+		if v.Temperature != nil {
+			gauge(t.switchTemp, v.Temperature.TC, device.Name, id)
+		}
+	}
+
+	if status.Wifi != nil && status.Wifi.RSSI != nil {
+		rssi := float64(*status.Wifi.RSSI)
+		gauge(t.wifiRSSI, &rssi, device.Name)
+	}
+
+	if status.System != nil {
+		gauge(t.sysUptime, status.System.Uptime, device.Name)
+		gauge(t.sysRAMSize, status.System.RAMSize, device.Name)
+		gauge(t.sysRAMFree, status.System.RAMFree, device.Name)
+		gauge(t.sysFsSize, status.System.FsSize, device.Name)
+		gauge(t.sysFsFree, status.System.FsFree, device.Name)
+	}
+
+	if status.Mqtt != nil {
+		ch <- prometheus.MustNewConstMetric(t.mqttConnected, prometheus.GaugeValue, boolValue(status.Mqtt.Connected), device.Name)
+	}
+}
+
+// getStatus returns the status of the device; the device is locked while the request is outstanding
+func (t *Collector) getStatus(ctx context.Context, device *Device) (*ShellyStatus, error) {
+
+	mutex := t.mutexes[device]
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return device.Client.GetStatus(ctx)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
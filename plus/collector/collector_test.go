@@ -0,0 +1,73 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// statusClient returns an empty status and records the highest number of concurrent calls
+type statusClient struct {
+	active int32
+	max    int32
+}
+
+func (t *statusClient) GetStatus(ctx context.Context) (*ShellyStatus, error) {
+
+	active := atomic.AddInt32(&t.active, 1)
+	defer atomic.AddInt32(&t.active, -1)
+
+	for {
+		max := atomic.LoadInt32(&t.max)
+		if active <= max || atomic.CompareAndSwapInt32(&t.max, max, active) {
+			break
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	return &ShellyStatus{}, nil
+}
+
+func TestCollectConcurrentScrapes(t *testing.T) {
+
+	a := &statusClient{}
+	b := &statusClient{}
+
+	collector := New(&Config{Devices: []*Device{{Name: "a", Client: a}, {Name: "b", Client: b}}})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ch := make(chan prometheus.Metric)
+			done := make(chan struct{})
+
+			go func() {
+				for range ch {
+				}
+				close(done)
+			}()
+
+			collector.Collect(ch)
+			close(ch)
+			<-done
+		}()
+	}
+
+	wg.Wait()
+
+	for name, client := range map[string]*statusClient{"a": a, "b": b} {
+		if client.max != 1 {
+			t.Errorf("device %s was scraped %d times concurrently", name, client.max)
+		}
+	}
+}
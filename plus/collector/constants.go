@@ -0,0 +1,11 @@
+package collector
+
+import "time"
+
+const (
+	Component = "Collector"
+
+	namespace = "shelly"
+
+	defaultTimeout = time.Duration(10) * time.Second
+)
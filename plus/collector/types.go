@@ -0,0 +1,8 @@
+package collector
+
+import (
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type ShellyStatus = types.ShellyStatus
+type SwitchStatus = types.SwitchStatus
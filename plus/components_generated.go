@@ -0,0 +1,154 @@
+// Code generated by gen_components.go; DO NOT EDIT.
+
+package plus
+
+import (
+	"github.com/jodydadescott/shelly-go-sdk/plus/bluetooth"
+	"github.com/jodydadescott/shelly-go-sdk/plus/cloud"
+	"github.com/jodydadescott/shelly-go-sdk/plus/ethernet"
+	"github.com/jodydadescott/shelly-go-sdk/plus/input"
+	"github.com/jodydadescott/shelly-go-sdk/plus/light"
+	"github.com/jodydadescott/shelly-go-sdk/plus/mqtt"
+	"github.com/jodydadescott/shelly-go-sdk/plus/switchx"
+	"github.com/jodydadescott/shelly-go-sdk/plus/system"
+	"github.com/jodydadescott/shelly-go-sdk/plus/websocket"
+	"github.com/jodydadescott/shelly-go-sdk/plus/wifi"
+)
+
+// components holds the component clients. They are created on first use.
+type components struct {
+	_system    *system.Client
+	_wifi      *wifi.Client
+	_bluetooth *bluetooth.Client
+	_mqtt      *mqtt.Client
+	_cloud     *cloud.Client
+	_switch    *switchx.Client
+	_light     *light.Client
+	_input     *input.Client
+	_websocket *websocket.Client
+	_ethernet  *ethernet.Client
+}
+
+// System returns the client of the Sys component
+func (t *Client) System() *system.Client {
+	if t._system == nil {
+		t._system = system.New(t)
+	}
+	return t._system
+}
+
+// Wifi returns the client of the Wifi component
+func (t *Client) Wifi() *wifi.Client {
+	if t._wifi == nil {
+		t._wifi = wifi.New(t)
+	}
+	return t._wifi
+}
+
+// Bluetooth returns the client of the BLE component
+func (t *Client) Bluetooth() *bluetooth.Client {
+	if t._bluetooth == nil {
+		t._bluetooth = bluetooth.New(t)
+	}
+	return t._bluetooth
+}
+
+// Mqtt returns the client of the Mqtt component
+func (t *Client) Mqtt() *mqtt.Client {
+	if t._mqtt == nil {
+		t._mqtt = mqtt.New(t)
+	}
+	return t._mqtt
+}
+
+// Cloud returns the client of the Cloud component
+func (t *Client) Cloud() *cloud.Client {
+	if t._cloud == nil {
+		t._cloud = cloud.New(t)
+	}
+	return t._cloud
+}
+
+// Switch returns the client of the Switch component
+func (t *Client) Switch() *switchx.Client {
+	if t._switch == nil {
+		t._switch = switchx.New(t)
+	}
+	return t._switch
+}
+
+// Light returns the client of the Light component
+func (t *Client) Light() *light.Client {
+	if t._light == nil {
+		t._light = light.New(t)
+	}
+	return t._light
+}
+
+// Input returns the client of the Input component
+func (t *Client) Input() *input.Client {
+	if t._input == nil {
+		t._input = input.New(t)
+	}
+	return t._input
+}
+
+// Websocket returns the client of the Ws component
+func (t *Client) Websocket() *websocket.Client {
+	if t._websocket == nil {
+		t._websocket = websocket.New(t)
+	}
+	return t._websocket
+}
+
+// Ethernet returns the client of the Eth component
+func (t *Client) Ethernet() *ethernet.Client {
+	if t._ethernet == nil {
+		t._ethernet = ethernet.New(t)
+	}
+	return t._ethernet
+}
+
+// close closes the component clients that have been created
+func (t *components) close() {
+
+	if t._system != nil {
+		t._system.Close()
+	}
+
+	if t._wifi != nil {
+		t._wifi.Close()
+	}
+
+	if t._bluetooth != nil {
+		t._bluetooth.Close()
+	}
+
+	if t._mqtt != nil {
+		t._mqtt.Close()
+	}
+
+	if t._cloud != nil {
+		t._cloud.Close()
+	}
+
+	if t._switch != nil {
+		t._switch.Close()
+	}
+
+	if t._light != nil {
+		t._light.Close()
+	}
+
+	if t._input != nil {
+		t._input.Close()
+	}
+
+	if t._websocket != nil {
+		t._websocket.Close()
+	}
+
+	if t._ethernet != nil {
+		t._ethernet.Close()
+	}
+}
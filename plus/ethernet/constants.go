@@ -1,5 +0,0 @@
-package ethernet
-
-const (
-	Component = "Eth"
-)
@@ -0,0 +1,12 @@
+package firmware
+
+const (
+	Component = "Firmware"
+
+	defaultAddr = ":8080"
+
+	imageExt = ".zip"
+
+	// pathPrefix is the URL path the images are served under
+	pathPrefix = "/firmware/"
+)
@@ -0,0 +1,362 @@
+package firmware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/shelly"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Server serves a directory of firmware images over HTTP so that devices without internet access can be
+// updated. Images are stored as <model>/<app>/<version>.zip below the directory, for example
+// SNSW-001X16EU/Plus1/1.0.3.zip.
+type Server struct {
+	mutex    sync.RWMutex
+	config   *Config
+	images   map[string]*Image
+	listener net.Listener
+	server   *http.Server
+}
+
+// New returns a new server with the images found in the directory. The server is not started.
+func New(config *Config) (*Server, error) {
+
+	if config == nil || config.Dir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+
+	t := &Server{
+		config: config,
+	}
+
+	err := t.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Reload scans the directory again
+func (t *Server) Reload() error {
+
+	images := map[string]*Image{}
+
+	err := filepath.WalkDir(t.config.Dir, func(file string, d fs.DirEntry, err error) error {
+
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(t.config.Dir, file)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		parts := strings.Split(rel, "/")
+		if len(parts) != 3 || !strings.HasSuffix(parts[2], imageExt) {
+			zap.L().Debug(fmt.Sprintf("ignoring %s; expected <model>/<app>/<version>%s", rel, imageExt))
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		images[rel] = &Image{
+			Model:   parts[0],
+			App:     parts[1],
+			Version: strings.TrimSuffix(parts[2], imageExt),
+			Path:    rel,
+			Size:    info.Size(),
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	t.images = images
+	t.mutex.Unlock()
+
+	zap.L().Debug(fmt.Sprintf("found %d firmware images in %s", len(images), t.config.Dir))
+
+	return nil
+}
+
+// Images returns the images sorted by model, app and version
+func (t *Server) Images() []*Image {
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	var result []*Image
+	for _, v := range t.images {
+		result = append(result, v.Clone())
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Model != result[j].Model {
+			return result[i].Model < result[j].Model
+		}
+		if result[i].App != result[j].App {
+			return result[i].App < result[j].App
+		}
+		return compareVersions(result[i].Version, result[j].Version) < 0
+	})
+
+	return result
+}
+
+// Find returns the image for the model and app. If version is empty the newest release is returned;
+// beta versions are only returned if they are requested explicitly or no release exists.
+func (t *Server) Find(model, app, version string) (*Image, error) {
+
+	var latest, latestBeta *Image
+
+	for _, v := range t.Images() {
+
+		if v.Model != model || v.App != app {
+			continue
+		}
+
+		if version != "" {
+			if compareVersions(v.Version, version) == 0 {
+				return v, nil
+			}
+			continue
+		}
+
+		// Images is sorted by version so the last match is the newest
+		if isPrerelease(v.Version) {
+			latestBeta = v
+		} else {
+			latest = v
+		}
+	}
+
+	if latest != nil {
+		return latest, nil
+	}
+
+	if latestBeta != nil {
+		return latestBeta, nil
+	}
+
+	if version != "" {
+		return nil, fmt.Errorf("no image for model %s app %s version %s", model, app, version)
+	}
+
+	return nil, fmt.Errorf("no image for model %s app %s", model, app)
+}
+
+// FindForDevice returns the image for the model and app reported by the device. See Find.
+func (t *Server) FindForDevice(info *DeviceInfo, version string) (*Image, error) {
+
+	if info == nil || info.Model == nil || info.App == nil {
+		return nil, fmt.Errorf("device info is missing model or app")
+	}
+
+	return t.Find(*info.Model, *info.App, version)
+}
+
+// URL returns the URL a device uses to download the image
+func (t *Server) URL(image *Image) (string, error) {
+
+	baseURL, err := t.baseURL()
+	if err != nil {
+		return "", err
+	}
+
+	return baseURL + pathPrefix + image.Path, nil
+}
+
+func (t *Server) baseURL() (string, error) {
+
+	if t.config.BaseURL != "" {
+		return strings.TrimSuffix(t.config.BaseURL, "/"), nil
+	}
+
+	addr := t.config.Addr
+	if addr == "" {
+		addr = defaultAddr
+	}
+
+	t.mutex.RLock()
+	if t.listener != nil {
+		// The listener holds the actual port if the configured port is 0
+		addr = t.listener.Addr().String()
+	}
+	t.mutex.RUnlock()
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		return "", fmt.Errorf("base URL is required when the server listens on all interfaces")
+	}
+
+	return "http://" + net.JoinHostPort(host, port), nil
+}
+
+// Start starts listening and serves the images in the background until Close is called
+func (t *Server) Start() error {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.listener != nil {
+		return fmt.Errorf("server is already started")
+	}
+
+	addr := t.config.Addr
+	if addr == "" {
+		addr = defaultAddr
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	t.listener = listener
+	t.server = &http.Server{
+		Handler:           t,
+		ReadHeaderTimeout: time.Duration(10) * time.Second,
+	}
+
+	go func(server *http.Server) {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.L().Error(fmt.Sprintf("firmware server stopped: %v", err))
+		}
+	}(t.server)
+
+	zap.L().Debug(fmt.Sprintf("firmware server listening on %s", listener.Addr()))
+
+	return nil
+}
+
+// Close stops the server
+func (t *Server) Close() error {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.server == nil {
+		return nil
+	}
+
+	err := t.server.Close()
+	t.server = nil
+	t.listener = nil
+	return err
+}
+
+// ServeHTTP serves the indexed images. Files in the directory that are not images are not served.
+func (t *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rel, ok := strings.CutPrefix(path.Clean(r.URL.Path), pathPrefix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	t.mutex.RLock()
+	image := t.images[rel]
+	t.mutex.RUnlock()
+
+	if image == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, err := os.Open(filepath.Join(t.config.Dir, filepath.FromSlash(image.Path)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	zap.L().Debug(fmt.Sprintf("serving %s to %s", image.Path, r.RemoteAddr))
+
+	w.Header().Set("Content-Type", "application/zip")
+	http.ServeContent(w, r, path.Base(image.Path), stat.ModTime(), f)
+}
+
+// Update picks the image for the device and starts Shelly.Update with its URL. If version is empty the
+// newest release is used. If the device already runs the version of the image the update is not
+// started and the image is returned together with an error wrapping types.ErrAlreadyUpToDate. The
+// server must be started and reachable by the device.
+func (t *Server) Update(ctx context.Context, client *shelly.Client, version string) (*Image, error) {
+
+	info, err := client.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := t.FindForDevice(info, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Version != nil && compareVersions(*info.Version, image.Version) == 0 {
+		zap.L().Debug(fmt.Sprintf("device already runs version %s", image.Version))
+		return image, fmt.Errorf("%w: device runs version %s", types.ErrAlreadyUpToDate, image.Version)
+	}
+
+	url, err := t.URL(image)
+	if err != nil {
+		return nil, err
+	}
+
+	zap.L().Debug(fmt.Sprintf("updating device to %s from %s", image.Version, url))
+
+	err = client.Update(ctx, &ShellyUpdateConfig{
+		Url: &url,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
@@ -0,0 +1,94 @@
+package firmware
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
+	"github.com/jodydadescott/shelly-go-sdk/plus/simulator"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+func TestUpdate(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		version string
+		image   string
+		err     error
+	}{
+		{name: "newer", version: "1.0.1", image: "1.0.1"},
+		{name: "newest release", image: "1.0.1"},
+		{name: "already up to date", version: "1.0.0", image: "1.0.0", err: types.ErrAlreadyUpToDate},
+		{name: "missing", version: "2.0.0", err: errors.New("not found")},
+	}
+
+	dir := t.TempDir()
+	for _, version := range []string{"1.0.0", "1.0.1"} {
+		file := filepath.Join(dir, simulator.ModelPlus1PM.Model, simulator.ModelPlus1PM.App, version+imageExt)
+		err := os.MkdirAll(filepath.Dir(file), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(file, []byte("image"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server, err := New(&Config{Dir: dir, BaseURL: "http://127.0.0.1:8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			device := simulator.New(&simulator.Config{Version: "1.0.0"})
+			err := device.Start()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer device.Close()
+
+			client, err := plus.New(&msghandlers.Config{Hostname: device.Hostname()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			image, err := server.Update(ctx, client.Shelly(), tt.version)
+
+			switch {
+
+			case tt.err == nil:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+			case errors.Is(tt.err, types.ErrAlreadyUpToDate):
+				if !errors.Is(err, types.ErrAlreadyUpToDate) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+
+			default:
+				if err == nil || errors.Is(err, types.ErrAlreadyUpToDate) {
+					t.Fatalf("error = %v, want an error", err)
+				}
+				return
+			}
+
+			if image == nil || image.Version != tt.image {
+				t.Errorf("image = %v, want version %s", image, tt.image)
+			}
+		})
+	}
+}
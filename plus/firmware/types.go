@@ -0,0 +1,10 @@
+package firmware
+
+import (
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type Image = types.FirmwareImage
+type Config = types.FirmwareServerConfig
+type DeviceInfo = types.DeviceInfo
+type ShellyUpdateConfig = types.ShellyUpdateConfig
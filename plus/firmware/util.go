@@ -0,0 +1,77 @@
+package firmware
+
+import (
+	"strconv"
+	"strings"
+)
+
+// compareVersions compares firmware versions such as 1.0.3 and 1.1.0-beta2. A release is newer than a
+// prerelease of the same version.
+func compareVersions(a, b string) int {
+
+	aRelease, aPre, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	bRelease, bPre, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+
+	aParts := strings.Split(aRelease, ".")
+	bParts := strings.Split(bRelease, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		if c := compareParts(partAt(aParts, i), partAt(bParts, i)); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+
+	return compareParts(aPre, bPre)
+}
+
+// compareParts compares numerically if both parts are numbers and by their text prefix and trailing
+// number otherwise so that beta10 is newer than beta9
+func compareParts(a, b string) int {
+
+	aText, aNum := splitNumber(a)
+	bText, bNum := splitNumber(b)
+
+	if aText != bText {
+		return strings.Compare(aText, bText)
+	}
+
+	switch {
+	case aNum < bNum:
+		return -1
+	case aNum > bNum:
+		return 1
+	}
+
+	return 0
+}
+
+func splitNumber(s string) (string, int) {
+
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+
+	n, _ := strconv.Atoi(s[i:])
+	return s[:i], n
+}
+
+func partAt(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return "0"
+}
+
+func isPrerelease(version string) bool {
+	return strings.Contains(version, "-")
+}
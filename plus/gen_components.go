@@ -0,0 +1,871 @@
+//go:build ignore
+
+// gen_components generates the component clients from the catalogue in components.yaml. For each
+// component it writes client_generated.go, types_generated.go and client_generated_test.go to the
+// directory of its package, the component accessors of Client to components_generated.go and the types
+// of the catalogue to types/components_generated.go.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	catalogueFile = "components.yaml"
+	header        = "// Code generated by gen_components.go; DO NOT EDIT.\n\n"
+)
+
+// Catalogue the components and types to generate
+type Catalogue struct {
+	Components []*Component `yaml:"components"`
+	Types      []*Type      `yaml:"types"`
+}
+
+// Component a component client
+type Component struct {
+	Package   string        `yaml:"package"`
+	Name      string        `yaml:"name"`
+	Accessor  string        `yaml:"accessor"`
+	Instances bool          `yaml:"instances"`
+	Status    string        `yaml:"status"`
+	Config    string        `yaml:"config"`
+	Aliases   yaml.MapSlice `yaml:"aliases"`
+	Methods   []*Method     `yaml:"methods"`
+}
+
+// Method a method of a component in addition to GetStatus, GetConfig and SetConfig
+type Method struct {
+	Name   string   `yaml:"name"`
+	Doc    string   `yaml:"doc"`
+	Params []*Param `yaml:"params"`
+	Result string   `yaml:"result"`
+}
+
+// Param a param of a method
+type Param struct {
+	Name string `yaml:"name"`
+	JSON string `yaml:"json"`
+	Type string `yaml:"type"`
+}
+
+// Type a type generated into plus/types
+type Type struct {
+	Name   string   `yaml:"name"`
+	Doc    []string `yaml:"doc"`
+	Fields []*Field `yaml:"fields"`
+}
+
+// Field a field of a type
+type Field struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	JSON string `yaml:"json"`
+	Doc  string `yaml:"doc"`
+}
+
+// testMethod a method called by the generated test of a component
+type testMethod struct {
+	Name string
+	// Params the params sent by the client, empty if it sends none
+	Params string
+	// Vars the params of the method, passed as zero values
+	Vars []*Param
+	// Call the call of the method
+	Call string
+	// Want the result for an empty result in the response, empty if the method only returns an error
+	Want string
+}
+
+// alias a type alias exported by a component package
+type alias struct {
+	Name string
+	Type string
+}
+
+// field a field of the params of a component package
+type field struct {
+	Name string
+	Type string
+	JSON string
+}
+
+func main() {
+
+	b, err := os.ReadFile(catalogueFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	catalogue := &Catalogue{}
+	err = yaml.UnmarshalStrict(b, catalogue)
+	if err != nil {
+		log.Fatalf("%s: %v", catalogueFile, err)
+	}
+
+	err = catalogue.validate()
+	if err != nil {
+		log.Fatalf("%s: %v", catalogueFile, err)
+	}
+
+	for _, component := range catalogue.Components {
+
+		err = write(filepath.Join(component.Package, "types_generated.go"), typesTemplate, component)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = write(filepath.Join(component.Package, "client_generated.go"), clientTemplate, component)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = write(filepath.Join(component.Package, "client_generated_test.go"), testTemplate, component)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err = write("components_generated.go", accessorsTemplate, catalogue)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file := filepath.Join("types", "components_generated.go")
+
+	if len(catalogue.Types) == 0 {
+		err = os.Remove(file)
+		if err != nil && !os.IsNotExist(err) {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	err = write(file, cloneTypesTemplate, catalogue)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (t *Catalogue) validate() error {
+
+	packages := map[string]bool{}
+	accessors := map[string]bool{}
+
+	for _, component := range t.Components {
+
+		if component.Package == "" || component.Name == "" || component.Accessor == "" {
+			return fmt.Errorf("package, name and accessor are required")
+		}
+
+		if !token.IsIdentifier(component.Package) || !token.IsExported(component.Accessor) {
+			return fmt.Errorf("%s: package or accessor is not a valid identifier", component.Package)
+		}
+
+		if component.Status == "" || component.Config == "" {
+			return fmt.Errorf("%s: status and config are required", component.Package)
+		}
+
+		if packages[component.Package] || accessors[component.Accessor] {
+			return fmt.Errorf("%s: package or accessor is defined twice", component.Package)
+		}
+
+		packages[component.Package] = true
+		accessors[component.Accessor] = true
+
+		methods := map[string]bool{"GetStatus": true, "GetConfig": true, "SetConfig": true}
+
+		for _, method := range component.Methods {
+
+			if !token.IsExported(method.Name) {
+				return fmt.Errorf("%s: method %s is not an exported identifier", component.Package, method.Name)
+			}
+
+			if methods[method.Name] {
+				return fmt.Errorf("%s: method %s is defined twice", component.Package, method.Name)
+			}
+
+			methods[method.Name] = true
+
+			for _, param := range method.Params {
+				if param.Name == "ctx" || param.Name == "id" || param.Name == "t" {
+					return fmt.Errorf("%s.%s: param %s is reserved", component.Package, method.Name, param.Name)
+				}
+				if !token.IsIdentifier(param.Name) || param.Type == "" {
+					return fmt.Errorf("%s.%s: param %s requires a name and a type", component.Package, method.Name, param.Name)
+				}
+			}
+		}
+
+		// The params of all methods share one struct so a param must have the same type everywhere
+		_, err := component.Fields()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range t.Types {
+		if !token.IsExported(v.Name) {
+			return fmt.Errorf("type %s is not an exported identifier", v.Name)
+		}
+		for _, f := range v.Fields {
+			if !token.IsExported(f.Name) || f.Type == "" || f.JSON == "" {
+				return fmt.Errorf("%s: field %s requires a name, a type and a json key", v.Name, f.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Field returns the name of the field of the params struct for the param
+func (t *Param) Field() string {
+	return strings.ToUpper(t.Name[:1]) + t.Name[1:]
+}
+
+// Key returns the JSON key of the param
+func (t *Param) Key() string {
+	if t.JSON != "" {
+		return t.JSON
+	}
+	return t.Name
+}
+
+// Fields returns the fields of the params struct shared by the methods other than ID and Config
+func (t *Component) Fields() ([]*field, error) {
+
+	var fields []*field
+	seen := map[string]*field{}
+
+	for _, method := range t.Methods {
+		for _, param := range method.Params {
+
+			f := &field{Name: param.Field(), Type: param.Type, JSON: param.Key()}
+
+			if existing, ok := seen[f.Name]; ok {
+				if *existing != *f {
+					return nil, fmt.Errorf("%s: param %s is defined with different types or keys", t.Package, param.Name)
+				}
+				continue
+			}
+
+			if f.Name == "ID" || f.Name == "Config" {
+				return nil, fmt.Errorf("%s: param %s is reserved", t.Package, param.Name)
+			}
+
+			seen[f.Name] = f
+			fields = append(fields, f)
+		}
+	}
+
+	return fields, nil
+}
+
+// TypeAliases returns the aliases in the order of the catalogue
+func (t *Component) TypeAliases() []*alias {
+
+	var aliases []*alias
+	for _, v := range t.Aliases {
+		aliases = append(aliases, &alias{Name: fmt.Sprint(v.Key), Type: fmt.Sprint(v.Value)})
+	}
+
+	return aliases
+}
+
+// TestMethods returns the methods called by the generated test with the params the client sends for
+// them
+func (t *Component) TestMethods() []*testMethod {
+
+	id := ""
+	params := ""
+	if t.Instances {
+		id = ", 0"
+		params = "&Params{}"
+	}
+
+	methods := []*testMethod{
+		{Name: "GetStatus", Params: params, Call: "client.GetStatus(ctx" + id + ")", Want: "&Status{}"},
+		{Name: "GetConfig", Params: params, Call: "client.GetConfig(ctx" + id + ")", Want: "markedUp(&Config{})"},
+		{Name: "SetConfig", Params: "&Params{Config: &Config{}}", Call: "client.SetConfig(ctx, &Config{})", Want: "new(bool)"},
+	}
+
+	for _, method := range t.Methods {
+
+		m := &testMethod{Name: method.Name, Params: params, Vars: method.Params}
+
+		if len(method.Params) > 0 {
+			m.Params = "&Params{}"
+		}
+
+		args := ""
+		for _, param := range method.Params {
+			args += ", " + param.Name
+		}
+
+		m.Call = "client." + method.Name + "(ctx" + id + args + ")"
+
+		if method.Result != "" {
+			m.Want = "&" + method.Result + "{}"
+		}
+
+		methods = append(methods, m)
+	}
+
+	return methods
+}
+
+// Field returns the unexported field of Client that holds the component client
+func (t *Component) Field() string {
+	return "_" + strings.ToLower(t.Accessor[:1]) + t.Accessor[1:]
+}
+
+func write(file string, tmpl *template.Template, data interface{}) error {
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+
+	err := tmpl.Execute(&buf, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: %w\n%s", file, err, buf.String())
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, b, 0644)
+}
+
+var typesTemplate = template.Must(template.New("types").Parse(`package {{.Package}}
+
+import (
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+const (
+	Component = "{{.Name}}"
+)
+
+type Request = types.Request
+type Response = types.Response
+type Error = types.Error
+type MessageHandlerFactory = types.MessageHandlerFactory
+type MessageHandler = types.MessageHandler
+
+type Status = types.{{.Status}}
+type Config = types.{{.Config}}
+{{- range .TypeAliases}}
+type {{.Name}} = types.{{.Type}}
+{{- end}}
+
+// Params internal use only
+type Params struct {
+{{- if .Instances}}
+	ID int ` + "`" + `json:"id" yaml:"id"` + "`" + `
+{{- end}}
+	Config *Config ` + "`" + `json:"config,omitempty" yaml:"config,omitempty"` + "`" + `
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSON}},omitempty" yaml:"{{.JSON}},omitempty"` + "`" + `
+{{- end}}
+}
+
+// Result internal use only
+type Result struct {
+	RestartRequired *bool ` + "`" + `json:"restart_required,omitempty"` + "`" + `
+}
+
+// GetConfigResponse internal use only
+type GetConfigResponse struct {
+	Response
+	Result *Config ` + "`" + `json:"result,omitempty"` + "`" + `
+}
+
+// SetConfigResponse internal use only
+type SetConfigResponse struct {
+	Response
+	Result *Result ` + "`" + `json:"result,omitempty"` + "`" + `
+}
+
+// GetStatusResponse internal use only
+type GetStatusResponse struct {
+	Response
+	Result *Status ` + "`" + `json:"result,omitempty"` + "`" + `
+}
+{{- range .Methods}}{{if .Result}}
+
+// {{.Name}}Response internal use only
+type {{.Name}}Response struct {
+	Response
+	Result *{{.Result}} ` + "`" + `json:"result,omitempty"` + "`" + `
+}
+{{- end}}{{end}}
+`))
+
+var clientTemplate = template.Must(template.New("client").Parse(`package {{.Package}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// New returns new instance of client
+func New(messageHandlerFactory MessageHandlerFactory) *Client {
+	return &Client{
+		MessageHandlerFactory: messageHandlerFactory,
+	}
+}
+
+// Client the component client
+type Client struct {
+	MessageHandlerFactory
+	_messageHandler MessageHandler
+}
+
+func (t *Client) getMessageHandler() MessageHandler {
+	if t._messageHandler != nil {
+		return t._messageHandler
+	}
+
+	t._messageHandler = t.NewHandle()
+	return t._messageHandler
+}
+
+// GetStatus returns status for component or error
+func (t *Client) GetStatus(ctx context.Context{{if .Instances}}, id int{{end}}) (*Status, error) {
+
+	method := Component + ".GetStatus"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: &method,
+{{- if .Instances}}
+		Params: &Params{
+			ID: id,
+		},
+{{- end}}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetStatusResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
+	}
+
+	return response.Result, nil
+}
+
+// GetConfig returns component config or error
+func (t *Client) GetConfig(ctx context.Context{{if .Instances}}, id int{{end}}) (*Config, error) {
+
+	method := Component + ".GetConfig"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: &method,
+{{- if .Instances}}
+		Params: &Params{
+			ID: id,
+		},
+{{- end}}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetConfigResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
+	}
+
+	response.Result.Markup()
+
+	return response.Result, nil
+}
+
+// SetConfig applies config to device component. Returns reboot required or error.
+// If reboot is required true is returned otherwise false.
+func (t *Client) SetConfig(ctx context.Context, config *Config) (*bool, error) {
+
+	method := Component + ".SetConfig"
+
+	config, err := types.PrepareConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: &method,
+		Params: &Params{
+{{- if .Instances}}
+			ID:     config.ID,
+{{- end}}
+			Config: config,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SetConfigResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
+	}
+
+	rebootRequired := false
+
+	if response.Result.RestartRequired != nil {
+		if *response.Result.RestartRequired {
+			rebootRequired = true
+		}
+	}
+
+	return &rebootRequired, nil
+}
+{{- $instances := .Instances}}
+{{- range .Methods}}
+
+// {{.Name}} {{.Doc}}
+func (t *Client) {{.Name}}(ctx context.Context{{if $instances}}, id int{{end}}{{range .Params}}, {{.Name}} {{.Type}}{{end}}) {{if .Result}}(*{{.Result}}, error){{else}}error{{end}} {
+
+	method := Component + ".{{.Name}}"
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: &method,
+{{- if or $instances .Params}}
+		Params: &Params{
+{{- if $instances}}
+			ID: id,
+{{- end}}
+{{- range .Params}}
+			{{.Field}}: {{.Name}},
+{{- end}}
+		},
+{{- end}}
+	})
+
+{{- if .Result}}
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &{{.Name}}Response{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
+	}
+
+	return response.Result, nil
+{{- else}}
+
+	if err != nil {
+		return err
+	}
+
+	response := &Response{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+	}
+
+	if response.Error != nil {
+		return response.Error
+	}
+
+	return nil
+{{- end}}
+}
+{{- end}}
+
+// Close closes message handler
+func (t *Client) Close() {
+	if t._messageHandler != nil {
+		t._messageHandler.Close()
+	}
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`package {{.Package}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers/recording"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// newReplayClient returns a client that gets the response of exchange from a replay
+func newReplayClient(t *testing.T, method string, params *Params, exchange *types.RecordedExchange) *Client {
+
+	exchange.Method = Component + "." + method
+
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exchange.Params = b
+	}
+
+	replay, err := recording.NewReplay(&types.Recording{
+		Version:   types.RecordingVersion,
+		Exchanges: []*types.RecordedExchange{exchange},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := New(replay)
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+// markedUp returns config with the write only fields marked up as GetConfig returns it
+func markedUp(config *Config) *Config {
+	config.Markup()
+	return config
+}
+
+func TestClient(t *testing.T) {
+
+	methods := []struct {
+		name   string
+		params *Params
+		call   func(ctx context.Context, client *Client) (interface{}, error)
+		// want result for an empty result in the response, nil if the method only returns an error
+		want interface{}
+	}{
+{{- range .TestMethods}}
+		{
+			name: "{{.Name}}",
+{{- if .Params}}
+			params: {{.Params}},
+{{- end}}
+			call: func(ctx context.Context, client *Client) (interface{}, error) {
+{{- range .Vars}}
+				var {{.Name}} {{.Type}}
+{{- end}}
+				return {{if not .Want}}nil, {{end}}{{.Call}}
+			},
+{{- if .Want}}
+			want: {{.Want}},
+{{- end}}
+		},
+{{- end}}
+	}
+
+	tests := []struct {
+		name           string
+		response       string
+		transportError *types.RecordedTransportError
+		// result true if the test only applies to methods with a result
+		result bool
+		// code of the expected device error
+		code    int
+		wantErr error
+	}{
+		{name: "ok", response: ` + "`" + `{"id":1,"src":"shellyplus1pm","result":{}}` + "`" + `},
+		{name: "device error", response: ` + "`" + `{"id":1,"src":"shellyplus1pm","error":{"code":-105,"message":"Argument 'id', value 0 not found!"}}` + "`" + `, code: -105},
+		{name: "timeout", transportError: &types.RecordedTransportError{Kind: "timeout", Message: "timeout waiting for response"}, wantErr: types.ErrTimeout},
+		{name: "missing result", response: ` + "`" + `{"id":1,"src":"shellyplus1pm"}` + "`" + `, result: true, wantErr: types.ErrMalformedResponse},
+		{name: "malformed result", response: ` + "`" + `{"id":1,"src":"shellyplus1pm","result":[]}` + "`" + `, result: true, wantErr: types.ErrMalformedResponse},
+	}
+
+	for _, method := range methods {
+		for _, tt := range tests {
+
+			if tt.result && method.want == nil {
+				continue
+			}
+
+			t.Run(method.name+"/"+tt.name, func(t *testing.T) {
+
+				client := newReplayClient(t, method.name, method.params, &types.RecordedExchange{
+					Response:       json.RawMessage(tt.response),
+					TransportError: tt.transportError,
+				})
+
+				result, err := method.call(context.Background(), client)
+
+				if tt.code != 0 {
+					var deviceErr *types.Error
+					if !errors.As(err, &deviceErr) || deviceErr.Code != tt.code {
+						t.Fatalf("error = %v, want device error %d", err, tt.code)
+					}
+					return
+				}
+
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("error = %v, want %v", err, tt.wantErr)
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(result, method.want) {
+					t.Errorf("result = %+v, want %+v", result, method.want)
+				}
+			})
+		}
+	}
+}
+
+func TestSetConfigRestart(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		response string
+		want     bool
+	}{
+		{"required", ` + "`" + `{"id":1,"src":"shellyplus1pm","result":{"restart_required":true}}` + "`" + `, true},
+		{"not required", ` + "`" + `{"id":1,"src":"shellyplus1pm","result":{"restart_required":false}}` + "`" + `, false},
+		{"omitted", ` + "`" + `{"id":1,"src":"shellyplus1pm","result":{}}` + "`" + `, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			client := newReplayClient(t, "SetConfig", &Params{Config: &Config{}}, &types.RecordedExchange{
+				Response: json.RawMessage(tt.response),
+			})
+
+			restart, err := client.SetConfig(context.Background(), &Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if restart == nil || *restart != tt.want {
+				t.Errorf("restart = %v, want %t", restart, tt.want)
+			}
+		})
+	}
+}
+`))
+
+var accessorsTemplate = template.Must(template.New("accessors").Parse(`package plus
+
+import (
+{{- range .Components}}
+	"github.com/jodydadescott/shelly-go-sdk/plus/{{.Package}}"
+{{- end}}
+)
+
+// components holds the component clients. They are created on first use.
+type components struct {
+{{- range .Components}}
+	{{.Field}} *{{.Package}}.Client
+{{- end}}
+}
+{{- range .Components}}
+
+// {{.Accessor}} returns the client of the {{.Name}} component
+func (t *Client) {{.Accessor}}() *{{.Package}}.Client {
+	if t.{{.Field}} == nil {
+		t.{{.Field}} = {{.Package}}.New(t)
+	}
+	return t.{{.Field}}
+}
+{{- end}}
+
+// close closes the component clients that have been created
+func (t *components) close() {
+{{- range .Components}}
+
+	if t.{{.Field}} != nil {
+		t.{{.Field}}.Close()
+	}
+{{- end}}
+}
+`))
+
+var cloneTypesTemplate = template.Must(template.New("types").Parse(`package types
+
+import (
+	"github.com/jinzhu/copier"
+)
+{{- range .Types}}
+
+{{range .Doc}}// {{.}}
+{{end -}}
+type {{.Name}} struct {
+{{- range .Fields}}
+{{- if .Doc}}
+	// {{.Doc}}
+{{- end}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSON}}" yaml:"{{.JSON}}"` + "`" + `
+{{- end}}
+}
+
+// Clone return copy
+func (t *{{.Name}}) Clone() *{{.Name}} {
+	c := &{{.Name}}{}
+	copier.Copy(&c, &t)
+	return c
+}
+{{- end}}
+`))
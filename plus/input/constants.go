@@ -1,5 +0,0 @@
-package input
-
-const (
-	Component = "Input"
-)
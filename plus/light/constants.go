@@ -1,5 +0,0 @@
-package light
-
-const (
-	Component = "Light"
-)
@@ -0,0 +1,9 @@
+package metrics
+
+const (
+	Component = "Metrics"
+
+	namespace = "shelly"
+
+	subsystem = "client"
+)
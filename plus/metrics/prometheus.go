@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Prometheus Metrics adapter that exposes the measurements as Prometheus metrics. Register it with a
+// prometheus.Registerer and set it as Metrics in the config of each device.
+type Prometheus struct {
+	rpcDuration    *prometheus.HistogramVec
+	rpcRequests    *prometheus.CounterVec
+	authChallenges *prometheus.CounterVec
+	reconnects     *prometheus.CounterVec
+	egressDepth    *prometheus.GaugeVec
+}
+
+// NewPrometheus returns a new Prometheus adapter
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rpc_duration_seconds",
+			Help:      "Duration of RPC attempts by method",
+			Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"hostname", "method"}),
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rpc_requests_total",
+			Help:      "RPC attempts by method and result code; the code is ok, the device error code or timeout, not_connected, closed, auth_required, malformed_response or error",
+		}, []string{"hostname", "method", "code"}),
+		authChallenges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "auth_challenges_total",
+			Help:      "Auth challenges received from the device",
+		}, []string{"hostname"}),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "reconnects_total",
+			Help:      "Connections established to the device after the initial connection",
+		}, []string{"hostname"}),
+		egressDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "egress_queue_depth",
+			Help:      "Requests waiting to be written to the device",
+		}, []string{"hostname"}),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (t *Prometheus) Describe(ch chan<- *prometheus.Desc) {
+	t.rpcDuration.Describe(ch)
+	t.rpcRequests.Describe(ch)
+	t.authChallenges.Describe(ch)
+	t.reconnects.Describe(ch)
+	t.egressDepth.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (t *Prometheus) Collect(ch chan<- prometheus.Metric) {
+	t.rpcDuration.Collect(ch)
+	t.rpcRequests.Collect(ch)
+	t.authChallenges.Collect(ch)
+	t.reconnects.Collect(ch)
+	t.egressDepth.Collect(ch)
+}
+
+// RPC implements Metrics
+func (t *Prometheus) RPC(hostname, method string, duration time.Duration, err error) {
+	t.rpcDuration.WithLabelValues(hostname, method).Observe(duration.Seconds())
+	t.rpcRequests.WithLabelValues(hostname, method, Code(err)).Inc()
+}
+
+// AuthChallenge implements Metrics
+func (t *Prometheus) AuthChallenge(hostname string) {
+	t.authChallenges.WithLabelValues(hostname).Inc()
+}
+
+// Connect implements Metrics
+func (t *Prometheus) Connect(hostname string, count int) {
+	counter := t.reconnects.WithLabelValues(hostname)
+	if count > 1 {
+		counter.Inc()
+	}
+}
+
+// EgressQueueDepth implements Metrics
+func (t *Prometheus) EgressQueueDepth(hostname string, depth int) {
+	t.egressDepth.WithLabelValues(hostname).Set(float64(depth))
+}
+
+// Code returns the result code of an RPC for use as a label
+func Code(err error) string {
+
+	if err == nil {
+		return "ok"
+	}
+
+	if e, ok := types.AsError(err); ok {
+		return strconv.Itoa(e.Code)
+	}
+
+	switch {
+	case errors.Is(err, types.ErrNotConnected):
+		return "not_connected"
+	case errors.Is(err, types.ErrTimeout):
+		return "timeout"
+	case errors.Is(err, types.ErrClosed):
+		return "closed"
+	case errors.Is(err, types.ErrAuthRequired):
+		return "auth_required"
+	case errors.Is(err, types.ErrMalformedResponse):
+		return "malformed_response"
+	}
+
+	return "error"
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+func TestCode(t *testing.T) {
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "nil", err: nil, want: "ok"},
+		{name: "device error", err: &types.Error{Code: types.ErrorCodeUnAvailable, Message: "busy"}, want: "-114"},
+		{name: "wrapped device error", err: fmt.Errorf("Switch.Set: %w", &types.Error{Code: types.ErrorCodeNotImplemented}), want: "404"},
+		{name: "not connected", err: types.ErrNotConnected, want: "not_connected"},
+		{name: "timeout", err: fmt.Errorf("%w: %w", types.ErrTimeout, errors.New("deadline")), want: "timeout"},
+		{name: "timeout not connected", err: fmt.Errorf("%w; %w", types.ErrTimeout, types.ErrNotConnected), want: "not_connected"},
+		{name: "closed", err: types.ErrClosed, want: "closed"},
+		{name: "auth required", err: fmt.Errorf("%w: password is required", types.ErrAuthRequired), want: "auth_required"},
+		{name: "malformed response", err: types.ErrMalformedResponse, want: "malformed_response"},
+		{name: "other", err: errors.New("boom"), want: "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.want {
+				t.Errorf("Code(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrometheusGather(t *testing.T) {
+
+	p := NewPrometheus()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(p)
+
+	// The first connection of a device is not a reconnect
+	p.Connect("plug-1", 1)
+	p.Connect("plug-1", 2)
+	p.Connect("plug-1", 3)
+	p.Connect("plug-2", 1)
+
+	p.EgressQueueDepth("plug-1", 3)
+	p.EgressQueueDepth("plug-1", 1)
+	p.EgressQueueDepth("plug-2", 2)
+
+	p.RPC("plug-1", "Switch.Set", 10*time.Millisecond, types.ErrTimeout)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// values by metric name and hostname
+	values := map[string]map[string]float64{}
+
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+
+			hostname := ""
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "hostname" {
+					hostname = label.GetValue()
+				}
+			}
+
+			if values[family.GetName()] == nil {
+				values[family.GetName()] = map[string]float64{}
+			}
+
+			switch {
+			case metric.Counter != nil:
+				values[family.GetName()][hostname] += metric.GetCounter().GetValue()
+			case metric.Gauge != nil:
+				values[family.GetName()][hostname] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+
+	tests := []struct {
+		metric   string
+		hostname string
+		want     float64
+	}{
+		{metric: "shelly_client_reconnects_total", hostname: "plug-1", want: 2},
+		{metric: "shelly_client_reconnects_total", hostname: "plug-2", want: 0},
+		{metric: "shelly_client_egress_queue_depth", hostname: "plug-1", want: 1},
+		{metric: "shelly_client_egress_queue_depth", hostname: "plug-2", want: 2},
+		{metric: "shelly_client_rpc_requests_total", hostname: "plug-1", want: 1},
+	}
+
+	for _, tt := range tests {
+
+		got, ok := values[tt.metric][tt.hostname]
+		if !ok {
+			t.Errorf("%s{hostname=%q} was not gathered", tt.metric, tt.hostname)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("%s{hostname=%q} = %v, want %v", tt.metric, tt.hostname, got, tt.want)
+		}
+	}
+}
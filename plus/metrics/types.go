@@ -0,0 +1,7 @@
+package metrics
+
+import (
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type Metrics = types.Metrics
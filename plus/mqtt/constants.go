@@ -1,5 +0,0 @@
-package mqtt
-
-const (
-	Component = "Mqtt"
-)
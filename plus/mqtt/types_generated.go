@@ -1,22 +1,32 @@
+// Code generated by gen_components.go; DO NOT EDIT.
+
 package mqtt
 
 import (
 	"github.com/jodydadescott/shelly-go-sdk/plus/types"
 )
 
+const (
+	Component = "Mqtt"
+)
+
 type Request = types.Request
 type Response = types.Response
 type Error = types.Error
 type MessageHandlerFactory = types.MessageHandlerFactory
 type MessageHandler = types.MessageHandler
 
-type Config = types.MqttConfig
 type Status = types.MqttStatus
+type Config = types.MqttConfig
+
+// Params internal use only
+type Params struct {
+	Config *Config `json:"config,omitempty" yaml:"config,omitempty"`
+}
 
 // Result internal use only
 type Result struct {
-	RestartRequired *bool  `json:"restart_required,omitempty"`
-	Error           *Error `json:"error,omitempty"`
+	RestartRequired *bool `json:"restart_required,omitempty"`
 }
 
 // GetConfigResponse internal use only
@@ -36,9 +46,3 @@ type GetStatusResponse struct {
 	Response
 	Result *Status `json:"result,omitempty"`
 }
-
-// Params internal use only
-type Params struct {
-	Config    *Config `json:"config,omitempty"`
-	Connected *bool   `json:"connected,omitempty"`
-}
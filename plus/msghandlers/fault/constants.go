@@ -0,0 +1,23 @@
+package fault
+
+import "time"
+
+const (
+	Component = "Fault"
+
+	defaultAddr = "127.0.0.1:0"
+
+	// wsPath path of the websocket RPC endpoint of the device
+	wsPath = "/rpc"
+
+	// holdTimeout maximum time a response is held back by FaultOutOfOrder
+	holdTimeout = time.Duration(250) * time.Millisecond
+
+	// defaultTimeout time a call of the Injector with a dropped request or response waits if the context
+	// has no deadline
+	defaultTimeout = time.Duration(5) * time.Second
+
+	authType = "digest"
+
+	authAlgorithm = "SHA-256"
+)
@@ -0,0 +1,277 @@
+package fault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// InjectorConfig config for the fault injector
+type InjectorConfig struct {
+	// Factory that sends the requests, for example a websocket, recording or replay factory. Required
+	Factory MessageHandlerFactory
+	// Policy decides which faults are injected. Required
+	Policy FaultPolicy
+	// Timeout how long a call with a dropped request or response waits before it fails with ErrTimeout if
+	// the context has no deadline. Default is 5s
+	Timeout time.Duration
+}
+
+// Injector is a MessageHandlerFactory that passes every request to another factory and injects the
+// faults of a policy into the calls. It works with any factory, including a replay, but can not reach
+// the frames, so each fault is applied to the call as a message handler would return it:
+//
+//   - latency: the call is delayed
+//   - drop_request: the request is not sent and the call fails with ErrTimeout
+//   - drop_response: the request is sent and the call fails with ErrTimeout
+//   - duplicate: the response is returned once as a handler matching request IDs discards the duplicate
+//   - disconnect: the request is sent, the handle is replaced and the call fails with ErrNotConnected
+//   - expired_nonce: the call returns the auth challenge of the device
+//   - out_of_order: the response is returned after the next call of the injector completes
+//
+// Use the Proxy to exercise the transport of the websocket message handler.
+type Injector struct {
+	mutex     sync.Mutex
+	factory   MessageHandlerFactory
+	policy    FaultPolicy
+	timeout   time.Duration
+	counts    map[FaultKind]int
+	completed chan struct{}
+}
+
+// NewInjector returns a new fault injector
+func NewInjector(config *InjectorConfig) (*Injector, error) {
+
+	if config == nil || config.Factory == nil {
+		return nil, fmt.Errorf("factory is required")
+	}
+
+	if config.Policy == nil {
+		return nil, fmt.Errorf("policy is required")
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Injector{
+		factory:   config.Factory,
+		policy:    config.Policy,
+		timeout:   timeout,
+		counts:    map[FaultKind]int{},
+		completed: make(chan struct{}),
+	}, nil
+}
+
+// NewHandle implements MessageHandlerFactory
+func (t *Injector) NewHandle() MessageHandler {
+	return &faultHandle{
+		injector: t,
+		handle:   t.factory.NewHandle(),
+	}
+}
+
+// Close implements MessageHandlerFactory
+func (t *Injector) Close() {
+	t.factory.Close()
+}
+
+// Counts returns the number of faults injected so far by kind
+func (t *Injector) Counts() map[FaultKind]int {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	counts := map[FaultKind]int{}
+	for k, v := range t.counts {
+		counts[k] = v
+	}
+
+	return counts
+}
+
+// ConnectCount implements ConnectionNotifier if the wrapped factory does
+func (t *Injector) ConnectCount() int {
+	if notifier, ok := t.factory.(types.ConnectionNotifier); ok {
+		return notifier.ConnectCount()
+	}
+	return 0
+}
+
+// WaitConnect implements ConnectionNotifier if the wrapped factory does
+func (t *Injector) WaitConnect(ctx context.Context, count int) error {
+	if notifier, ok := t.factory.(types.ConnectionNotifier); ok {
+		return notifier.WaitConnect(ctx, count)
+	}
+	return nil
+}
+
+// Subscribe implements NotificationNotifier if the wrapped factory does
+func (t *Injector) Subscribe(fn func(*Notification)) func() {
+	if notifier, ok := t.factory.(types.NotificationNotifier); ok {
+		return notifier.Subscribe(fn)
+	}
+	return func() {}
+}
+
+func (t *Injector) count(kind FaultKind) {
+	t.mutex.Lock()
+	t.counts[kind]++
+	t.mutex.Unlock()
+}
+
+// nextCompletion returns a channel that is closed when the next call completes
+func (t *Injector) nextCompletion() chan struct{} {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.completed
+}
+
+func (t *Injector) complete() {
+	t.mutex.Lock()
+	close(t.completed)
+	t.completed = make(chan struct{})
+	t.mutex.Unlock()
+}
+
+type faultHandle struct {
+	mutex    sync.Mutex
+	injector *Injector
+	handle   MessageHandler
+}
+
+func (t *faultHandle) getHandle() MessageHandler {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.handle
+}
+
+// wait blocks until ctx is done or the timeout elapsed and returns ErrTimeout
+func (t *faultHandle) wait(ctx context.Context) error {
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.injector.timeout)
+		defer cancel()
+	}
+
+	<-ctx.Done()
+
+	return fmt.Errorf("%w: %w", types.ErrTimeout, ctx.Err())
+}
+
+// reconnect closes the handle and replaces it with a new one
+func (t *faultHandle) reconnect() {
+
+	t.mutex.Lock()
+	handle := t.handle
+	t.handle = t.injector.factory.NewHandle()
+	t.mutex.Unlock()
+
+	handle.Close()
+}
+
+func (t *faultHandle) Send(ctx context.Context, request *Request) ([]byte, error) {
+
+	defer t.injector.complete()
+
+	fault := t.injector.policy.Fault(request)
+	if fault == nil {
+		return t.getHandle().Send(ctx, request)
+	}
+
+	method := ""
+	if request.Method != nil {
+		method = *request.Method
+	}
+
+	zap.L().Debug(fmt.Sprintf("injecting %s into %s", fault.Kind, method))
+
+	t.injector.count(fault.Kind)
+
+	if fault.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", types.ErrTimeout, ctx.Err())
+		case <-time.After(fault.Latency):
+		}
+	}
+
+	switch fault.Kind {
+
+	case types.FaultDropRequest:
+		return nil, t.wait(ctx)
+
+	case types.FaultDropResponse:
+		t.getHandle().Send(ctx, request)
+		return nil, t.wait(ctx)
+
+	case types.FaultDisconnect:
+		t.getHandle().Send(ctx, request)
+		t.reconnect()
+		return nil, fmt.Errorf("%w: connection lost during %s", types.ErrNotConnected, method)
+
+	case types.FaultExpiredNonce:
+		return expiredNonceResponse(request)
+
+	case types.FaultOutOfOrder:
+		// A call that completes while this one is in flight counts as the next call
+		next := t.injector.nextCompletion()
+
+		b, err := t.getHandle().Send(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-next:
+		case <-time.After(holdTimeout):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", types.ErrTimeout, ctx.Err())
+		}
+
+		return b, nil
+
+	}
+
+	return t.getHandle().Send(ctx, request)
+}
+
+func (t *faultHandle) Close() {
+	t.getHandle().Close()
+}
+
+func (t *faultHandle) IsAuthEnabled() bool {
+	return t.getHandle().IsAuthEnabled()
+}
+
+// expiredNonceResponse returns the 401 response the device sends when the nonce of the auth expired
+func expiredNonceResponse(request *Request) ([]byte, error) {
+
+	challenge, err := json.Marshal(map[string]interface{}{
+		"auth_type": authType,
+		"nonce":     time.Now().Unix(),
+		"nc":        1,
+		"realm":     "",
+		"algorithm": authAlgorithm,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&types.Response{
+		ID: request.ID,
+		Error: &types.Error{
+			Code:    types.ErrorCodeUnauthorized,
+			Message: string(challenge),
+		},
+	})
+}
@@ -0,0 +1,121 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers/recording"
+	"github.com/jodydadescott/shelly-go-sdk/plus/simulator"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// newReplayInjector returns a client that replays the golden recording through an injector
+func newReplayInjector(t *testing.T, faults ...*Fault) (*plus.Client, *Injector) {
+
+	replay, err := recording.LoadReplay("../recording/testdata/plus1pm.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	injector, err := NewInjector(&InjectorConfig{
+		Factory: replay,
+		Policy:  Script(faults...),
+		Timeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return plus.NewFromFactory(injector), injector
+}
+
+func TestInjector(t *testing.T) {
+
+	tests := []struct {
+		kind FaultKind
+		err  error
+	}{
+		{kind: types.FaultLatency},
+		{kind: types.FaultDropRequest, err: types.ErrTimeout},
+		{kind: types.FaultDropResponse, err: types.ErrTimeout},
+		{kind: types.FaultDuplicate},
+		{kind: types.FaultDisconnect, err: types.ErrNotConnected},
+		{kind: types.FaultExpiredNonce, err: types.ErrAuthRequired},
+		{kind: types.FaultOutOfOrder},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+
+			latency := 20 * time.Millisecond
+
+			client, injector := newReplayInjector(t, &Fault{Kind: tt.kind, Method: "Shelly.GetDeviceInfo", Latency: latency})
+
+			start := time.Now()
+
+			info, err := client.Shelly().GetDeviceInfo(context.Background())
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+
+			if err == nil && (info.Model == nil || *info.Model != simulator.ModelPlus1PM.Model) {
+				t.Errorf("device info has model %v", info.Model)
+			}
+
+			if elapsed := time.Since(start); elapsed < latency {
+				t.Errorf("call returned after %v, latency is %v", elapsed, latency)
+			}
+
+			if injector.Counts()[tt.kind] != 1 {
+				t.Errorf("fault was injected %d times", injector.Counts()[tt.kind])
+			}
+
+			// The fault is not injected into the next call
+			_, err = client.Shelly().GetDeviceInfo(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestInjectorOutOfOrder(t *testing.T) {
+
+	client, _ := newReplayInjector(t, &Fault{Kind: types.FaultOutOfOrder, Method: "Shelly.GetDeviceInfo"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan time.Duration, 1)
+
+	go func() {
+		_, err := client.Shelly().GetDeviceInfo(ctx)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- time.Since(start)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	select {
+	case <-done:
+		t.Fatal("held call returned before the next call")
+	default:
+	}
+
+	_, err := client.Mqtt().GetConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The held response is returned once the next call completes rather than after holdTimeout
+	if elapsed := <-done; elapsed >= holdTimeout {
+		t.Errorf("held call returned after %v, want right after the next call", elapsed)
+	}
+}
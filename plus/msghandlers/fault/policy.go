@@ -0,0 +1,123 @@
+package fault
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Script returns a policy that injects the faults in order. A fault with Method is injected into the next
+// request for that method; requests for other methods pass through. A nil fault lets one request pass
+// through. Once the script is exhausted no more faults are injected.
+func Script(faults ...*Fault) FaultPolicy {
+	return &scriptPolicy{
+		faults: faults,
+	}
+}
+
+type scriptPolicy struct {
+	mutex  sync.Mutex
+	faults []*Fault
+}
+
+func (t *scriptPolicy) Fault(request *Request) *Fault {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.faults) == 0 {
+		return nil
+	}
+
+	next := t.faults[0]
+
+	if next != nil && next.Method != "" && (request.Method == nil || *request.Method != next.Method) {
+		return nil
+	}
+
+	t.faults = t.faults[1:]
+
+	if next == nil {
+		return nil
+	}
+
+	return next.Clone()
+}
+
+// Random returns a policy that injects faults at random with the rates in config. The same seed yields
+// the same faults for the same sequence of requests.
+func Random(config *RandomFaultConfig) FaultPolicy {
+
+	if config == nil {
+		config = &RandomFaultConfig{}
+	}
+
+	config = config.Clone()
+
+	methods := map[string]bool{}
+	for _, method := range config.Methods {
+		methods[method] = true
+	}
+
+	return &randomPolicy{
+		config:  config,
+		methods: methods,
+		rand:    rand.New(rand.NewSource(config.Seed)),
+	}
+}
+
+type randomPolicy struct {
+	mutex   sync.Mutex
+	config  *RandomFaultConfig
+	methods map[string]bool
+	rand    *rand.Rand
+}
+
+func (t *randomPolicy) Fault(request *Request) *Fault {
+
+	if len(t.methods) > 0 && (request.Method == nil || !t.methods[*request.Method]) {
+		return nil
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	fault := &Fault{}
+
+	latency := t.config.MinLatency
+	if spread := t.config.MaxLatency - t.config.MinLatency; spread > 0 {
+		latency += time.Duration(t.rand.Int63n(int64(spread)))
+	}
+
+	fault.Latency = latency
+
+	// A single draw picks at most one fault so that the rates add up rather than compete
+	draw := t.rand.Float64()
+
+	for _, v := range []struct {
+		kind FaultKind
+		rate float64
+	}{
+		{types.FaultDropRequest, t.config.DropRequest},
+		{types.FaultDropResponse, t.config.DropResponse},
+		{types.FaultDuplicate, t.config.Duplicate},
+		{types.FaultDisconnect, t.config.Disconnect},
+		{types.FaultExpiredNonce, t.config.ExpiredNonce},
+		{types.FaultOutOfOrder, t.config.OutOfOrder},
+	} {
+		if draw < v.rate {
+			fault.Kind = v.kind
+			return fault
+		}
+		draw -= v.rate
+	}
+
+	if latency > 0 {
+		fault.Kind = types.FaultLatency
+		return fault
+	}
+
+	return nil
+}
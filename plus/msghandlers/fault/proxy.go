@@ -0,0 +1,417 @@
+package fault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Config config for the fault proxy
+type Config struct {
+	// Target host and port of the device or simulator, for example 127.0.0.1:8080. Required
+	Target string
+	// Policy decides which faults are injected. Required
+	Policy FaultPolicy
+	// Addr address to listen on. Default is 127.0.0.1:0 (a free port)
+	Addr string
+}
+
+// Proxy is a websocket proxy that sits between the websocket message handler and a device and injects
+// faults into the frames according to a policy: latency, dropped requests and responses, duplicate and
+// late responses, dropped connections and requests sent without auth as if the nonce expired. The
+// faults hit the transport so that the auth retry, reconnect and late response handling of the client
+// are exercised. Point the client at Hostname instead of the device.
+type Proxy struct {
+	mutex    sync.Mutex
+	target   string
+	policy   FaultPolicy
+	addr     string
+	counts   map[FaultKind]int
+	listener net.Listener
+	server   *http.Server
+	conns    map[*proxyConn]bool
+}
+
+// New returns a new proxy. It does not listen until Start is called.
+func New(config *Config) (*Proxy, error) {
+
+	if config == nil || config.Target == "" {
+		return nil, fmt.Errorf("target is required")
+	}
+
+	if config.Policy == nil {
+		return nil, fmt.Errorf("policy is required")
+	}
+
+	addr := config.Addr
+	if addr == "" {
+		addr = defaultAddr
+	}
+
+	return &Proxy{
+		target: config.Target,
+		policy: config.Policy,
+		addr:   addr,
+		counts: map[FaultKind]int{},
+		conns:  map[*proxyConn]bool{},
+	}, nil
+}
+
+// Start listens on the address and serves in the background
+func (t *Proxy) Start() error {
+
+	listener, err := net.Listen("tcp", t.addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(wsPath, t.serveWS)
+
+	t.mutex.Lock()
+	t.listener = listener
+	t.server = &http.Server{Handler: mux}
+	server := t.server
+	t.mutex.Unlock()
+
+	go server.Serve(listener)
+
+	zap.L().Debug(fmt.Sprintf("fault proxy for %s listening on %s", t.target, listener.Addr()))
+
+	return nil
+}
+
+// Hostname returns the address the proxy listens on, to be used as hostname of the client
+func (t *Proxy) Hostname() string {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.listener == nil {
+		return t.addr
+	}
+
+	return t.listener.Addr().String()
+}
+
+// Close stops the proxy and closes all connections
+func (t *Proxy) Close() error {
+
+	t.mutex.Lock()
+	server := t.server
+	var conns []*proxyConn
+	for v := range t.conns {
+		conns = append(conns, v)
+	}
+	t.mutex.Unlock()
+
+	for _, v := range conns {
+		v.close()
+	}
+
+	if server == nil {
+		return nil
+	}
+
+	return server.Shutdown(context.Background())
+}
+
+// Counts returns the number of faults injected so far by kind
+func (t *Proxy) Counts() map[FaultKind]int {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	counts := map[FaultKind]int{}
+	for k, v := range t.counts {
+		counts[k] = v
+	}
+
+	return counts
+}
+
+func (t *Proxy) count(kind FaultKind) {
+	t.mutex.Lock()
+	t.counts[kind]++
+	t.mutex.Unlock()
+}
+
+func (t *Proxy) serveWS(w http.ResponseWriter, r *http.Request) {
+
+	upgrader := gorilla.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+
+	client, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	targetURL := url.URL{Scheme: "ws", Host: t.target, Path: wsPath}
+	device, _, err := gorilla.DefaultDialer.Dial(targetURL.String(), nil)
+	if err != nil {
+		zap.L().Debug(fmt.Sprintf("fault proxy can not connect to %s: %v", t.target, err))
+		client.Close()
+		return
+	}
+
+	c := &proxyConn{
+		proxy:   t,
+		client:  client,
+		device:  device,
+		pending: map[int]*pendingRequest{},
+	}
+
+	t.mutex.Lock()
+	t.conns[c] = true
+	t.mutex.Unlock()
+
+	go c.ingress()
+	c.egress()
+
+	t.mutex.Lock()
+	delete(t.conns, c)
+	t.mutex.Unlock()
+}
+
+// proxyConn a client connection and its connection to the device
+type proxyConn struct {
+	mutex     sync.Mutex
+	proxy     *Proxy
+	client    *gorilla.Conn
+	device    *gorilla.Conn
+	closeOnce sync.Once
+	// clientMutex and deviceMutex serialize the writes to the connections
+	clientMutex sync.Mutex
+	deviceMutex sync.Mutex
+	// nextID last request ID assigned by the proxy
+	nextID int
+	// pending requests by the request ID assigned by the proxy
+	pending map[int]*pendingRequest
+	// held responses delivered after the next response
+	held [][]byte
+}
+
+// pendingRequest a request passed to the device that waits for its response
+type pendingRequest struct {
+	// id request ID assigned by the client
+	id int
+	// kind fault to apply to the response
+	kind FaultKind
+}
+
+func (t *proxyConn) close() {
+	t.closeOnce.Do(func() {
+		t.client.Close()
+		t.device.Close()
+	})
+}
+
+func (t *proxyConn) writeClient(messageType int, b []byte) error {
+	t.clientMutex.Lock()
+	defer t.clientMutex.Unlock()
+	return t.client.WriteMessage(messageType, b)
+}
+
+func (t *proxyConn) writeDevice(messageType int, b []byte) error {
+	t.deviceMutex.Lock()
+	defer t.deviceMutex.Unlock()
+	return t.device.WriteMessage(messageType, b)
+}
+
+// track assigns a request ID of the proxy to the request and returns the frame with that ID. Clients
+// may reuse request IDs; the ID of the proxy ties the fault to the response of this request.
+func (t *proxyConn) track(b []byte, id int, kind FaultKind) []byte {
+
+	t.mutex.Lock()
+	t.nextID++
+	proxyID := t.nextID
+	t.pending[proxyID] = &pendingRequest{id: id, kind: kind}
+	t.mutex.Unlock()
+
+	return withField(b, "id", proxyID)
+}
+
+// flushHeld delivers the held responses
+func (t *proxyConn) flushHeld() {
+
+	t.mutex.Lock()
+	held := t.held
+	t.held = nil
+	t.mutex.Unlock()
+
+	for _, frame := range held {
+		if t.writeClient(gorilla.TextMessage, frame) != nil {
+			return
+		}
+	}
+}
+
+// egress passes the requests of the client to the device and injects the faults
+func (t *proxyConn) egress() {
+
+	defer t.close()
+
+	for {
+
+		messageType, b, err := t.client.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		request := &Request{}
+		if json.Unmarshal(b, request) != nil || request.Method == nil {
+			err = t.writeDevice(messageType, b)
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		var kind FaultKind
+		var latency time.Duration
+
+		fault := t.proxy.policy.Fault(request)
+		if fault != nil {
+			zap.L().Debug(fmt.Sprintf("injecting %s into %s", fault.Kind, *request.Method))
+			t.proxy.count(fault.Kind)
+			kind = fault.Kind
+			latency = fault.Latency
+		}
+
+		switch kind {
+
+		case types.FaultDropRequest:
+			continue
+
+		case types.FaultExpiredNonce:
+			b = withField(b, "auth", nil)
+
+		}
+
+		if request.ID != nil {
+			b = t.track(b, *request.ID, kind)
+		}
+
+		forward := func() {
+			err := t.writeDevice(messageType, b)
+			if err != nil || kind == types.FaultDisconnect {
+				t.close()
+			}
+		}
+
+		// A delayed request does not hold up the requests behind it
+		if latency > 0 {
+			time.AfterFunc(latency, forward)
+			continue
+		}
+
+		forward()
+	}
+}
+
+// ingress passes the responses and notifications of the device to the client and applies the pending
+// faults
+func (t *proxyConn) ingress() {
+
+	defer t.close()
+
+	for {
+
+		messageType, b, err := t.device.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		response := &Response{}
+		json.Unmarshal(b, response)
+
+		if response.ID == nil {
+			// Notifications are passed as they are
+			err = t.writeClient(messageType, b)
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		t.mutex.Lock()
+		pending := t.pending[*response.ID]
+		delete(t.pending, *response.ID)
+		t.mutex.Unlock()
+
+		var kind FaultKind
+
+		if pending != nil {
+			b = withField(b, "id", pending.id)
+			kind = pending.kind
+		}
+
+		switch kind {
+
+		case types.FaultDropResponse:
+			continue
+
+		case types.FaultOutOfOrder:
+			t.mutex.Lock()
+			t.held = append(t.held, b)
+			t.mutex.Unlock()
+			// The response is delivered after the next response or after holdTimeout if there is none
+			time.AfterFunc(holdTimeout, t.flushHeld)
+			continue
+
+		}
+
+		frames := [][]byte{b}
+
+		if kind == types.FaultDuplicate {
+			frames = append(frames, b)
+		}
+
+		for _, frame := range frames {
+			err = t.writeClient(messageType, frame)
+			if err != nil {
+				return
+			}
+		}
+
+		t.flushHeld()
+	}
+}
+
+// withField returns the frame with the field set to value or removed if value is nil. The frame is
+// returned unchanged if it can not be decoded.
+func withField(b []byte, key string, value interface{}) []byte {
+
+	frame := map[string]json.RawMessage{}
+	if json.Unmarshal(b, &frame) != nil {
+		return b
+	}
+
+	if value == nil {
+		delete(frame, key)
+	} else {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return b
+		}
+		frame[key] = raw
+	}
+
+	result, err := json.Marshal(frame)
+	if err != nil {
+		return b
+	}
+
+	return result
+}
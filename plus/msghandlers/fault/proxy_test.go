@@ -0,0 +1,253 @@
+package fault
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
+	"github.com/jodydadescott/shelly-go-sdk/plus/simulator"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// challengeMetrics counts the auth challenges
+type challengeMetrics struct {
+	types.NopMetrics
+	challenges int32
+}
+
+func (t *challengeMetrics) AuthChallenge(hostname string) {
+	atomic.AddInt32(&t.challenges, 1)
+}
+
+func TestProxyRecovery(t *testing.T) {
+
+	tests := []struct {
+		kind FaultKind
+		// challenges auth challenges after the first one
+		challenges int32
+		// reconnects number of reconnects
+		reconnects int
+	}{
+		{kind: types.FaultLatency},
+		{kind: types.FaultDropRequest},
+		{kind: types.FaultDropResponse},
+		{kind: types.FaultDuplicate},
+		{kind: types.FaultOutOfOrder},
+		{kind: types.FaultExpiredNonce, challenges: 1},
+		{kind: types.FaultDisconnect, reconnects: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+
+			device := simulator.New(&simulator.Config{Password: "secret"})
+			err := device.Start()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer device.Close()
+
+			proxy, err := New(&Config{
+				Target: device.Hostname(),
+				Policy: Script(&Fault{Kind: tt.kind, Method: "Sys.GetConfig", Latency: 10 * time.Millisecond}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = proxy.Start()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer proxy.Close()
+
+			metrics := &challengeMetrics{}
+
+			client, err := plus.New(&msghandlers.Config{
+				Hostname:    proxy.Hostname(),
+				Username:    types.ShellyUser,
+				Password:    "secret",
+				SendTimeout: time.Second,
+				Metrics:     metrics,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			client.Use(msghandlers.RetryInterceptor(&types.RetryPolicy{MaxAttempts: 8, InitialBackoff: 50 * time.Millisecond}))
+
+			notifier := client.MessageHandlerFactory.(types.ConnectionNotifier)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			defer cancel()
+
+			// Authenticates before the fault is injected
+			_, err = client.System().GetStatus(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			challenges := atomic.LoadInt32(&metrics.challenges)
+			connects := notifier.ConnectCount()
+
+			config, err := client.System().GetConfig(ctx)
+			if err != nil {
+				t.Fatalf("request with %s fault failed: %v", tt.kind, err)
+			}
+
+			if config.Device == nil {
+				t.Errorf("sys config is missing the device")
+			}
+
+			if proxy.Counts()[tt.kind] != 1 {
+				t.Errorf("fault was injected %d times", proxy.Counts()[tt.kind])
+			}
+
+			// Late and duplicate responses arrive while no request is waiting; the next request must
+			// discard them rather than take one as its response
+			time.Sleep(100 * time.Millisecond)
+
+			info, err := client.Shelly().GetDeviceInfo(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if info.Model == nil || *info.Model != simulator.ModelPlus1PM.Model {
+				t.Errorf("device info has model %v; got a stale response", info.Model)
+			}
+
+			if got := atomic.LoadInt32(&metrics.challenges) - challenges; got != tt.challenges {
+				t.Errorf("auth challenges = %d, want %d", got, tt.challenges)
+			}
+
+			if got := notifier.ConnectCount() - connects; got != tt.reconnects {
+				t.Errorf("reconnects = %d, want %d", got, tt.reconnects)
+			}
+		})
+	}
+}
+
+// startProxy starts a device without auth and a proxy in front of it and returns a raw websocket
+// connection to the proxy
+func startProxy(t *testing.T, policy FaultPolicy) *gorilla.Conn {
+
+	device := simulator.New(&simulator.Config{})
+	err := device.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { device.Close() })
+
+	proxy, err := New(&Config{Target: device.Hostname(), Policy: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = proxy.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { proxy.Close() })
+
+	conn, _, err := gorilla.DefaultDialer.Dial("ws://"+proxy.Hostname()+wsPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// readResponses reads responses until no frame arrives for wait and returns the methods they answer,
+// identified by the result, in the order they arrived
+func readResponses(t *testing.T, conn *gorilla.Conn, wait time.Duration) []string {
+
+	var methods []string
+
+	for {
+
+		conn.SetReadDeadline(time.Now().Add(wait))
+
+		response := &struct {
+			ID     *int                   `json:"id"`
+			Result map[string]interface{} `json:"result"`
+		}{}
+
+		err := conn.ReadJSON(response)
+		if err != nil {
+			return methods
+		}
+
+		if response.ID == nil {
+			continue
+		}
+
+		if *response.ID != 7 {
+			t.Errorf("response has id %d, want the id of the request", *response.ID)
+		}
+
+		if _, ok := response.Result["model"]; ok {
+			methods = append(methods, "Shelly.GetDeviceInfo")
+		} else {
+			methods = append(methods, "Sys.GetStatus")
+		}
+	}
+}
+
+func TestProxyConn(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		faults []*Fault
+		// methods requests sent back to back, all with the same id
+		methods []string
+		// want responses in the order they arrive
+		want []string
+	}{
+		{
+			name:    "reused request id",
+			faults:  []*Fault{{Kind: types.FaultDuplicate, Method: "Sys.GetStatus"}, {Kind: types.FaultDropResponse, Method: "Shelly.GetDeviceInfo"}},
+			methods: []string{"Sys.GetStatus", "Shelly.GetDeviceInfo"},
+			want:    []string{"Sys.GetStatus", "Sys.GetStatus"},
+		},
+		{
+			name:    "held without next response",
+			faults:  []*Fault{{Kind: types.FaultOutOfOrder, Method: "Sys.GetStatus"}},
+			methods: []string{"Sys.GetStatus"},
+			want:    []string{"Sys.GetStatus"},
+		},
+		{
+			name:    "latency does not block the connection",
+			faults:  []*Fault{{Kind: types.FaultLatency, Method: "Sys.GetStatus", Latency: 300 * time.Millisecond}},
+			methods: []string{"Sys.GetStatus", "Shelly.GetDeviceInfo"},
+			want:    []string{"Shelly.GetDeviceInfo", "Sys.GetStatus"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			conn := startProxy(t, Script(tt.faults...))
+
+			for _, method := range tt.methods {
+				err := conn.WriteJSON(map[string]interface{}{"id": 7, "src": "test", "method": method})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := readResponses(t, conn, 2*holdTimeout)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("responses = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
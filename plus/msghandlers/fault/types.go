@@ -0,0 +1,15 @@
+package fault
+
+import (
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type MessageHandlerFactory = types.MessageHandlerFactory
+type MessageHandler = types.MessageHandler
+type Request = types.Request
+type Response = types.Response
+type Notification = types.Notification
+type Fault = types.Fault
+type FaultKind = types.FaultKind
+type FaultPolicy = types.FaultPolicy
+type RandomFaultConfig = types.RandomFaultConfig
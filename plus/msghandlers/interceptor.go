@@ -0,0 +1,49 @@
+package msghandlers
+
+import (
+	"context"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type Interceptor = types.Interceptor
+type SendFunc = types.SendFunc
+
+// NewInterceptorHandler returns a message handler that passes every request through the interceptors
+// before it is sent with handler. The first interceptor is the outermost.
+func NewInterceptorHandler(handler MessageHandler, interceptors ...Interceptor) MessageHandler {
+
+	if len(interceptors) == 0 {
+		return handler
+	}
+
+	return &interceptorHandler{
+		MessageHandler: handler,
+		send:           Chain(handler.Send, interceptors...),
+	}
+}
+
+// Chain returns send wrapped by the interceptors. The first interceptor is the outermost.
+func Chain(send SendFunc, interceptors ...Interceptor) SendFunc {
+
+	for i := len(interceptors) - 1; i >= 0; i-- {
+
+		interceptor := interceptors[i]
+		next := send
+
+		send = func(ctx context.Context, request *Request) ([]byte, error) {
+			return interceptor(ctx, request, next)
+		}
+	}
+
+	return send
+}
+
+type interceptorHandler struct {
+	MessageHandler
+	send SendFunc
+}
+
+func (t *interceptorHandler) Send(ctx context.Context, request *Request) ([]byte, error) {
+	return t.send(ctx, request)
+}
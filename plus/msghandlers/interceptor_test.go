@@ -0,0 +1,39 @@
+package msghandlers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestChainOrder(t *testing.T) {
+
+	var calls []string
+
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, request *Request, next SendFunc) ([]byte, error) {
+			calls = append(calls, name+" before")
+			b, err := next(ctx, request)
+			calls = append(calls, name+" after")
+			return b, err
+		}
+	}
+
+	send := func(ctx context.Context, request *Request) ([]byte, error) {
+		calls = append(calls, "send")
+		return nil, nil
+	}
+
+	method := "Shelly.GetStatus"
+	_, err := Chain(send, record("first"), record("second"))(context.Background(), &Request{Method: &method})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first interceptor is the outermost
+	want := []string{"first before", "second before", "send", "second after", "first after"}
+
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
@@ -18,6 +18,7 @@ type Config struct {
 	Username     string
 	SendTimeout  time.Duration
 	DebugEnabled bool
+	Metrics      types.Metrics
 }
 
 func (t *Config) GetHostname() string {
@@ -40,6 +41,10 @@ func (t *Config) IsDebugEnabled() bool {
 	return t.DebugEnabled
 }
 
+func (t *Config) GetMetrics() types.Metrics {
+	return t.Metrics
+}
+
 func NewWS(config *Config) (MessageHandlerFactory, error) {
 	return ws.New(config)
 }
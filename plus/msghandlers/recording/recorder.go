@@ -0,0 +1,137 @@
+package recording
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Recorder is a MessageHandlerFactory that passes every request to another factory and records the
+// request and response frames so that they can be saved as a golden file and served by Replay. Params
+// are recorded as sent except for the write only fields such as passwords, which are replaced with
+// Redacted; use Redact to remove other secrets before the recording is saved.
+type Recorder struct {
+	mutex     sync.Mutex
+	factory   MessageHandlerFactory
+	exchanges []*RecordedExchange
+	// RecordWriteOnly if true the write only fields are recorded as sent. Default is false
+	RecordWriteOnly bool
+	// Redact is called for every exchange before it is recorded. Optional
+	Redact func(*RecordedExchange)
+}
+
+// NewRecorder returns a recorder that sends requests with factory
+func NewRecorder(factory MessageHandlerFactory) *Recorder {
+	return &Recorder{
+		factory: factory,
+	}
+}
+
+// NewHandle implements MessageHandlerFactory
+func (t *Recorder) NewHandle() MessageHandler {
+	return &recordingHandle{
+		MessageHandler: t.factory.NewHandle(),
+		recorder:       t,
+	}
+}
+
+// Close implements MessageHandlerFactory
+func (t *Recorder) Close() {
+	t.factory.Close()
+}
+
+// Recording returns the exchanges recorded so far
+func (t *Recorder) Recording() *Recording {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return &Recording{
+		Version:   types.RecordingVersion,
+		Exchanges: append([]*RecordedExchange{}, t.exchanges...),
+	}
+}
+
+// Save writes the exchanges recorded so far to file
+func (t *Recorder) Save(file string) error {
+	return t.Recording().Save(file)
+}
+
+// ConnectCount implements ConnectionNotifier if the wrapped factory does
+func (t *Recorder) ConnectCount() int {
+	if notifier, ok := t.factory.(types.ConnectionNotifier); ok {
+		return notifier.ConnectCount()
+	}
+	return 0
+}
+
+// WaitConnect implements ConnectionNotifier if the wrapped factory does
+func (t *Recorder) WaitConnect(ctx context.Context, count int) error {
+	if notifier, ok := t.factory.(types.ConnectionNotifier); ok {
+		return notifier.WaitConnect(ctx, count)
+	}
+	return nil
+}
+
+// Subscribe implements NotificationNotifier if the wrapped factory does
+func (t *Recorder) Subscribe(fn func(*Notification)) func() {
+	if notifier, ok := t.factory.(types.NotificationNotifier); ok {
+		return notifier.Subscribe(fn)
+	}
+	return func() {}
+}
+
+func (t *Recorder) record(request *Request, b []byte, err error) {
+
+	exchange := &RecordedExchange{}
+
+	if request.Method != nil {
+		exchange.Method = *request.Method
+	}
+
+	params, paramsErr := types.CanonicalParams(request.Params)
+	if paramsErr != nil {
+		zap.L().Error(fmt.Sprintf("not recording %s: %v", exchange.Method, paramsErr))
+		return
+	}
+
+	exchange.Params = params
+
+	if err != nil {
+		if e, ok := types.AsError(err); ok {
+			exchange.Error = e
+		} else {
+			exchange.TransportError = types.NewRecordedTransportError(err)
+		}
+	} else {
+		exchange.Response = append([]byte{}, b...)
+	}
+
+	if !t.RecordWriteOnly {
+		RedactWriteOnly(exchange)
+	}
+
+	if t.Redact != nil {
+		t.Redact(exchange)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.exchanges = append(t.exchanges, exchange)
+}
+
+type recordingHandle struct {
+	MessageHandler
+	recorder *Recorder
+}
+
+func (t *recordingHandle) Send(ctx context.Context, request *Request) ([]byte, error) {
+	b, err := t.MessageHandler.Send(ctx, request)
+	t.recorder.record(request, b, err)
+	return b, err
+}
@@ -0,0 +1,181 @@
+package recording
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
+	"github.com/jodydadescott/shelly-go-sdk/plus/simulator"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+var update = flag.Bool("update", false, "record the golden file from the simulator")
+
+const goldenFile = "testdata/plus1pm.json"
+
+// exercise sends the requests of the golden file and returns their results
+func exercise(ctx context.Context, client *plus.Client) ([]interface{}, error) {
+
+	info, err := client.Shelly().GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	server := "broker:1883"
+	user := "user"
+	pass := "secret"
+
+	restart, err := client.Mqtt().SetConfig(ctx, &types.MqttConfig{
+		Enable: true,
+		Server: &server,
+		User:   &user,
+		Pass:   &pass,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := client.Mqtt().GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	on := true
+	err = client.Switch().Set(ctx, 0, &on)
+	if err != nil {
+		return nil, err
+	}
+
+	_, notFound := client.Switch().GetConfig(ctx, 5)
+
+	return []interface{}{info, restart, config, types.NewRecordedTransportError(notFound)}, nil
+}
+
+// startSimulator returns a client that records the exchanges with a simulated device
+func startSimulator(t *testing.T) (*plus.Client, *Recorder) {
+
+	device := simulator.New(&simulator.Config{ID: "shellyplus1pm-a8032ab12345", MAC: "A8032AB12345"})
+	err := device.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { device.Close() })
+
+	factory, err := msghandlers.NewWS(&msghandlers.Config{Hostname: device.Hostname()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := NewRecorder(factory)
+	t.Cleanup(recorder.Close)
+
+	return plus.NewFromFactory(recorder), recorder
+}
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, recorder := startSimulator(t)
+
+	recorded, err := exercise(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "recording.json")
+	if *update {
+		file = goldenFile
+	}
+
+	err = recorder.Save(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "secret") || !strings.Contains(string(b), Redacted) {
+		t.Errorf("password was not redacted:\n%s", string(b))
+	}
+
+	replay, err := LoadReplay(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := exercise(ctx, plus.NewFromFactory(replay))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("replayed results differ from the recorded results\n%+v\n%+v", replayed, recorded)
+	}
+}
+
+func TestReplayGolden(t *testing.T) {
+
+	replay, err := LoadReplay(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := exercise(context.Background(), plus.NewFromFactory(replay))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := results[0].(*types.DeviceInfo)
+	if info.Model == nil || *info.Model != simulator.ModelPlus1PM.Model {
+		t.Errorf("model = %v, want %s", info.Model, simulator.ModelPlus1PM.Model)
+	}
+
+	config := results[2].(*types.MqttConfig)
+	if config.Server == nil || *config.Server != "broker:1883" {
+		t.Errorf("mqtt server = %v, want broker:1883", config.Server)
+	}
+
+	if transportErr := results[3].(*types.RecordedTransportError); !strings.Contains(transportErr.Message, "-105") {
+		t.Errorf("error = %s, want not found", transportErr.Message)
+	}
+}
+
+func TestRedactWriteOnly(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		params string
+		want   string
+	}{
+		{"none", `{"id":0,"on":true}`, `{"id":0,"on":true}`},
+		{"mqtt", `{"config":{"pass":"secret","user":"u"}}`, `{"config":{"pass":"` + Redacted + `","user":"u"}}`},
+		{"wifi", `{"config":{"ap":{"pass":"a"},"sta":{"pass":"b"},"sta1":{"pass":"c"}}}`,
+			`{"config":{"ap":{"pass":"` + Redacted + `"},"sta":{"pass":"` + Redacted + `"},"sta1":{"pass":"` + Redacted + `"}}}`},
+		{"auth", `{"ha1":"abc","realm":"r","user":"admin"}`, `{"ha1":"` + Redacted + `","realm":"r","user":"admin"}`},
+		{"null pass", `{"config":{"pass":null}}`, `{"config":{"pass":null}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			exchange := &RecordedExchange{Params: []byte(tt.params)}
+			RedactWriteOnly(exchange)
+
+			if string(exchange.Params) != tt.want {
+				t.Errorf("params = %s, want %s", string(exchange.Params), tt.want)
+			}
+		})
+	}
+}
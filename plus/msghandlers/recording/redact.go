@@ -0,0 +1,77 @@
+package recording
+
+import (
+	"encoding/json"
+)
+
+// Redacted replaces the values of write only fields in recorded params
+const Redacted = "*** redacted ***"
+
+// writeOnlyKeys are the keys of params that hold secrets the device never returns, such as pass in
+// MQTT.SetConfig and in the ap, sta and sta1 configs of WiFi.SetConfig and ha1 in Shelly.SetAuth
+var writeOnlyKeys = map[string]bool{
+	"pass": true,
+	"ha1":  true,
+}
+
+// RedactWriteOnly replaces the values of the write only fields in the params of exchange with Redacted
+func RedactWriteOnly(exchange *RecordedExchange) {
+	exchange.Params = redactParams(exchange.Params)
+}
+
+// redactParams returns params with the write only fields replaced. Params that are not valid JSON are
+// returned as is.
+func redactParams(params json.RawMessage) json.RawMessage {
+
+	if params == nil {
+		return nil
+	}
+
+	var v interface{}
+	err := json.Unmarshal(params, &v)
+	if err != nil {
+		return params
+	}
+
+	if !redact(v) {
+		return params
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return params
+	}
+
+	return b
+}
+
+// redact replaces the write only fields in v and returns true if any was found
+func redact(v interface{}) bool {
+
+	found := false
+
+	switch v := v.(type) {
+
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(string); ok && writeOnlyKeys[key] {
+				v[key] = Redacted
+				found = true
+				continue
+			}
+			if redact(value) {
+				found = true
+			}
+		}
+
+	case []interface{}:
+		for _, value := range v {
+			if redact(value) {
+				found = true
+			}
+		}
+
+	}
+
+	return found
+}
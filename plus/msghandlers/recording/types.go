@@ -0,0 +1,12 @@
+package recording
+
+import (
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type MessageHandlerFactory = types.MessageHandlerFactory
+type MessageHandler = types.MessageHandler
+type Request = types.Request
+type Notification = types.Notification
+type Recording = types.Recording
+type RecordedExchange = types.RecordedExchange
@@ -0,0 +1,58 @@
+package msghandlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type RetryPolicy = types.RetryPolicy
+
+// NewRetry returns a message handler that retries requests sent with handler according to policy
+func NewRetry(handler MessageHandler, policy *RetryPolicy) MessageHandler {
+	return NewInterceptorHandler(handler, RetryInterceptor(policy))
+}
+
+// RetryInterceptor returns an interceptor that retries requests according to policy. Every attempt
+// is sent as a new request with its own ID, so a late response to an earlier attempt is dropped.
+func RetryInterceptor(policy *RetryPolicy) Interceptor {
+
+	policy = policy.WithDefaults()
+
+	return func(ctx context.Context, request *Request, next SendFunc) ([]byte, error) {
+
+		method := ""
+		if request.Method != nil {
+			method = *request.Method
+		}
+
+		canRetry := policy.CanRetry(ctx, method)
+
+		for attempt := 1; ; attempt++ {
+
+			b, err := next(ctx, request)
+			if err == nil || !canRetry || attempt >= policy.MaxAttempts || !policy.IsRetryable(err) {
+				return b, err
+			}
+
+			backoff := policy.Backoff(attempt)
+
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+				// The retry could not complete before the caller gives up
+				return b, err
+			}
+
+			zap.L().Debug(fmt.Sprintf("%s attempt %d failed: %v; retrying in %v", method, attempt, err, backoff))
+
+			select {
+			case <-ctx.Done():
+				return b, err
+			case <-time.After(backoff):
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package msghandlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// failingSend returns a SendFunc that fails with err the first failures calls and records the time of
+// every call
+func failingSend(failures int, err error, calls *[]time.Time) SendFunc {
+	return func(ctx context.Context, request *Request) ([]byte, error) {
+		*calls = append(*calls, time.Now())
+		if len(*calls) <= failures {
+			return nil, err
+		}
+		return []byte(`{}`), nil
+	}
+}
+
+func TestRetryInterceptor(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		method     string
+		idempotent bool
+		policy     *RetryPolicy
+		err        error
+		calls      int
+		wantErr    error
+	}{
+		{
+			name:   "read retried",
+			method: "Switch.GetStatus",
+			err:    types.ErrTimeout,
+			calls:  3,
+		},
+		{
+			name:    "toggle not retried",
+			method:  "Switch.Toggle",
+			err:     types.ErrTimeout,
+			calls:   1,
+			wantErr: types.ErrTimeout,
+		},
+		{
+			name:    "set not retried",
+			method:  "Switch.Set",
+			err:     types.ErrNotConnected,
+			calls:   1,
+			wantErr: types.ErrNotConnected,
+		},
+		{
+			name:       "with idempotent",
+			method:     "Switch.Toggle",
+			idempotent: true,
+			err:        types.ErrTimeout,
+			calls:      3,
+		},
+		{
+			name:   "idempotent method",
+			method: "Switch.Set",
+			policy: &RetryPolicy{IdempotentMethods: []string{"Switch.Set"}},
+			err:    types.ErrTimeout,
+			calls:  3,
+		},
+		{
+			name:    "not retryable",
+			method:  "Switch.GetStatus",
+			err:     types.ErrAuthRequired,
+			calls:   1,
+			wantErr: types.ErrAuthRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			policy := tt.policy
+			if policy == nil {
+				policy = &RetryPolicy{}
+			}
+			policy.InitialBackoff = time.Millisecond
+
+			ctx := context.Background()
+			if tt.idempotent {
+				ctx = types.WithIdempotent(ctx)
+			}
+
+			var calls []time.Time
+
+			// The send fails twice, a retried request succeeds on the third attempt
+			send := Chain(failingSend(2, tt.err, &calls), RetryInterceptor(policy))
+
+			_, err := send(ctx, &Request{Method: &tt.method})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(calls) != tt.calls {
+				t.Errorf("calls = %d, want %d", len(calls), tt.calls)
+			}
+		})
+	}
+}
+
+func TestRetryInterceptorBackoff(t *testing.T) {
+
+	policy := &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 20 * time.Millisecond,
+		Multiplier:     4,
+		Jitter:         0.01,
+	}
+
+	var calls []time.Time
+
+	send := Chain(failingSend(3, types.ErrTimeout, &calls), RetryInterceptor(policy))
+
+	method := "Switch.GetStatus"
+	_, err := send(context.Background(), &Request{Method: &method})
+	if !errors.Is(err, types.ErrTimeout) {
+		t.Fatalf("error = %v, want %v", err, types.ErrTimeout)
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("calls = %d, want 3", len(calls))
+	}
+
+	first, second := calls[1].Sub(calls[0]), calls[2].Sub(calls[1])
+
+	if first < 19*time.Millisecond {
+		t.Errorf("first backoff = %v, want about 20ms", first)
+	}
+
+	if second < 79*time.Millisecond || second <= first {
+		t.Errorf("second backoff = %v, want about 80ms", second)
+	}
+}
+
+func TestRetryInterceptorDeadline(t *testing.T) {
+
+	policy := &RetryPolicy{InitialBackoff: time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var calls []time.Time
+
+	send := Chain(failingSend(3, types.ErrTimeout, &calls), RetryInterceptor(policy))
+
+	start := time.Now()
+
+	method := "Switch.GetStatus"
+	_, err := send(ctx, &Request{Method: &method})
+	if !errors.Is(err, types.ErrTimeout) {
+		t.Fatalf("error = %v, want %v", err, types.ErrTimeout)
+	}
+
+	// The backoff is longer than the time left before the deadline
+	if len(calls) != 1 {
+		t.Errorf("calls = %d, want 1", len(calls))
+	}
+
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("gave up after %v, want immediately", elapsed)
+	}
+}
@@ -3,6 +3,7 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"sync"
@@ -28,6 +29,7 @@ type Request = types.Request
 type AuthResponse = types.AuthResponse
 type AuthRequest = types.AuthRequest
 type Response = types.Response
+type Notification = types.Notification
 
 type Config interface {
 	GetHostname() string
@@ -50,6 +52,13 @@ type Client struct {
 	sendTimeout    time.Duration
 	debugEnabled   bool
 	authResponse   *AuthResponse
+	connected      bool
+	connectCount   int
+	connectSignal  chan struct{}
+	src            string
+	subscriberID   int
+	subscribers    map[int]func(*Notification)
+	metrics        types.Metrics
 }
 
 func New(config Config) (MessageHandlerFactory, error) {
@@ -63,6 +72,14 @@ func New(config Config) (MessageHandlerFactory, error) {
 		handleMap:      make(map[int]*Handle),
 		egressMessages: make(chan []byte, 50),
 		debugEnabled:   config.IsDebugEnabled(),
+		connectSignal:  make(chan struct{}),
+		src:            fmt.Sprintf("shelly-go-sdk-%d", time.Now().UnixNano()),
+		subscribers:    make(map[int]func(*Notification)),
+		metrics:        types.NopMetrics{},
+	}
+
+	if metricsConfig, ok := config.(types.MetricsConfig); ok && metricsConfig.GetMetrics() != nil {
+		t.metrics = metricsConfig.GetMetrics()
 	}
 
 	if t.hostname == "" {
@@ -105,6 +122,102 @@ func (t *Client) setAuthResponse(authResponse *AuthResponse) {
 	t.authResponse = authResponse
 }
 
+// IsConnected returns true if the websocket is currently connected to the device
+func (t *Client) IsConnected() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.connected
+}
+
+// ConnectCount returns the number of times a connection to the device has been established
+func (t *Client) ConnectCount() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.connectCount
+}
+
+// WaitConnect blocks until a connection has been established more than count times or the
+// context is done. Pass the value of ConnectCount taken before an expected disconnect (such
+// as a reboot) to wait for the reconnect.
+func (t *Client) WaitConnect(ctx context.Context, count int) error {
+
+	for {
+
+		t.mutex.RLock()
+		connected := t.connected && t.connectCount > count
+		signal := t.connectSignal
+		t.mutex.RUnlock()
+
+		if connected {
+			return nil
+		}
+
+		select {
+
+		case <-ctx.Done():
+			return fmt.Errorf("%w: timeout waiting for connection: %w", types.ErrNotConnected, ctx.Err())
+
+		case <-signal:
+			continue
+
+		}
+	}
+}
+
+// Subscribe registers fn to be called for every notification sent by the device and returns a
+// function that removes the subscription. fn is called from the receiving goroutine and must not block.
+func (t *Client) Subscribe(fn func(*Notification)) func() {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.subscriberID++
+	id := t.subscriberID
+	t.subscribers[id] = fn
+
+	return func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		delete(t.subscribers, id)
+	}
+}
+
+func (t *Client) notify(b []byte) {
+
+	notification := &Notification{}
+	err := json.Unmarshal(b, notification)
+	if err != nil {
+		zap.L().Error(fmt.Sprintf("notification error %v", err))
+		return
+	}
+
+	t.mutex.RLock()
+	var subscribers []func(*Notification)
+	for _, fn := range t.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	t.mutex.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(notification)
+	}
+}
+
+func (t *Client) setConnected(connected bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.connected = connected
+
+	if connected {
+		t.connectCount++
+		t.metrics.Connect(t.hostname, t.connectCount)
+	}
+
+	close(t.connectSignal)
+	t.connectSignal = make(chan struct{})
+}
+
 func (t *Client) Close() {
 	zap.L().Debug("(*Client) Close()")
 	t.cancel()
@@ -130,6 +243,12 @@ func (t *Client) run() {
 			return
 		}
 
+		if msg.ID == nil {
+			// Messages without an ID are notifications
+			t.notify(b)
+			return
+		}
+
 		t.mutex.RLock()
 		defer t.mutex.RUnlock()
 
@@ -139,23 +258,37 @@ func (t *Client) run() {
 			return
 		}
 
-		handle.receive <- &responseWrapper{
+		// A response that arrives after the sender gave up is dropped rather than blocking the
+		// ingress goroutine
+		select {
+		case handle.receive <- &responseWrapper{
 			response: msg,
 			rawBytes: b,
+		}:
+		default:
+			zap.L().Debug(fmt.Sprintf("dropping late response for handle ID %d", *msg.ID))
 		}
 	}
 
-	handleEgress := func(conn *gorilla.Conn, errs chan error) {
+	handleEgress := func(connCtx context.Context, conn *gorilla.Conn, errs chan error) chan struct{} {
+
+		done := make(chan struct{})
 
 		go func() {
+			defer close(done)
+
 			for {
 				select {
-				case <-ctx.Done():
-					conn.WriteMessage(gorilla.CloseMessage, gorilla.FormatCloseMessage(gorilla.CloseNormalClosure, ""))
+				case <-connCtx.Done():
+					if ctx.Err() != nil {
+						conn.WriteMessage(gorilla.CloseMessage, gorilla.FormatCloseMessage(gorilla.CloseNormalClosure, ""))
+					}
 					return
 
 				case b := <-t.egressMessages:
 
+					t.metrics.EgressQueueDepth(t.hostname, len(t.egressMessages))
+
 					if t.debugEnabled {
 						zap.L().Debug(fmt.Sprintf("TX->%s", string(b)))
 					}
@@ -169,6 +302,8 @@ func (t *Client) run() {
 			}
 
 		}()
+
+		return done
 	}
 
 	handleIngress := func(conn *gorilla.Conn, errs chan error) {
@@ -191,10 +326,23 @@ func (t *Client) run() {
 	}
 
 	handle := func(conn *gorilla.Conn) error {
+		// The errs channel is never closed; it is buffered so that both goroutines can always
+		// report without blocking after handle has returned.
 		errs := make(chan error, 2)
-		defer close(errs)
+
+		connCtx, connCancel := context.WithCancel(ctx)
+
 		handleIngress(conn, errs)
-		handleEgress(conn, errs)
+		egressDone := handleEgress(connCtx, conn, errs)
+
+		defer func() {
+			connCancel()
+			<-egressDone
+			conn.Close()
+			t.setConnected(false)
+		}()
+
+		t.setConnected(true)
 
 		select {
 		case <-ctx.Done():
@@ -218,8 +366,9 @@ func (t *Client) run() {
 		return handle(conn)
 	}
 
+	t.wg.Add(1)
+
 	go func() {
-		t.wg.Add(1)
 		defer t.wg.Done()
 
 		for {
@@ -254,32 +403,56 @@ func (t *Client) NewHandle() MessageHandler {
 
 	zap.L().Debug("(*Client) NewHandle()")
 
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
-
-	t.uniqID = t.uniqID + 1
-
-	handle := &Handle{
+	return &Handle{
 		Client:  t,
-		id:      t.uniqID,
-		receive: make(chan *responseWrapper),
+		receive: make(chan *responseWrapper, 1),
 		done:    make(chan struct{}),
 	}
-
-	t.handleMap[handle.id] = handle
-
-	return handle
 }
 
 func (t *Handle) Close() {
 
 	zap.L().Debug("(*Handle) Close()")
 
+	t.mutex.Lock()
+	delete(t.handleMap, t.id)
+	t.closed = true
+	t.mutex.Unlock()
+
 	close(t.done)
 	close(t.receive)
+}
+
+// register assigns a new request ID to the handle and routes responses with that ID to it. Every
+// request gets its own ID so that a late response to an earlier request, for example one that was
+// retried, is not taken as the response to the current one.
+func (t *Handle) register() (int, error) {
+
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
+
+	if t.closed {
+		return 0, types.ErrClosed
+	}
+
 	delete(t.handleMap, t.id)
+
+	t.uniqID = t.uniqID + 1
+	t.id = t.uniqID
+	t.handleMap[t.id] = t
+
+	return t.id, nil
+}
+
+// unregister stops routing responses with the request ID of the handle
+func (t *Handle) unregister(id int) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.id == id {
+		delete(t.handleMap, id)
+	}
 }
 
 type responseWrapper struct {
@@ -289,17 +462,54 @@ type responseWrapper struct {
 
 type Handle struct {
 	*Client
+	// id of the request in flight; guarded by the client mutex
 	id      int
+	closed  bool
 	receive chan *responseWrapper
 	done    chan struct{}
 }
 
+// enqueue queues the request for the egress goroutine
+func (t *Client) enqueue(b []byte) {
+	t.egressMessages <- b
+	t.metrics.EgressQueueDepth(t.hostname, len(t.egressMessages))
+}
+
 func (t *Handle) Send(ctx context.Context, request *Request) ([]byte, error) {
 
 	zap.L().Debug("(*Handle) Send(ctx, *Request)")
 
+	method := ""
+	if request.Method != nil {
+		method = *request.Method
+	}
+
+	start := time.Now()
+
+	b, err := t.send(ctx, request)
+
+	t.metrics.RPC(t.hostname, method, time.Since(start), err)
+
+	return b, err
+}
+
+func (t *Handle) send(ctx context.Context, request *Request) ([]byte, error) {
+
+	id, err := t.register()
+	if err != nil {
+		return nil, err
+	}
+	defer t.unregister(id)
+
 	request = request.Clone()
-	request.ID = &t.id
+	request.ID = &id
+	request.Src = &t.src
+
+	info := types.SendInfoFromContext(ctx)
+	if info != nil {
+		info.Hostname = t.hostname
+		info.RequestID = id
+	}
 
 	request.Auth = t.getAuthResponse()
 
@@ -314,23 +524,38 @@ func (t *Handle) Send(ctx context.Context, request *Request) ([]byte, error) {
 		return nil, err
 	}
 
-	t.egressMessages <- requestBytes
+	// Discard a late response to a previous request that timed out
+	select {
+	case <-t.receive:
+	default:
+	}
+
+	t.enqueue(requestBytes)
 
 	waitOnResponse := func() (*responseWrapper, error) {
 
 		select {
 
-		case response := <-t.receive:
+		case response, ok := <-t.receive:
+			if !ok {
+				return nil, types.ErrClosed
+			}
 			return response, nil
 
 		case <-t.done:
-			return nil, fmt.Errorf("channel closed by server")
+			return nil, types.ErrClosed
 
 		case <-ctx.Done():
-			return nil, fmt.Errorf("channel closed by client")
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w: %w", types.ErrTimeout, ctx.Err())
+			}
+			return nil, fmt.Errorf("%w by client: %w", types.ErrClosed, ctx.Err())
 
 		case <-time.After(t.sendTimeout):
-			return nil, fmt.Errorf("timeout waiting for response")
+			if !t.IsConnected() {
+				return nil, fmt.Errorf("%w; %w", types.ErrTimeout, types.ErrNotConnected)
+			}
+			return nil, types.ErrTimeout
 
 		}
 
@@ -348,18 +573,20 @@ func (t *Handle) Send(ctx context.Context, request *Request) ([]byte, error) {
 
 			zap.L().Debug("server responded with auth required")
 
+			t.metrics.AuthChallenge(t.hostname)
+
 			if t.username == "" {
-				return nil, fmt.Errorf("username is required")
+				return nil, fmt.Errorf("%w: username is required", types.ErrAuthRequired)
 			}
 
 			if t.password == "" {
-				return nil, fmt.Errorf("password is required")
+				return nil, fmt.Errorf("%w: password is required", types.ErrAuthRequired)
 			}
 
 			authRequest := &AuthRequest{}
 			err = json.Unmarshal([]byte(response.response.Error.Message), authRequest)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("%w: auth challenge: %w", types.ErrMalformedResponse, err)
 			}
 
 			authRequest.Username = t.username
@@ -372,6 +599,10 @@ func (t *Handle) Send(ctx context.Context, request *Request) ([]byte, error) {
 
 			t.setAuthResponse(authResponse)
 
+			if info != nil {
+				info.AuthRetry = true
+			}
+
 			request.Auth = authResponse
 
 			requestBytes, err := json.Marshal(request)
@@ -379,7 +610,7 @@ func (t *Handle) Send(ctx context.Context, request *Request) ([]byte, error) {
 				return nil, err
 			}
 
-			t.egressMessages <- requestBytes
+			t.enqueue(requestBytes)
 
 			response, err := waitOnResponse()
 
@@ -387,6 +618,11 @@ func (t *Handle) Send(ctx context.Context, request *Request) ([]byte, error) {
 				return nil, err
 			}
 
+			if response.response.Error != nil {
+				// A second 401 means the credentials were rejected
+				return nil, response.response.Error
+			}
+
 			return response.rawBytes, nil
 
 		}
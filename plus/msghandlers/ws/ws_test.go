@@ -0,0 +1,224 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type testConfig struct {
+	hostname    string
+	sendTimeout time.Duration
+}
+
+func (t *testConfig) GetHostname() string           { return t.hostname }
+func (t *testConfig) GetPassword() string           { return "" }
+func (t *testConfig) GetUsername() string           { return "" }
+func (t *testConfig) GetSendTimeout() time.Duration { return t.sendTimeout }
+func (t *testConfig) IsDebugEnabled() bool          { return false }
+
+// newSilentServer returns a websocket server that reads requests and never responds
+func newSilentServer(t *testing.T) *httptest.Server {
+
+	upgrader := gorilla.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, _, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+		}
+	}))
+
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newDelayServer returns a websocket server that answers every request with the number of the
+// request as result. The answer to request n is delayed by delays[n-1] if set.
+func newDelayServer(t *testing.T, delays ...time.Duration) *httptest.Server {
+
+	upgrader := gorilla.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var mutex sync.Mutex
+		count := 0
+
+		for {
+			request := &Request{}
+			err := conn.ReadJSON(request)
+			if err != nil {
+				return
+			}
+
+			count++
+
+			var delay time.Duration
+			if count <= len(delays) {
+				delay = delays[count-1]
+			}
+
+			response := map[string]interface{}{"id": *request.ID, "result": count}
+
+			time.AfterFunc(delay, func() {
+				mutex.Lock()
+				defer mutex.Unlock()
+				conn.WriteJSON(response)
+			})
+		}
+	}))
+
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSendContextDone(t *testing.T) {
+
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+		err  error
+		want error
+	}{
+		{
+			name: "deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			err:  types.ErrTimeout,
+			want: context.DeadlineExceeded,
+		},
+		{
+			name: "cancelled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			err:  types.ErrClosed,
+			want: context.Canceled,
+		},
+		{
+			name: "send timeout",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			err: types.ErrTimeout,
+		},
+	}
+
+	server := newSilentServer(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			factory, err := New(&testConfig{
+				hostname:    strings.TrimPrefix(server.URL, "http://"),
+				sendTimeout: 200 * time.Millisecond,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer factory.Close()
+
+			handle := factory.NewHandle()
+			defer handle.Close()
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			method := "Shelly.GetStatus"
+			_, err = handle.Send(ctx, &Request{Method: &method})
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendHandleClosed(t *testing.T) {
+
+	server := newSilentServer(t)
+
+	factory, err := New(&testConfig{
+		hostname:    strings.TrimPrefix(server.URL, "http://"),
+		sendTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer factory.Close()
+
+	handle := factory.NewHandle()
+
+	time.AfterFunc(50*time.Millisecond, handle.Close)
+
+	method := "Shelly.GetStatus"
+	_, err = handle.Send(context.Background(), &Request{Method: &method})
+
+	// The handle was closed by the caller, not the server
+	if err != types.ErrClosed {
+		t.Fatalf("error = %v, want %v", err, types.ErrClosed)
+	}
+}
+
+func TestSendLateResponse(t *testing.T) {
+
+	server := newDelayServer(t, 300*time.Millisecond, 150*time.Millisecond)
+
+	factory, err := New(&testConfig{
+		hostname:    strings.TrimPrefix(server.URL, "http://"),
+		sendTimeout: 200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer factory.Close()
+
+	handle := factory.NewHandle()
+	defer handle.Close()
+
+	method := "Shelly.GetStatus"
+
+	_, err = handle.Send(context.Background(), &Request{Method: &method})
+	if !errors.Is(err, types.ErrTimeout) {
+		t.Fatalf("error = %v, want %v", err, types.ErrTimeout)
+	}
+
+	// The response to the first request arrives while the second one waits; the second request has
+	// its own ID so the late response is dropped
+	b, err := handle.Send(context.Background(), &Request{Method: &method})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), `"result":2`) {
+		t.Errorf("response = %s, want the response to the second request", b)
+	}
+}
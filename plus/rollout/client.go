@@ -0,0 +1,576 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Config config for the rollout client
+type Config struct {
+	// Password of the devices. All devices in a fleet are expected to share the same password
+	Password string
+	// DebugEnabled enables debug logging of the device clients
+	DebugEnabled bool
+	// SendTimeout timeout of a single RPC request. Optional
+	SendTimeout time.Duration
+	// Connect returns a client for the device. Optional; by default a websocket client is created with the
+	// hostname of the device and the password
+	Connect func(device *Device) (*plus.Client, error)
+}
+
+// Client rolls out firmware updates across a fleet of devices in waves
+type Client struct {
+	config *Config
+}
+
+// New returns new instance of client
+func New(config *Config) *Client {
+
+	if config == nil {
+		config = &Config{}
+	}
+
+	return &Client{
+		config: config,
+	}
+}
+
+// Plan returns the initial state of a rollout without updating any device. The first Canary devices
+// form the canary wave; the remaining devices are split into waves of BatchSize in the given order.
+func (t *Client) Plan(devices []*Device, options *Options) (*State, error) {
+
+	options = withDefaults(options)
+
+	err := validateDevices(devices)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{
+		Version: types.RolloutStateVersion,
+		Stage:   options.Stage,
+		Devices: map[string]*DeviceState{},
+	}
+
+	canary := options.Canary
+	if canary > len(devices) {
+		canary = len(devices)
+	}
+
+	if canary > 0 {
+		addWave(state, devices[:canary], true)
+	}
+
+	addWaves(state, devices[canary:], options.BatchSize)
+
+	return state, nil
+}
+
+// Run rolls out the firmware update. If the state file exists the rollout is resumed; devices that
+// have been processed are not updated again and devices that are not in the state are added as new
+// waves. Each device is updated with UpdateAndWait and must pass a health gate: the device is back
+// online, reports the new version and has no switch errors that it did not have before the update.
+// The rollout halts if any canary fails or the number of failed devices exceeds MaxFailures. A halted
+// rollout is only resumed with RetryFailed or Force. The state is returned together with an error if
+// the rollout halted or was cancelled.
+func (t *Client) Run(ctx context.Context, devices []*Device, options *Options) (*State, error) {
+
+	options = withDefaults(options)
+
+	state, err := t.load(devices, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Halted && !options.RetryFailed && !options.Force {
+		return state, fmt.Errorf("%w: %s; retry the failed devices or force to resume", types.ErrRolloutHalted, state.HaltReason)
+	}
+
+	state.Halted = false
+	state.HaltReason = ""
+
+	forced := map[string]bool{}
+
+	for _, v := range state.Devices {
+
+		if v.Status != types.RolloutDeviceStatusFailed {
+			continue
+		}
+
+		if options.RetryFailed {
+			v.Status = types.RolloutDeviceStatusPending
+			v.Reason = ""
+			v.Finished = nil
+			continue
+		}
+
+		if options.Force {
+			forced[v.Name] = true
+		}
+	}
+
+	if state.Started.IsZero() {
+		state.Started = time.Now()
+	}
+
+	run := &run{
+		client:  t,
+		options: options,
+		state:   state,
+		forced:  forced,
+	}
+
+	err = run.save()
+	if err != nil {
+		return state, err
+	}
+
+	for i, wave := range state.Waves {
+
+		err = run.wave(ctx, i, wave)
+		if err != nil {
+			return state, err
+		}
+	}
+
+	zap.L().Info(fmt.Sprintf("rollout complete; updated %d, skipped %d, failed %d",
+		state.Count(types.RolloutDeviceStatusUpdated), state.Count(types.RolloutDeviceStatusSkipped), state.Count(types.RolloutDeviceStatusFailed)))
+
+	return state, nil
+}
+
+// load returns the state from the state file with new devices appended or a new plan
+func (t *Client) load(devices []*Device, options *Options) (*State, error) {
+
+	if options.StateFile == "" {
+		return t.Plan(devices, options)
+	}
+
+	state, err := types.LoadRolloutState(options.StateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if state == nil {
+		return t.Plan(devices, options)
+	}
+
+	if state.Stage != options.Stage {
+		return nil, fmt.Errorf("%s: stage %s does not match the requested stage %s", options.StateFile, state.Stage, options.Stage)
+	}
+
+	err = validateDevices(devices)
+	if err != nil {
+		return nil, err
+	}
+
+	var added []*Device
+	for _, v := range devices {
+		if _, ok := state.Devices[v.Name]; !ok {
+			added = append(added, v)
+		}
+	}
+
+	if len(added) > 0 {
+		zap.L().Info(fmt.Sprintf("resuming rollout; adding %d new devices", len(added)))
+		addWaves(state, added, options.BatchSize)
+	}
+
+	return state, nil
+}
+
+func (t *Client) connect(device *Device) (*plus.Client, error) {
+
+	if t.config.Connect != nil {
+		return t.config.Connect(device)
+	}
+
+	return plus.New(&msghandlers.Config{
+		Hostname:     device.Hostname,
+		Password:     t.config.Password,
+		DebugEnabled: t.config.DebugEnabled,
+		SendTimeout:  t.config.SendTimeout,
+	})
+}
+
+// update updates a single device and checks the health gate. The returned state is a copy.
+func (t *Client) update(ctx context.Context, device *DeviceState, options *Options) *DeviceState {
+
+	result := *device
+
+	fail := func(format string, a ...interface{}) *DeviceState {
+		result.Status = types.RolloutDeviceStatusFailed
+		result.Reason = fmt.Sprintf(format, a...)
+		return &result
+	}
+
+	client, err := t.connect(&Device{Name: device.Name, Hostname: device.Hostname})
+	if err != nil {
+		return fail("connect: %v", err)
+	}
+
+	defer client.Close()
+
+	status, err := client.Shelly().GetStatus(ctx)
+	if err != nil {
+		return fail("status before update: %v", err)
+	}
+
+	baseline := switchErrors(status)
+
+	updateResult := client.Shelly().UpdateAndWaitWithOptions(ctx, &ShellyUpdateOptions{
+		Stage:   options.Stage,
+		Timeout: options.UpdateTimeout,
+	})
+
+	result.FromVersion = updateResult.FromVersion
+	result.ToVersion = updateResult.ToVersion
+
+	switch updateResult.Status {
+
+	case types.UpdateStatusSkipped:
+		result.Status = types.RolloutDeviceStatusSkipped
+		result.Reason = updateResult.Reason
+		return &result
+
+	case types.UpdateStatusFailed:
+		return fail("%s", updateResult.Reason)
+
+	}
+
+	if options.HealthDelay > 0 {
+		select {
+		case <-ctx.Done():
+			return fail("health gate: %v", ctx.Err())
+		case <-time.After(options.HealthDelay):
+		}
+	}
+
+	err = healthGate(ctx, client, updateResult, baseline)
+	if err != nil {
+		return fail("health gate: %v", err)
+	}
+
+	result.Status = types.RolloutDeviceStatusUpdated
+	result.Reason = ""
+	return &result
+}
+
+// healthGate returns an error if the device is not online, does not report the new version or reports
+// switch errors that were not present before the update
+func healthGate(ctx context.Context, client *plus.Client, updateResult *UpdateResult, baseline map[string]bool) error {
+
+	info, err := client.Shelly().GetDeviceInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("device is not online: %w", err)
+	}
+
+	if info.Version == nil || updateResult.ToVersion == nil || *info.Version != *updateResult.ToVersion {
+		return fmt.Errorf("device reports version %s; expected %s", stringValue(info.Version), stringValue(updateResult.ToVersion))
+	}
+
+	status, err := client.Shelly().GetStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("device is not online: %w", err)
+	}
+
+	var errors *multierror.Error
+
+	for _, v := range sortedKeys(switchErrors(status)) {
+		if !baseline[v] {
+			errors = multierror.Append(errors, fmt.Errorf("new error %s", v))
+		}
+	}
+
+	return errors.ErrorOrNil()
+}
+
+// run holds the state of a single call to Run
+type run struct {
+	mutex   sync.Mutex
+	client  *Client
+	options *Options
+	state   *State
+	// forced devices that failed before the rollout was forced to resume; they do not halt the rollout
+	forced map[string]bool
+}
+
+// wave updates the pending devices of the wave and returns an error if the rollout has to halt. The
+// halt is checked before the wave starts so that failures saved by a previous run are not skipped.
+func (t *run) wave(ctx context.Context, index int, wave *Wave) error {
+
+	if reason := t.haltReason(wave); reason != "" {
+		return t.halt(reason)
+	}
+
+	var pending []*DeviceState
+	for _, name := range wave.Devices {
+		if device := t.state.Devices[name]; device.Status == types.RolloutDeviceStatusPending {
+			pending = append(pending, device)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	zap.L().Info(fmt.Sprintf("starting wave %d with %d devices (canary %t)", index, len(pending), wave.Canary))
+
+	var wg sync.WaitGroup
+	var saveErr error
+	sem := make(chan struct{}, t.options.Concurrency)
+
+	for _, device := range pending {
+
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+
+		if ctx.Err() != nil || t.halted(wave) {
+			break
+		}
+
+		wg.Add(1)
+
+		go func(device *DeviceState) {
+
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			zap.L().Debug(fmt.Sprintf("updating %s (%s)", device.Name, device.Hostname))
+
+			result := t.client.update(ctx, device, t.options)
+
+			if ctx.Err() != nil {
+				// The device is left pending so that it is retried when the rollout is resumed
+				return
+			}
+
+			now := time.Now()
+			result.Finished = &now
+
+			if result.Status == types.RolloutDeviceStatusFailed {
+				zap.L().Error(fmt.Sprintf("update of %s failed: %s", result.Name, result.Reason))
+			} else {
+				zap.L().Info(fmt.Sprintf("%s %s", result.Name, result.Status))
+			}
+
+			t.mutex.Lock()
+			t.state.Devices[result.Name] = result
+			err := t.save()
+			if err != nil && saveErr == nil {
+				saveErr = err
+			}
+			t.mutex.Unlock()
+
+			if t.options.Progress != nil {
+				t.options.Progress(result)
+			}
+
+		}(device)
+	}
+
+	wg.Wait()
+
+	if saveErr != nil {
+		return saveErr
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if reason := t.haltReason(wave); reason != "" {
+		return t.halt(reason)
+	}
+
+	return nil
+}
+
+// halt saves the state as halted and returns the error
+func (t *run) halt(reason string) error {
+
+	t.state.Halted = true
+	t.state.HaltReason = reason
+	zap.L().Error(fmt.Sprintf("rollout halted: %s", reason))
+
+	err := t.save()
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", types.ErrRolloutHalted, reason)
+}
+
+func (t *run) halted(wave *Wave) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.haltReason(wave) != ""
+}
+
+// haltReason returns why the rollout has to halt or an empty string
+func (t *run) haltReason(wave *Wave) string {
+
+	if wave.Canary {
+		for _, name := range wave.Devices {
+			if device := t.state.Devices[name]; device.Status == types.RolloutDeviceStatusFailed && !t.forced[name] {
+				return fmt.Sprintf("canary %s failed: %s", device.Name, device.Reason)
+			}
+		}
+	}
+
+	failed := 0
+	for _, device := range t.state.Devices {
+		if device.Status == types.RolloutDeviceStatusFailed && !t.forced[device.Name] {
+			failed++
+		}
+	}
+
+	if failed > t.options.MaxFailures {
+		return fmt.Sprintf("%d devices failed; threshold is %d", failed, t.options.MaxFailures)
+	}
+
+	return ""
+}
+
+// save writes the state to the state file if one is configured. The caller must hold the mutex or be
+// the only goroutine accessing the state.
+func (t *run) save() error {
+
+	t.state.Updated = time.Now()
+
+	if t.options.StateFile == "" {
+		return nil
+	}
+
+	return t.state.Save(t.options.StateFile)
+}
+
+func withDefaults(options *Options) *Options {
+
+	result := &Options{}
+	if options != nil {
+		*result = *options
+	}
+
+	if result.Stage == "" {
+		result.Stage = defaultStage
+	}
+
+	if result.BatchSize < 1 {
+		result.BatchSize = defaultBatchSize
+	}
+
+	if result.Concurrency < 1 {
+		result.Concurrency = defaultConcurrency
+	}
+
+	return result
+}
+
+func validateDevices(devices []*Device) error {
+
+	var errors *multierror.Error
+
+	names := map[string]bool{}
+
+	for i, v := range devices {
+
+		if v == nil {
+			errors = multierror.Append(errors, fmt.Errorf("device %d is nil", i))
+			continue
+		}
+
+		if v.Name == "" {
+			errors = multierror.Append(errors, fmt.Errorf("device %d :: name is required", i))
+		} else if names[v.Name] {
+			errors = multierror.Append(errors, fmt.Errorf("device %s :: name is not unique", v.Name))
+		}
+
+		if v.Hostname == "" {
+			errors = multierror.Append(errors, fmt.Errorf("device %s :: hostname is required", v.Name))
+		}
+
+		names[v.Name] = true
+	}
+
+	return errors.ErrorOrNil()
+}
+
+func addWaves(state *State, devices []*Device, batchSize int) {
+
+	for len(devices) > 0 {
+
+		size := batchSize
+		if size > len(devices) {
+			size = len(devices)
+		}
+
+		addWave(state, devices[:size], false)
+		devices = devices[size:]
+	}
+}
+
+func addWave(state *State, devices []*Device, canary bool) {
+
+	wave := &Wave{
+		Canary: canary,
+	}
+
+	for _, v := range devices {
+		wave.Devices = append(wave.Devices, v.Name)
+		state.Devices[v.Name] = &DeviceState{
+			Name:     v.Name,
+			Hostname: v.Hostname,
+			Wave:     len(state.Waves),
+			Status:   types.RolloutDeviceStatusPending,
+		}
+	}
+
+	state.Waves = append(state.Waves, wave)
+}
+
+// switchErrors returns the errors reported by the switches keyed by switch:<id>:<error>
+func switchErrors(status *ShellyStatus) map[string]bool {
+
+	result := map[string]bool{}
+
+	for _, v := range status.Switch {
+		for _, e := range v.Errors {
+			result[fmt.Sprintf("switch:%d:%s", v.ID, e)] = true
+		}
+	}
+
+	return result
+}
+
+func sortedKeys(m map[string]bool) []string {
+
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return "<none>"
+	}
+	return *s
+}
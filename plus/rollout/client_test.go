@@ -0,0 +1,122 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+func TestRunResumeAfterHalt(t *testing.T) {
+
+	devices := []*Device{
+		{Name: "canary", Hostname: "canary.local"},
+		{Name: "a", Hostname: "a.local"},
+		{Name: "b", Hostname: "b.local"},
+	}
+
+	tests := []struct {
+		name string
+		// halted if true the saved state is marked as halted
+		halted    bool
+		options   *Options
+		connected []string
+		halt      bool
+	}{
+		{
+			name:   "halted",
+			halted: true,
+			halt:   true,
+		},
+		{
+			name: "halt not saved",
+			halt: true,
+		},
+		{
+			name:      "retry failed",
+			halted:    true,
+			options:   &Options{RetryFailed: true},
+			connected: []string{"canary"},
+			halt:      true,
+		},
+		{
+			name:      "force",
+			halted:    true,
+			options:   &Options{Force: true, MaxFailures: 5},
+			connected: []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			stateFile := filepath.Join(t.TempDir(), "state.json")
+
+			options := &Options{}
+			if tt.options != nil {
+				*options = *tt.options
+			}
+			options.Canary = 1
+			options.StateFile = stateFile
+
+			var mutex sync.Mutex
+			var connected []string
+
+			client := New(&Config{
+				Connect: func(device *Device) (*plus.Client, error) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					connected = append(connected, device.Name)
+					return nil, errors.New("unreachable")
+				},
+			})
+
+			state, err := client.Plan(devices, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			state.Devices["canary"].Status = types.RolloutDeviceStatusFailed
+			state.Devices["canary"].Reason = "health gate"
+			state.Halted = tt.halted
+			state.HaltReason = "canary canary failed: health gate"
+
+			err = state.Save(stateFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			state, err = client.Run(context.Background(), devices, options)
+
+			if errors.Is(err, types.ErrRolloutHalted) != tt.halt {
+				t.Fatalf("error = %v, want halt %v", err, tt.halt)
+			}
+
+			if !tt.halt && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if strings.Join(connected, ",") != strings.Join(tt.connected, ",") {
+				t.Errorf("connected = %v, want %v", connected, tt.connected)
+			}
+
+			if state.Halted != tt.halt {
+				t.Errorf("state halted = %v, want %v", state.Halted, tt.halt)
+			}
+
+			saved, err := types.LoadRolloutState(stateFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if saved.Halted != tt.halt {
+				t.Errorf("saved state halted = %v, want %v", saved.Halted, tt.halt)
+			}
+		})
+	}
+}
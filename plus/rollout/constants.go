@@ -0,0 +1,11 @@
+package rollout
+
+const (
+	Component = "Rollout"
+
+	defaultStage = "stable"
+
+	defaultBatchSize = 10
+
+	defaultConcurrency = 1
+)
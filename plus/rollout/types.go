@@ -0,0 +1,15 @@
+package rollout
+
+import (
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type Device = types.RolloutDevice
+type Options = types.RolloutOptions
+type State = types.RolloutState
+type Wave = types.RolloutWave
+type DeviceState = types.RolloutDeviceState
+type DeviceStatus = types.RolloutDeviceStatus
+type UpdateResult = types.UpdateResult
+type ShellyUpdateOptions = types.ShellyUpdateOptions
+type ShellyStatus = types.ShellyStatus
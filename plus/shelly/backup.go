@@ -0,0 +1,557 @@
+package shelly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/copier"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Backup reads the config, scripts, schedules, webhooks, KVS and device info from the device and
+// returns them as an archive. Passwords can not be read from the device and are replaced with
+// placeholders. Services the device does not support are skipped and recorded as warnings.
+func (t *Client) Backup(ctx context.Context) (*Archive, error) {
+
+	deviceInfo, err := t.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := t.GetConfig(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Auth = &ShellyAuthConfig{
+		Enable: deviceInfo.AuthEnabled,
+	}
+
+	config.Markup()
+
+	// TLS material can not be read; only flags are kept
+	config.UserCA = nil
+	config.TLSClientCert = nil
+	config.TLSClientKey = nil
+
+	archive := &Archive{
+		Version:    types.ArchiveVersion,
+		Created:    time.Now().UTC(),
+		DeviceInfo: deviceInfo,
+		Config:     config,
+	}
+
+	skip := func(service string, err error) error {
+		if types.IsNotImplemented(err) {
+			archive.Warnings = append(archive.Warnings, fmt.Sprintf("%s is not supported by the device", service))
+			return nil
+		}
+		return err
+	}
+
+	archive.Scripts, err = t.backupScripts(ctx)
+	if err := skip("Script", err); err != nil {
+		return nil, err
+	}
+
+	archive.Schedules, err = t.backupSchedules(ctx)
+	if err := skip("Schedule", err); err != nil {
+		return nil, err
+	}
+
+	archive.Webhooks, err = t.backupWebhooks(ctx)
+	if err := skip("Webhook", err); err != nil {
+		return nil, err
+	}
+
+	archive.KVS, err = t.backupKVS(ctx)
+	if err := skip("KVS", err); err != nil {
+		return nil, err
+	}
+
+	archive.TLS = archiveTLS(archive)
+
+	return archive, nil
+}
+
+// Restore replays the archive onto the device, which must be of the same model as the source device
+// unless options.AllowModelMismatch is set. Identity fields (MAC, firmware id, MQTT client id and an
+// MQTT topic prefix equal to the source device id) are never restored and the device name is only
+// restored if options.IncludeName is set. Auth is set last so that the other items can be restored
+// with the current credentials. An error is returned if the restore could not be started; the result
+// of each item is recorded in the report.
+func (t *Client) Restore(ctx context.Context, archive *Archive, options *ArchiveRestoreOptions) (*ArchiveRestoreReport, error) {
+
+	if archive == nil {
+		return nil, fmt.Errorf("archive is required")
+	}
+
+	if archive.Version < 1 || archive.Version > types.ArchiveVersion {
+		return nil, fmt.Errorf("archive version %d is not supported; supported version is %d", archive.Version, types.ArchiveVersion)
+	}
+
+	if options == nil {
+		options = &ArchiveRestoreOptions{}
+	}
+
+	deviceInfo, err := t.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.AllowModelMismatch && archive.DeviceInfo != nil && archive.DeviceInfo.Model != nil {
+		if deviceInfo.Model == nil || *deviceInfo.Model != *archive.DeviceInfo.Model {
+			return nil, fmt.Errorf("archive was taken from model %s; device is model %s", *archive.DeviceInfo.Model, stringValue(deviceInfo.Model))
+		}
+	}
+
+	report := &ArchiveRestoreReport{}
+
+	var auth *ShellyAuthConfig
+
+	if archive.Config != nil {
+
+		config := &ShellyConfig{}
+		err = copier.CopyWithOption(config, archive.Config, copier.Option{DeepCopy: true})
+		if err != nil {
+			return nil, err
+		}
+
+		if options.Secrets != nil {
+			err = types.ResolveSecrets(config, options.Secrets)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		auth = config.Auth
+		config.Auth = nil
+
+		t.restoreIdentity(config, archive, options)
+
+		config.UserCA = options.UserCA
+		config.TLSClientCert = options.TLSClientCert
+		config.TLSClientKey = options.TLSClientKey
+
+		if archive.TLS != nil {
+			if archive.TLS.UserCA && options.UserCA == nil {
+				report.Warnings = append(report.Warnings, "the config uses user_ca.pem but no UserCA was provided")
+			}
+			if archive.TLS.TLSClientCert && (options.TLSClientCert == nil || options.TLSClientKey == nil) {
+				report.Warnings = append(report.Warnings, "the config uses a TLS client certificate but no TLSClientCert and TLSClientKey were provided")
+			}
+		}
+
+		report.Config = t.SetConfigWithOptions(ctx, config, options.SetConfigOptions)
+	}
+
+	if options.ReplaceExisting {
+		report.Warnings = append(report.Warnings, t.deleteExisting(ctx)...)
+	}
+
+	for _, v := range archive.KVS {
+		report.KVS = append(report.KVS, &ArchiveItemReport{
+			Item:  "kvs:" + v.Key,
+			Error: t.call(ctx, "KVS.Set", map[string]interface{}{"key": v.Key, "value": v.Value}, nil),
+		})
+	}
+
+	// Scripts get new IDs on the device; schedules that refer to them are updated to the new IDs
+	scriptIDs := map[int]int{}
+
+	for _, v := range archive.Scripts {
+		id, err := t.restoreScript(ctx, v)
+		if err == nil {
+			scriptIDs[v.ID] = id
+		}
+		report.Scripts = append(report.Scripts, &ArchiveItemReport{
+			Item:  fmt.Sprintf("script:%d", v.ID),
+			Error: err,
+		})
+	}
+
+	for i, v := range archive.Schedules {
+
+		schedule := v.Clone()
+		schedule.ID = nil
+
+		for _, call := range schedule.Calls {
+			remapScriptID(call, scriptIDs)
+		}
+
+		report.Schedules = append(report.Schedules, &ArchiveItemReport{
+			Item:  fmt.Sprintf("schedule:%d", intValue(v.ID, i)),
+			Error: t.call(ctx, "Schedule.Create", schedule, nil),
+		})
+	}
+
+	for i, v := range archive.Webhooks {
+		report.Webhooks = append(report.Webhooks, &ArchiveItemReport{
+			Item:  fmt.Sprintf("webhook:%d", intValue(v.ID, i)),
+			Error: t.restoreWebhook(ctx, v),
+		})
+	}
+
+	if auth != nil && auth.Enable {
+		report.Auth = &ArchiveItemReport{
+			Item:  "auth",
+			Error: t.setAuth(ctx, auth),
+		}
+	}
+
+	return report, nil
+}
+
+// restoreIdentity removes the fields that identify the source device from config
+func (t *Client) restoreIdentity(config *ShellyConfig, archive *Archive, options *ArchiveRestoreOptions) {
+
+	if config.System != nil && config.System.Device != nil {
+		config.System.Device.MAC = nil
+		config.System.Device.FwID = nil
+		if !options.IncludeName {
+			config.System.Device.Name = nil
+		}
+	}
+
+	if config.Mqtt != nil {
+		config.Mqtt.ClientID = nil
+		if config.Mqtt.TopicPrefix != nil && archive.DeviceInfo != nil && archive.DeviceInfo.ID != nil {
+			if *config.Mqtt.TopicPrefix == *archive.DeviceInfo.ID {
+				config.Mqtt.TopicPrefix = nil
+			}
+		}
+	}
+}
+
+// deleteExisting deletes scripts, schedules and webhooks on the device and returns warnings for those
+// that could not be deleted
+func (t *Client) deleteExisting(ctx context.Context) []string {
+
+	var warnings []string
+
+	scripts, err := t.listScripts(ctx)
+	if err != nil && !types.IsNotImplemented(err) {
+		warnings = append(warnings, fmt.Sprintf("unable to list existing scripts: %v", err))
+	}
+
+	for _, v := range scripts {
+
+		if v.Running {
+			// Deleting a running script fails
+			t.call(ctx, "Script.Stop", map[string]interface{}{"id": v.ID}, nil)
+		}
+
+		err = t.call(ctx, "Script.Delete", map[string]interface{}{"id": v.ID}, nil)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to delete existing script:%d: %v", v.ID, err))
+		}
+	}
+
+	for _, method := range []string{"Schedule.DeleteAll", "Webhook.DeleteAll"} {
+		err = t.call(ctx, method, nil, nil)
+		if err != nil && !types.IsNotImplemented(err) {
+			warnings = append(warnings, fmt.Sprintf("%s failed: %v", method, err))
+		}
+	}
+
+	return warnings
+}
+
+func (t *Client) restoreScript(ctx context.Context, script *Script) (int, error) {
+
+	name := ""
+	if script.Name != nil {
+		name = *script.Name
+	}
+
+	created := &struct {
+		ID int `json:"id"`
+	}{}
+
+	err := t.call(ctx, "Script.Create", map[string]interface{}{"name": name}, created)
+	if err != nil {
+		return 0, err
+	}
+
+	chunks := splitRunesByWidth(script.Code, maxRPCChunkSize)
+
+	for i, chunk := range chunks {
+		err = t.call(ctx, "Script.PutCode", map[string]interface{}{
+			"id":     created.ID,
+			"code":   chunk,
+			"append": i > 0,
+		}, nil)
+		if err != nil {
+			return created.ID, err
+		}
+	}
+
+	err = t.call(ctx, "Script.SetConfig", map[string]interface{}{
+		"id":     created.ID,
+		"config": map[string]interface{}{"enable": script.Enable},
+	}, nil)
+	if err != nil {
+		return created.ID, err
+	}
+
+	if script.Running {
+		err = t.call(ctx, "Script.Start", map[string]interface{}{"id": created.ID}, nil)
+		if err != nil {
+			return created.ID, err
+		}
+	}
+
+	return created.ID, nil
+}
+
+func (t *Client) restoreWebhook(ctx context.Context, webhook *Webhook) error {
+
+	b, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{}
+	err = json.Unmarshal(b, &params)
+	if err != nil {
+		return err
+	}
+
+	// The device assigns a new ID
+	delete(params, "id")
+
+	return t.call(ctx, "Webhook.Create", params, nil)
+}
+
+func (t *Client) listScripts(ctx context.Context) ([]*Script, error) {
+
+	result := &struct {
+		Scripts []*Script `json:"scripts"`
+	}{}
+
+	err := t.call(ctx, "Script.List", nil, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Scripts, nil
+}
+
+func (t *Client) backupScripts(ctx context.Context) ([]*Script, error) {
+
+	scripts, err := t.listScripts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range scripts {
+
+		var code strings.Builder
+
+		for {
+
+			result := &struct {
+				Data string `json:"data"`
+				Left int    `json:"left"`
+			}{}
+
+			err = t.call(ctx, "Script.GetCode", map[string]interface{}{"id": v.ID, "offset": code.Len()}, result)
+			if err != nil {
+				return nil, fmt.Errorf("script:%d: %w", v.ID, err)
+			}
+
+			code.WriteString(result.Data)
+
+			if result.Left <= 0 || result.Data == "" {
+				break
+			}
+		}
+
+		v.Code = code.String()
+	}
+
+	return scripts, nil
+}
+
+func (t *Client) backupSchedules(ctx context.Context) ([]*Schedule, error) {
+
+	result := &struct {
+		Jobs []*Schedule `json:"jobs"`
+	}{}
+
+	err := t.call(ctx, "Schedule.List", nil, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Jobs, nil
+}
+
+func (t *Client) backupWebhooks(ctx context.Context) ([]*Webhook, error) {
+
+	result := &struct {
+		Hooks []*Webhook `json:"hooks"`
+	}{}
+
+	err := t.call(ctx, "Webhook.List", nil, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Hooks, nil
+}
+
+// backupKVS reads all KVS items. Older firmware returns the items as an object keyed by key, newer
+// firmware returns a paginated list.
+func (t *Client) backupKVS(ctx context.Context) ([]*KVSItem, error) {
+
+	var items []*KVSItem
+
+	for {
+
+		result := &struct {
+			Items json.RawMessage `json:"items"`
+			Total *int            `json:"total"`
+		}{}
+
+		err := t.call(ctx, "KVS.GetMany", map[string]interface{}{"match": "*", "offset": len(items)}, result)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(result.Items) == 0 || result.Items[0] != '[' {
+
+			byKey := map[string]*KVSItem{}
+			if len(result.Items) > 0 {
+				err = json.Unmarshal(result.Items, &byKey)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+				}
+			}
+
+			for k, v := range byKey {
+				items = append(items, &KVSItem{Key: k, Value: v.Value})
+			}
+
+			return items, nil
+		}
+
+		var page []*KVSItem
+		err = json.Unmarshal(result.Items, &page)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+		}
+
+		items = append(items, page...)
+
+		if len(page) == 0 || result.Total == nil || len(items) >= *result.Total {
+			return items, nil
+		}
+	}
+}
+
+// call sends the RPC request and decodes the result into result unless it is nil
+func (t *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+
+	respBytes, err := t.getMessageHandler().Send(ctx, &Request{
+		Method: &method,
+		Params: params,
+	})
+	if err != nil {
+		return err
+	}
+
+	response := &RawResponse{}
+	err = json.Unmarshal(respBytes, response)
+	if err != nil {
+		return fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+	}
+
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if response.Result == nil {
+		return fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
+	}
+
+	err = json.Unmarshal(response.Result, result)
+	if err != nil {
+		return fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
+	}
+
+	return nil
+}
+
+// archiveTLS returns the TLS material referenced by the archived config and webhooks
+func archiveTLS(archive *Archive) *ArchiveTLS {
+
+	tls := &ArchiveTLS{}
+
+	usesUserCA := func(sslCa *string) bool {
+		return sslCa != nil && *sslCa == "user_ca.pem"
+	}
+
+	if config := archive.Config; config != nil {
+
+		if config.Mqtt != nil {
+			tls.UserCA = tls.UserCA || usesUserCA(config.Mqtt.SslCa)
+			tls.TLSClientCert = config.Mqtt.UseClientCert != nil && *config.Mqtt.UseClientCert
+		}
+
+		if config.Websocket != nil {
+			tls.UserCA = tls.UserCA || usesUserCA(config.Websocket.SslCa)
+		}
+	}
+
+	for _, v := range archive.Webhooks {
+		tls.UserCA = tls.UserCA || usesUserCA(v.SslCa)
+	}
+
+	return tls
+}
+
+// remapScriptID updates the script id of Script.* calls
+func remapScriptID(call *ScheduleCall, scriptIDs map[int]int) {
+
+	if !strings.HasPrefix(strings.ToLower(call.Method), "script.") || call.Params == nil {
+		return
+	}
+
+	var id int
+
+	switch v := call.Params["id"].(type) {
+	case float64:
+		id = int(v)
+	case int:
+		id = v
+	default:
+		return
+	}
+
+	if newID, ok := scriptIDs[id]; ok {
+		call.Params["id"] = newID
+	}
+}
+
+func intValue(v *int, def int) int {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return "<unknown>"
+	}
+	return *v
+}
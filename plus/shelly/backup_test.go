@@ -0,0 +1,100 @@
+package shelly_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/simulator"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+func TestBackupRestore(t *testing.T) {
+
+	source, sourceClient := startDevice(t, &simulator.Config{})
+	_, targetClient := startDevice(t, &simulator.Config{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	report := sourceClient.Shelly().SetConfig(ctx, &types.ShellyConfig{
+		System: &types.SystemConfig{Device: &types.SystemDevice{Name: ptr("garage")}},
+		Mqtt: &types.MqttConfig{
+			Enable:      true,
+			Server:      ptr("broker:1883"),
+			User:        ptr("u"),
+			Pass:        ptr("broker secret"),
+			ClientID:    ptr("source-client"),
+			TopicPrefix: ptr(source.ID()),
+		},
+		Switch: []*types.SwitchConfig{{ID: 0, Name: ptr("pump")}},
+	})
+
+	err := report.Error()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := sourceClient.Shelly().Backup(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The simulator does not implement these services
+	warnings := []string{
+		"Script is not supported by the device",
+		"Schedule is not supported by the device",
+		"Webhook is not supported by the device",
+		"KVS is not supported by the device",
+	}
+
+	if !reflect.DeepEqual(archive.Warnings, warnings) {
+		t.Errorf("backup warnings = %v, want %v", archive.Warnings, warnings)
+	}
+
+	archive.KVS = append(archive.KVS, &types.KVSItem{Key: "mode", Value: "eco"})
+
+	restore, err := targetClient.Shelly().Restore(ctx, archive, &types.ArchiveRestoreOptions{
+		IncludeName: true,
+		Secrets:     types.MapSecretResolver{"mqtt.pass": "broker secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = restore.Config.Error()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(restore.KVS) != 1 || !types.IsNotImplemented(restore.KVS[0].Error) {
+		t.Errorf("kvs report = %v, want a not implemented error", restore.KVS)
+	}
+
+	if restore.Error() == nil {
+		t.Errorf("restore report has no error for the kvs item")
+	}
+
+	config, err := targetClient.Shelly().GetConfig(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name := config.System.Device.Name; name == nil || *name != "garage" {
+		t.Errorf("sys.device.name = %v, want garage", name)
+	}
+
+	if name := config.Switch[0].Name; name == nil || *name != "pump" {
+		t.Errorf("switch:0.name = %v, want pump", name)
+	}
+
+	if server := config.Mqtt.Server; server == nil || *server != "broker:1883" {
+		t.Errorf("mqtt.server = %v, want broker:1883", server)
+	}
+
+	// Identity fields of the source are not restored
+	if config.Mqtt.ClientID != nil || config.Mqtt.TopicPrefix != nil {
+		t.Errorf("mqtt client_id = %v and topic_prefix = %v, want them not restored", config.Mqtt.ClientID, config.Mqtt.TopicPrefix)
+	}
+}
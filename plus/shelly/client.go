@@ -6,6 +6,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jodydadescott/shelly-go-sdk/plus/bluetooth"
 	"github.com/jodydadescott/shelly-go-sdk/plus/cloud"
@@ -15,6 +17,7 @@ import (
 	"github.com/jodydadescott/shelly-go-sdk/plus/mqtt"
 	"github.com/jodydadescott/shelly-go-sdk/plus/switchx"
 	"github.com/jodydadescott/shelly-go-sdk/plus/system"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
 	"github.com/jodydadescott/shelly-go-sdk/plus/websocket"
 	"github.com/jodydadescott/shelly-go-sdk/plus/wifi"
 )
@@ -68,7 +71,7 @@ func (t *Client) GetStatus(ctx context.Context) (*ShellyStatus, error) {
 	response := &GetStatusResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -76,7 +79,7 @@ func (t *Client) GetStatus(ctx context.Context) (*ShellyStatus, error) {
 	}
 
 	if response.Result == nil {
-		return nil, fmt.Errorf("Result is missing from response")
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
 	}
 
 	return response.Result.convert(), nil
@@ -100,7 +103,7 @@ func (t *Client) ListMethods(ctx context.Context) (*ShellyRPCMethods, error) {
 	response := &ListMethodsResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -108,7 +111,7 @@ func (t *Client) ListMethods(ctx context.Context) (*ShellyRPCMethods, error) {
 	}
 
 	if response.Result == nil {
-		return nil, fmt.Errorf("Result is missing from response")
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
 	}
 
 	return response.Result, nil
@@ -129,7 +132,7 @@ func (t *Client) GetConfig(ctx context.Context, markup bool) (*ShellyConfig, err
 	response := &GetConfigResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -137,7 +140,7 @@ func (t *Client) GetConfig(ctx context.Context, markup bool) (*ShellyConfig, err
 	}
 
 	if response.Result == nil {
-		return nil, fmt.Errorf("Result is missing from response")
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
 	}
 
 	config := response.Result.convert()
@@ -145,6 +148,10 @@ func (t *Client) GetConfig(ctx context.Context, markup bool) (*ShellyConfig, err
 	if markup {
 
 		if t.getMessageHandler().IsAuthEnabled() {
+			// The device does not return auth as part of the config
+			if config.Auth == nil {
+				config.Auth = &ShellyAuthConfig{}
+			}
 			config.Auth.Enable = true
 		}
 
@@ -155,11 +162,46 @@ func (t *Client) GetConfig(ctx context.Context, markup bool) (*ShellyConfig, err
 }
 
 // SetConfig sets the configuration for each component with non nil config. Note that this function
-// calls into each componenet as necessary.
+// calls into each componenet as necessary. See SetConfigWithOptions to reboot and verify.
 func (t *Client) SetConfig(ctx context.Context, config *ShellyConfig) *ShellyReport {
+	return t.SetConfigWithOptions(ctx, config, nil)
+}
+
+// SetConfigWithOptions sets the configuration like SetConfig. Rebooting and verifying are opt in with
+// options; see ShellySetConfigOptions. Options may be nil.
+func (t *Client) SetConfigWithOptions(ctx context.Context, config *ShellyConfig, options *ShellySetConfigOptions) *ShellyReport {
+
+	report := t.setConfig(ctx, config)
+
+	if options == nil {
+		return report
+	}
+
+	if options.Reboot && report.RebootRequired() {
+
+		timeout := options.RebootTimeout
+		if timeout <= 0 {
+			timeout = defaultRebootTimeout
+		}
+
+		report.Reboot = &ComponentReport{}
+		report.Reboot.Error = t.RebootAndWait(ctx, timeout)
+
+		if report.Reboot.Error != nil {
+			return report
+		}
+	}
+
+	if options.Verify || report.Reboot != nil {
+		report.Verify = &ComponentReport{}
+		report.Verify.Error = t.Verify(ctx, config, report)
+	}
+
+	return report
+}
 
-	config = config.Clone()
-	config.Sanatize()
+// setConfig sets each component with non nil config and returns the report
+func (t *Client) setConfig(ctx context.Context, config *ShellyConfig) *ShellyReport {
 
 	report := &ShellyReport{}
 
@@ -231,27 +273,33 @@ func (t *Client) SetConfig(ctx context.Context, config *ShellyConfig) *ShellyRep
 
 	if config.Light != nil {
 		for _, v := range config.Light {
+			rebootRequired, err := t.Light().SetConfig(ctx, v)
 			report.Light = append(report.Light, &ComponentReport{
-				ID:    &v.ID,
-				Error: t.Light().SetConfig(ctx, v),
+				ID:             &v.ID,
+				RebootRequired: rebootRequired,
+				Error:          err,
 			})
 		}
 	}
 
 	if config.Input != nil {
 		for _, v := range config.Input {
+			rebootRequired, err := t.Input().SetConfig(ctx, v)
 			report.Input = append(report.Input, &ComponentReport{
-				ID:    &v.ID,
-				Error: t.Input().SetConfig(ctx, v),
+				ID:             &v.ID,
+				RebootRequired: rebootRequired,
+				Error:          err,
 			})
 		}
 	}
 
 	if config.Switch != nil {
 		for _, v := range config.Switch {
+			rebootRequired, err := t.Switch().SetConfig(ctx, v)
 			report.Switch = append(report.Switch, &ComponentReport{
-				ID:    &v.ID,
-				Error: t.Switch().SetConfig(ctx, v),
+				ID:             &v.ID,
+				RebootRequired: rebootRequired,
+				Error:          err,
 			})
 		}
 	}
@@ -264,6 +312,200 @@ func (t *Client) SetConfig(ctx context.Context, config *ShellyConfig) *ShellyRep
 	return report
 }
 
+// Verify reads the config back from the device and records the fields that differ from config as
+// Mismatches in the matching component report. Only components that were set without error are
+// verified. The components TLSClientCert, TLSClientKey and UserCA can not be read back and are not
+// verified. Write only fields such as passwords are ignored. An error is returned if the config can
+// not be read from the device.
+func (t *Client) Verify(ctx context.Context, config *ShellyConfig, report *ShellyReport) error {
+
+	sent := &ShellyConfig{}
+
+	if report.Auth != nil && report.Auth.Error == nil {
+		sent.Auth = config.Auth
+	}
+
+	if report.Bluetooth != nil && report.Bluetooth.Error == nil {
+		sent.Bluetooth = config.Bluetooth
+	}
+
+	if report.Cloud != nil && report.Cloud.Error == nil {
+		sent.Cloud = config.Cloud
+	}
+
+	if report.Mqtt != nil && report.Mqtt.Error == nil {
+		sent.Mqtt = config.Mqtt
+	}
+
+	if report.Ethernet != nil && report.Ethernet.Error == nil {
+		sent.Ethernet = config.Ethernet
+	}
+
+	if report.System != nil && report.System.Error == nil {
+		sent.System = config.System
+	}
+
+	if report.Wifi != nil && report.Wifi.Error == nil {
+		sent.Wifi = config.Wifi
+	}
+
+	if report.Websocket != nil && report.Websocket.Error == nil {
+		sent.Websocket = config.Websocket
+	}
+
+	for _, v := range report.Light {
+		if v.Error == nil {
+			sent.Light = append(sent.Light, config.GetLight(*v.ID))
+		}
+	}
+
+	for _, v := range report.Input {
+		if v.Error == nil {
+			sent.Input = append(sent.Input, config.GetInput(*v.ID))
+		}
+	}
+
+	for _, v := range report.Switch {
+		if v.Error == nil {
+			sent.Switch = append(sent.Switch, config.GetSwitch(*v.ID))
+		}
+	}
+
+	plan, err := t.Plan(ctx, sent)
+	if err != nil {
+		return err
+	}
+
+	setMismatches := func(report *ComponentReport, plan *ComponentPlan) {
+		if report != nil && plan != nil {
+			for _, change := range plan.Changes {
+				if !change.Unverifiable {
+					report.Mismatches = append(report.Mismatches, change)
+				}
+			}
+		}
+	}
+
+	setMismatches(report.Auth, plan.Auth)
+	setMismatches(report.Bluetooth, plan.Bluetooth)
+	setMismatches(report.Cloud, plan.Cloud)
+	setMismatches(report.Mqtt, plan.Mqtt)
+	setMismatches(report.Ethernet, plan.Ethernet)
+	setMismatches(report.System, plan.System)
+	setMismatches(report.Wifi, plan.Wifi)
+	setMismatches(report.Websocket, plan.Websocket)
+
+	for _, v := range plan.Light {
+		for _, r := range report.Light {
+			if *r.ID == *v.ID {
+				setMismatches(r, v)
+			}
+		}
+	}
+
+	for _, v := range plan.Input {
+		for _, r := range report.Input {
+			if *r.ID == *v.ID {
+				setMismatches(r, v)
+			}
+		}
+	}
+
+	for _, v := range plan.Switch {
+		for _, r := range report.Switch {
+			if *r.ID == *v.ID {
+				setMismatches(r, v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RebootAndWait reboots the device and waits for it to come back. The device is considered back
+// when the connection has been re-established and the system status no longer reports that a
+// restart is required.
+func (t *Client) RebootAndWait(ctx context.Context, timeout time.Duration) error {
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	notifier, _ := t.clientContract.(types.ConnectionNotifier)
+
+	count := 0
+	if notifier != nil {
+		count = notifier.ConnectCount()
+	}
+
+	err := t.Reboot(ctx)
+	if err != nil {
+		return err
+	}
+
+	if notifier != nil {
+		err = notifier.WaitConnect(ctx, count)
+		if err != nil {
+			return fmt.Errorf("%w: device did not reconnect after reboot: %w", types.ErrTimeout, err)
+		}
+	}
+
+	for {
+
+		status, err := t.System().GetStatus(ctx)
+		if err == nil {
+			if status.RestartRequired == nil || !*status.RestartRequired {
+				return nil
+			}
+		}
+
+		select {
+
+		case <-ctx.Done():
+			return fmt.Errorf("%w: device still reports restart required: %w", types.ErrTimeout, ctx.Err())
+
+		case <-time.After(rebootPollInterval):
+
+		}
+	}
+}
+
+// Plan fetches the current config from the device and returns the changes required to get to the
+// desired config. Nothing is sent to the device. Use Apply to apply the plan.
+func (t *Client) Plan(ctx context.Context, desired *ShellyConfig) (*ShellyPlan, error) {
+
+	current, err := t.GetConfig(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceInfo, err := t.GetDeviceInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current.Auth = &ShellyAuthConfig{
+		Enable: deviceInfo.AuthEnabled,
+	}
+
+	current.Sanatize()
+
+	desired = desired.Clone()
+	desired.Sanatize()
+
+	return types.NewShellyPlan(current, desired), nil
+}
+
+// Apply sends the components of the plan that have changes to the device. Components without
+// changes are not sent.
+func (t *Client) Apply(ctx context.Context, plan *ShellyPlan) *ShellyReport {
+	return t.SetConfig(ctx, plan.Config())
+}
+
+// ApplyWithOptions applies the plan like Apply with options passed to SetConfigWithOptions
+func (t *Client) ApplyWithOptions(ctx context.Context, plan *ShellyPlan, options *ShellySetConfigOptions) *ShellyReport {
+	return t.SetConfigWithOptions(ctx, plan.Config(), options)
+}
+
 // GetDeviceInfo returns information about the device.
 func (t *Client) GetDeviceInfo(ctx context.Context) (*DeviceInfo, error) {
 
@@ -279,7 +521,7 @@ func (t *Client) GetDeviceInfo(ctx context.Context) (*DeviceInfo, error) {
 	response := &DeviceInfoResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -287,7 +529,7 @@ func (t *Client) GetDeviceInfo(ctx context.Context) (*DeviceInfo, error) {
 	}
 
 	if response.Result == nil {
-		return nil, fmt.Errorf("Result is missing from response")
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
 	}
 
 	return response.Result, nil
@@ -309,7 +551,7 @@ func (t *Client) CheckForUpdate(ctx context.Context) (*UpdatesReport, error) {
 	response := &CheckForUpdateResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -317,7 +559,7 @@ func (t *Client) CheckForUpdate(ctx context.Context) (*UpdatesReport, error) {
 	}
 
 	if response.Result == nil {
-		return nil, fmt.Errorf("Result is missing from response")
+		return nil, fmt.Errorf("%w: Result is missing from response", types.ErrMalformedResponse)
 	}
 
 	return &UpdatesReport{
@@ -342,7 +584,7 @@ func (t *Client) Update(ctx context.Context, config *ShellyUpdateConfig) error {
 	response := &SetConfigResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -352,6 +594,189 @@ func (t *Client) Update(ctx context.Context, config *ShellyUpdateConfig) error {
 	return nil
 }
 
+// UpdateAndWait updates the firmware to the latest version of stage (stable or beta) and waits for
+// the device to come back with the new version. See UpdateAndWaitWithOptions.
+func (t *Client) UpdateAndWait(ctx context.Context, stage string) *UpdateResult {
+	return t.UpdateAndWaitWithOptions(ctx, &ShellyUpdateOptions{
+		Stage: stage,
+	})
+}
+
+// UpdateAndWaitWithOptions checks for an update of the configured stage and skips if there is none.
+// Otherwise the update is started and ota events are followed until the device reboots and
+// reconnects. The device info is then polled until the new version is reported. The result is
+// failed if the device reports an ota_error, comes back with a different version or the timeout
+// expires.
+func (t *Client) UpdateAndWaitWithOptions(ctx context.Context, options *ShellyUpdateOptions) *UpdateResult {
+
+	if options == nil {
+		options = &ShellyUpdateOptions{}
+	}
+
+	stage := options.Stage
+	if stage == "" {
+		stage = "stable"
+	}
+
+	result := &UpdateResult{
+		Stage: stage,
+	}
+
+	fail := func(format string, args ...interface{}) *UpdateResult {
+		result.Status = types.UpdateStatusFailed
+		result.Reason = fmt.Sprintf(format, args...)
+		return result
+	}
+
+	if stage != "stable" && stage != "beta" {
+		return fail("stage %q is not one of stable, beta", stage)
+	}
+
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = defaultUpdateTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	before, err := t.GetDeviceInfo(ctx)
+	if err != nil {
+		return fail("unable to get device info: %v", err)
+	}
+
+	result.FromVersion = before.Version
+
+	updates, err := t.CheckForUpdate(ctx)
+	if err != nil {
+		return fail("unable to check for update: %v", err)
+	}
+
+	var available *FirmwareStatus
+
+	if updates.AvailableUpdates != nil {
+		if stage == "beta" {
+			available = updates.AvailableUpdates.Beta
+		} else {
+			available = updates.AvailableUpdates.Stable
+		}
+	}
+
+	if available == nil || available.Version == nil {
+		result.Status = types.UpdateStatusSkipped
+		result.Reason = fmt.Sprintf("no %s update available", stage)
+		return result
+	}
+
+	result.ToVersion = available.Version
+
+	progress := make(chan *UpdateProgress, 64)
+
+	if notifier, ok := t.clientContract.(types.NotificationNotifier); ok {
+
+		unsubscribe := notifier.Subscribe(func(notification *Notification) {
+
+			events, err := notification.Events()
+			if err != nil {
+				return
+			}
+
+			for _, v := range events {
+
+				if v.Component != "sys" || !strings.HasPrefix(v.Event, "ota_") {
+					continue
+				}
+
+				select {
+				case progress <- &UpdateProgress{Event: v.Event, Percent: v.ProgressPercent, Message: v.Msg}:
+				default:
+				}
+			}
+		})
+
+		defer unsubscribe()
+	}
+
+	connectionNotifier, _ := t.clientContract.(types.ConnectionNotifier)
+
+	count := 0
+	if connectionNotifier != nil {
+		count = connectionNotifier.ConnectCount()
+	}
+
+	err = t.Update(ctx, &ShellyUpdateConfig{
+		Stage: &stage,
+	})
+
+	if err != nil {
+		return fail("unable to start update: %v", err)
+	}
+
+	reconnected := make(chan error, 1)
+	waitingForReconnect := connectionNotifier != nil
+
+	if waitingForReconnect {
+		go func() {
+			reconnected <- connectionNotifier.WaitConnect(ctx, count)
+		}()
+	}
+
+	ticker := time.NewTicker(updatePollInterval)
+	defer ticker.Stop()
+
+	current := before.Version
+
+	for {
+
+		select {
+
+		case p := <-progress:
+			if options.Progress != nil {
+				options.Progress(p)
+			}
+			if p.Event == "ota_error" {
+				return fail("device reported ota_error: %s", stringValue(p.Message))
+			}
+			continue
+
+		case err := <-reconnected:
+			if err != nil {
+				return fail("device did not reconnect: %v", err)
+			}
+			waitingForReconnect = false
+
+		case <-ctx.Done():
+			return fail("timeout waiting for version %s; device reports %s", *available.Version, stringValue(current))
+
+		case <-ticker.C:
+
+		}
+
+		if waitingForReconnect {
+			continue
+		}
+
+		// The device is unreachable while it reboots; errors are expected
+		info, err := t.GetDeviceInfo(ctx)
+		if err != nil || info.Version == nil {
+			continue
+		}
+
+		current = info.Version
+
+		if *info.Version == *available.Version {
+			result.Status = types.UpdateStatusUpdated
+			result.ToVersion = info.Version
+			return result
+		}
+
+		// The device has rebooted; a different version means the update was not applied or rolled back
+		if connectionNotifier != nil {
+			return fail("device came back with version %s, expected %s", *info.Version, *available.Version)
+		}
+	}
+}
+
 // FactoryReset resets the configuration to its default state
 func (t *Client) FactoryReset(ctx context.Context) error {
 
@@ -367,7 +792,7 @@ func (t *Client) FactoryReset(ctx context.Context) error {
 	response := &SetConfigResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -392,7 +817,7 @@ func (t *Client) ResetWiFiConfig(ctx context.Context) error {
 	response := &SetConfigResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -417,7 +842,7 @@ func (t *Client) Reboot(ctx context.Context) error {
 	response := &SetConfigResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -431,6 +856,11 @@ func (t *Client) setAuth(ctx context.Context, config *ShellyAuthConfig) error {
 
 	method := Component + ".SetAuth"
 
+	config, err := types.PrepareConfig(config)
+	if err != nil {
+		return err
+	}
+
 	raw := &RawShellyAuthConfig{}
 
 	if config.Enable {
@@ -467,7 +897,7 @@ func (t *Client) setAuth(ctx context.Context, config *ShellyAuthConfig) error {
 	response := &SetConfigResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -487,7 +917,7 @@ func (t *Client) send(ctx context.Context, request *Request) error {
 	response := &SetConfigResponse{}
 	err = json.Unmarshal(respBytes, response)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", types.ErrMalformedResponse, err)
 	}
 
 	if response.Error != nil {
@@ -501,6 +931,11 @@ func (t *Client) setUserCA(ctx context.Context, config *ShellyUserCAConfig) erro
 
 	method := Component + ".PutUserCA"
 
+	config, err := types.PrepareConfig(config)
+	if err != nil {
+		return err
+	}
+
 	if config.Enable {
 
 		if config.Data == nil {
@@ -550,6 +985,11 @@ func (t *Client) setTLSClientCert(ctx context.Context, config *ShellyTLSClientCe
 
 	method := Component + ".PutTLSClientCert"
 
+	config, err := types.PrepareConfig(config)
+	if err != nil {
+		return err
+	}
+
 	if config.Enable {
 
 		if config.Data == nil {
@@ -599,6 +1039,11 @@ func (t *Client) setTLSClientKey(ctx context.Context, config *ShellyTLSClientKey
 
 	method := Component + ".PutTLSClientKey"
 
+	config, err := types.PrepareConfig(config)
+	if err != nil {
+		return err
+	}
+
 	if config.Enable {
 
 		if config.Data == nil {
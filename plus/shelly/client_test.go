@@ -0,0 +1,437 @@
+package shelly_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
+	"github.com/jodydadescott/shelly-go-sdk/plus/simulator"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// startDevice starts a simulated device and returns a client connected to it
+func startDevice(t *testing.T, config *simulator.Config) (*simulator.Device, *plus.Client) {
+
+	device := simulator.New(config)
+	err := device.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { device.Close() })
+
+	client, err := plus.New(&msghandlers.Config{
+		Hostname:    device.Hostname(),
+		Username:    types.ShellyUser,
+		Password:    config.Password,
+		SendTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+
+	return device, client
+}
+
+// interceptResult returns an interceptor that passes the result of method to fn, which may modify it,
+// and sends the modified result to the caller
+func interceptResult(method string, fn func(result map[string]interface{})) types.Interceptor {
+	return func(ctx context.Context, request *types.Request, next types.SendFunc) ([]byte, error) {
+
+		b, err := next(ctx, request)
+		if err != nil || request.Method == nil || *request.Method != method {
+			return b, err
+		}
+
+		response := map[string]interface{}{}
+		if json.Unmarshal(b, &response) != nil {
+			return b, err
+		}
+
+		result, ok := response["result"].(map[string]interface{})
+		if !ok {
+			return b, err
+		}
+
+		fn(result)
+
+		return json.Marshal(response)
+	}
+}
+
+func TestSetConfigWithOptionsReboot(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		config *types.ShellyConfig
+		reboot bool
+	}{
+		{
+			name:   "restart required",
+			config: &types.ShellyConfig{Mqtt: &types.MqttConfig{Server: ptr("broker:1883")}},
+			reboot: true,
+		},
+		{
+			name:   "no restart",
+			config: &types.ShellyConfig{Switch: []*types.SwitchConfig{{ID: 0, Name: ptr("pump")}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, client := startDevice(t, &simulator.Config{})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			_, err := client.Shelly().GetDeviceInfo(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			connects := client.ConnectCount()
+
+			report := client.Shelly().SetConfigWithOptions(ctx, tt.config, &types.ShellySetConfigOptions{Reboot: true})
+
+			err = report.Error()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if rebooted := report.Reboot != nil; rebooted != tt.reboot {
+				t.Fatalf("rebooted = %t, want %t", rebooted, tt.reboot)
+			}
+
+			reconnects := client.ConnectCount() - connects
+			if tt.reboot && reconnects != 1 || !tt.reboot && reconnects != 0 {
+				t.Errorf("reconnects = %d, reboot %t", reconnects, tt.reboot)
+			}
+
+			status, err := client.System().GetStatus(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if status.RestartRequired == nil || *status.RestartRequired {
+				t.Errorf("restart required = %v after SetConfigWithOptions returned", status.RestartRequired)
+			}
+		})
+	}
+}
+
+func TestRebootAndWaitRestartRequired(t *testing.T) {
+
+	_, client := startDevice(t, &simulator.Config{})
+
+	// The device still reports restart_required in the first two polls after it is back
+	var polls int32
+	client.Use(interceptResult("Sys.GetStatus", func(result map[string]interface{}) {
+		if atomic.AddInt32(&polls, 1) <= 2 {
+			result["restart_required"] = true
+		}
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := client.Shelly().RebootAndWait(ctx, 20*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atomic.LoadInt32(&polls); got != 3 {
+		t.Errorf("polls = %d, want 3", got)
+	}
+}
+
+func TestRebootAndWaitTimeout(t *testing.T) {
+
+	_, client := startDevice(t, &simulator.Config{})
+
+	ctx := context.Background()
+
+	_, err := client.Shelly().GetDeviceInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+
+	err = client.Shelly().RebootAndWait(ctx, 500*time.Millisecond)
+	if !errors.Is(err, types.ErrTimeout) {
+		t.Fatalf("error = %v, want %v", err, types.ErrTimeout)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("RebootAndWait returned after %v, timeout is 500ms", elapsed)
+	}
+}
+
+func TestVerify(t *testing.T) {
+
+	getConfigErr := &types.Error{Code: types.ErrorCodeUnAvailable, Message: "busy"}
+
+	tests := []struct {
+		name   string
+		config *types.ShellyConfig
+		// interceptor fails Shelly.GetConfig if set
+		failGetConfig bool
+		mismatches    []string
+		// unverifiable write only fields that must not be reported as mismatches
+		unverifiable []string
+		verifyErr    error
+	}{
+		{
+			name:       "clamped",
+			config:     &types.ShellyConfig{Switch: []*types.SwitchConfig{{ID: 0, PowerLimit: ptr(4000.0)}}},
+			mismatches: []string{"switch:0.power_limit"},
+		},
+		{
+			name:         "write only",
+			config:       &types.ShellyConfig{Mqtt: &types.MqttConfig{Server: ptr("broker:1883"), User: ptr("u"), Pass: ptr("secret")}},
+			unverifiable: []string{"mqtt.pass"},
+		},
+		{
+			name:          "get config error",
+			config:        &types.ShellyConfig{Switch: []*types.SwitchConfig{{ID: 0, Name: ptr("pump")}}},
+			failGetConfig: true,
+			verifyErr:     getConfigErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, client := startDevice(t, &simulator.Config{})
+
+			if tt.failGetConfig {
+				client.Use(func(ctx context.Context, request *types.Request, next types.SendFunc) ([]byte, error) {
+					if *request.Method == "Shelly.GetConfig" {
+						return nil, getConfigErr
+					}
+					return next(ctx, request)
+				})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			report := client.Shelly().SetConfigWithOptions(ctx, tt.config, &types.ShellySetConfigOptions{Verify: true})
+
+			if report.Verify == nil {
+				t.Fatal("config was not verified")
+			}
+
+			if !errors.Is(report.Verify.Error, tt.verifyErr) {
+				t.Fatalf("verify error = %v, want %v", report.Verify.Error, tt.verifyErr)
+			}
+
+			var mismatches []string
+			for _, v := range append(report.Switch, report.Mqtt) {
+				if v == nil {
+					continue
+				}
+				for _, change := range v.Mismatches {
+					mismatches = append(mismatches, change.Path)
+				}
+			}
+
+			if !reflect.DeepEqual(mismatches, tt.mismatches) {
+				t.Errorf("mismatches = %v, want %v", mismatches, tt.mismatches)
+			}
+
+			if tt.unverifiable != nil {
+
+				plan, err := client.Shelly().Plan(ctx, tt.config)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var unverifiable []string
+				for _, change := range plan.Mqtt.Changes {
+					if change.Unverifiable {
+						unverifiable = append(unverifiable, change.Path)
+					}
+				}
+
+				if !reflect.DeepEqual(unverifiable, tt.unverifiable) {
+					t.Errorf("unverifiable = %v, want %v", unverifiable, tt.unverifiable)
+				}
+			}
+
+			if len(tt.mismatches) > 0 || tt.verifyErr != nil {
+				if report.Error() == nil {
+					t.Errorf("report has no error")
+				}
+			} else if err := report.Error(); err != nil {
+				t.Errorf("report error = %v", err)
+			}
+		})
+	}
+}
+
+func TestSetConfigMarkup(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		resolver types.MapSecretResolver
+		// errors components that must fail because a placeholder would be sent
+		errors []string
+	}{
+		{
+			name:     "unresolved",
+			resolver: types.MapSecretResolver{},
+			errors:   []string{"auth", "mqtt"},
+		},
+		{
+			name:     "resolved",
+			resolver: types.MapSecretResolver{"auth.pass": "secret", "mqtt.pass": "broker secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, client := startDevice(t, &simulator.Config{Password: "secret"})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			_, err := client.Mqtt().SetConfig(ctx, &types.MqttConfig{Enable: true, Server: ptr("broker:1883"), User: ptr("u"), Pass: ptr("broker secret")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			exported, err := client.Shelly().GetConfig(ctx, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := exported.ResolveSecrets(tt.resolver)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report := client.Shelly().SetConfig(ctx, config)
+
+			components := map[string]*types.ComponentReport{
+				"auth": report.Auth,
+				"mqtt": report.Mqtt,
+				"sys":  report.System,
+				"wifi": report.Wifi,
+			}
+
+			var errors []string
+			for _, name := range []string{"auth", "mqtt", "sys", "wifi"} {
+
+				component := components[name]
+				if component == nil {
+					t.Fatalf("%s was not set", name)
+				}
+
+				if component.Error != nil {
+					if !strings.Contains(component.Error.Error(), "placeholders") {
+						t.Errorf("%s: %v", name, component.Error)
+					}
+					errors = append(errors, name)
+				}
+			}
+
+			if !reflect.DeepEqual(errors, tt.errors) {
+				t.Errorf("components with errors = %v, want %v", errors, tt.errors)
+			}
+
+			// The device still accepts the password
+			_, err = client.System().GetConfig(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestUpdateAndWait(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		config  *simulator.Config
+		timeout time.Duration
+		status  types.UpdateStatus
+		// reason substring of the expected reason
+		reason string
+	}{
+		{
+			name:   "up to date",
+			config: &simulator.Config{Version: "1.0.0"},
+			status: types.UpdateStatusSkipped,
+			reason: "no stable update available",
+		},
+		{
+			name:   "updated",
+			config: &simulator.Config{Version: "1.0.0", StableUpdate: "1.0.1"},
+			status: types.UpdateStatusUpdated,
+		},
+		{
+			name:   "ota error",
+			config: &simulator.Config{Version: "1.0.0", StableUpdate: "1.0.1", OTAError: "Image checksum mismatch"},
+			status: types.UpdateStatusFailed,
+			reason: "device reported ota_error: Image checksum mismatch",
+		},
+		{
+			name:   "rollback",
+			config: &simulator.Config{Version: "1.0.0", StableUpdate: "1.0.1", OTARollback: true},
+			status: types.UpdateStatusFailed,
+			reason: "device came back with version 1.0.0, expected 1.0.1",
+		},
+		{
+			name:    "timeout",
+			config:  &simulator.Config{Version: "1.0.0", StableUpdate: "1.0.1"},
+			timeout: time.Second,
+			status:  types.UpdateStatusFailed,
+			reason:  "timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, client := startDevice(t, tt.config)
+
+			var events []string
+
+			result := client.Shelly().UpdateAndWaitWithOptions(context.Background(), &types.ShellyUpdateOptions{
+				Timeout:  tt.timeout,
+				Progress: func(p *types.UpdateProgress) { events = append(events, p.Event) },
+			})
+
+			if result.Status != tt.status {
+				t.Fatalf("status = %s, want %s; reason %s", result.Status, tt.status, result.Reason)
+			}
+
+			if !strings.Contains(result.Reason, tt.reason) {
+				t.Errorf("reason = %q, want %q", result.Reason, tt.reason)
+			}
+
+			if tt.status == types.UpdateStatusUpdated {
+				if result.ToVersion == nil || *result.ToVersion != tt.config.StableUpdate {
+					t.Errorf("to version = %v, want %s", result.ToVersion, tt.config.StableUpdate)
+				}
+				if len(events) == 0 || events[len(events)-1] != "ota_success" {
+					t.Errorf("events = %v, want ota_success last", events)
+				}
+			}
+		})
+	}
+}
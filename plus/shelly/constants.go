@@ -1,5 +1,7 @@
 package shelly
 
+import "time"
+
 const (
 	Component = "Shelly"
 
@@ -7,4 +9,12 @@ const (
 	ShellyUser = "admin"
 
 	maxRPCChunkSize = 1000
+
+	defaultRebootTimeout = time.Duration(2) * time.Minute
+
+	rebootPollInterval = time.Duration(2) * time.Second
+
+	defaultUpdateTimeout = time.Duration(10) * time.Minute
+
+	updatePollInterval = time.Duration(5) * time.Second
 )
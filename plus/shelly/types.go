@@ -1,6 +1,8 @@
 package shelly
 
 import (
+	"encoding/json"
+
 	"github.com/jodydadescott/shelly-go-sdk/plus/types"
 )
 
@@ -31,10 +33,29 @@ type MqttConfig = types.MqttConfig
 type ShellyStatus = types.ShellyStatus
 type ShellyReport = types.ShellyReport
 type ComponentReport = types.ComponentReport
+type ShellyPlan = types.ShellyPlan
+type ComponentPlan = types.ComponentPlan
+type Change = types.Change
 type ShellyRPCMethods = types.ShellyRPCMethods
 type ShellyConfig = types.ShellyConfig
 type DeviceInfo = types.DeviceInfo
 type ShellyUpdateConfig = types.ShellyUpdateConfig
+type ShellySetConfigOptions = types.ShellySetConfigOptions
+type ShellyUpdateOptions = types.ShellyUpdateOptions
+type UpdateProgress = types.UpdateProgress
+type UpdateResult = types.UpdateResult
+type Notification = types.Notification
+type ValidationError = types.ValidationError
+type Violation = types.Violation
+type Archive = types.Archive
+type ArchiveTLS = types.ArchiveTLS
+type ArchiveRestoreOptions = types.ArchiveRestoreOptions
+type ArchiveRestoreReport = types.ArchiveRestoreReport
+type ArchiveItemReport = types.ArchiveItemReport
+type Script = types.Script
+type Schedule = types.Schedule
+type ScheduleCall = types.ScheduleCall
+type KVSItem = types.KVSItem
 type ShellyAuthConfig = types.ShellyAuthConfig
 type ShellyUserCAConfig = types.ShellyUserCAConfig
 type ShellyTLSClientCertConfig = types.ShellyTLSClientCertConfig
@@ -78,6 +99,12 @@ type Result struct {
 	Error           *Error `json:"error,omitempty"`
 }
 
+// RawResponse internal use only
+type RawResponse struct {
+	Response
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
 // SetConfigResponse internal use only
 type SetConfigResponse struct {
 	Response
@@ -1,5 +1,7 @@
 package shelly
 
+import "unicode/utf8"
+
 func splitByWidth(str string, size int) []string {
 	strLength := len(str)
 	var splited []string
@@ -13,3 +15,24 @@ func splitByWidth(str string, size int) []string {
 	}
 	return splited
 }
+
+// splitRunesByWidth splits str into chunks of at most size bytes without splitting UTF-8 sequences
+func splitRunesByWidth(str string, size int) []string {
+	var splited []string
+	for len(str) > 0 {
+		stop := size
+		if stop >= len(str) {
+			stop = len(str)
+		} else {
+			for stop > 0 && !utf8.RuneStart(str[stop]) {
+				stop--
+			}
+			if stop == 0 {
+				stop = size
+			}
+		}
+		splited = append(splited, str[:stop])
+		str = str[stop:]
+	}
+	return splited
+}
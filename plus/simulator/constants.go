@@ -0,0 +1,36 @@
+package simulator
+
+import "time"
+
+const (
+	Component = "Simulator"
+
+	defaultAddr = "127.0.0.1:0"
+
+	defaultVersion = "1.0.0"
+
+	defaultLoad = 100.0
+
+	defaultVoltage = 230.0
+
+	defaultTemperature = 42.0
+
+	// maxPowerLimit highest power_limit of a switch with power metering; higher values are clamped
+	maxPowerLimit = 3500.0
+
+	// rebootDelay time between the response to Shelly.Reboot or Shelly.Update and the simulated reboot
+	rebootDelay = time.Duration(200) * time.Millisecond
+
+	// defaultNonceTTL time a nonce is accepted after it was issued
+	defaultNonceTTL = time.Duration(60) * time.Second
+
+	// otaStepDelay time between the ota events of a simulated update
+	otaStepDelay = time.Duration(100) * time.Millisecond
+
+	authType = "digest"
+
+	authAlgorithm = "SHA-256"
+
+	// dummyHA2 is the SHA-256 of "dummy_method:dummy_uri" used by the websocket digest auth
+	dummyHA2 = "6370ec69915103833b5222b368555393393f098bfbfbb59f47e0590af135f062"
+)
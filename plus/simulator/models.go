@@ -0,0 +1,54 @@
+package simulator
+
+// Model hardware the simulator mimics
+type Model struct {
+	// Name of the model, for example Plus1PM
+	Name string
+	// Model identifier reported in the device info, for example SNSW-001P16EU
+	Model string
+	// App name of the firmware
+	App string
+	// Switches number of switch components
+	Switches int
+	// PowerMetering true if the switches report power, voltage, current and energy
+	PowerMetering bool
+	// Lights number of light components
+	Lights int
+	// Inputs number of input components
+	Inputs int
+}
+
+var (
+	// ModelPlus1PM Shelly Plus 1PM, one switch with power metering and one input
+	ModelPlus1PM = &Model{
+		Name:          "Plus1PM",
+		Model:         "SNSW-001P16EU",
+		App:           "Plus1PM",
+		Switches:      1,
+		PowerMetering: true,
+		Inputs:        1,
+	}
+
+	// ModelPro4PM Shelly Pro 4PM, four switches with power metering and four inputs
+	ModelPro4PM = &Model{
+		Name:          "Pro4PM",
+		Model:         "SPSW-104PE16EU",
+		App:           "Pro4PM",
+		Switches:      4,
+		PowerMetering: true,
+		Inputs:        4,
+	}
+
+	// ModelDimmer Shelly Plus Wall Dimmer, one light
+	ModelDimmer = &Model{
+		Name:   "Dimmer",
+		Model:  "SNDM-0013US",
+		App:    "WallDimmer",
+		Lights: 1,
+	}
+)
+
+// Models returns the models the simulator can mimic
+func Models() []*Model {
+	return []*Model{ModelPlus1PM, ModelPro4PM, ModelDimmer}
+}
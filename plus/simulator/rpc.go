@@ -0,0 +1,1003 @@
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// handler implements a method. It is called with the device locked. Changed holds the keys of the
+// components whose status changed, for example switch:0. After is called once the response is sent.
+type handler func(t *Device, p *params) (result interface{}, changed []string, after func(), err *Error)
+
+// handlers is set in init because Shelly.ListMethods refers to it
+var handlers map[string]handler
+
+// methodNames maps the lower case method names to the names in handlers. Like the real firmware method
+// names are case insensitive, for example Wifi.GetConfig is WiFi.GetConfig.
+var methodNames = map[string]string{}
+
+func init() {
+	handlers = map[string]handler{
+		"Shelly.GetDeviceInfo":    (*Device).shellyGetDeviceInfo,
+		"Shelly.GetStatus":        (*Device).shellyGetStatus,
+		"Shelly.GetConfig":        (*Device).shellyGetConfig,
+		"Shelly.ListMethods":      (*Device).shellyListMethods,
+		"Shelly.CheckForUpdate":   (*Device).shellyCheckForUpdate,
+		"Shelly.Update":           (*Device).shellyUpdate,
+		"Shelly.Reboot":           (*Device).shellyReboot,
+		"Shelly.FactoryReset":     (*Device).shellyFactoryReset,
+		"Shelly.ResetWiFiConfig":  (*Device).shellyResetWiFiConfig,
+		"Shelly.SetAuth":          (*Device).shellySetAuth,
+		"Shelly.PutUserCA":        (*Device).shellyPutUserCA,
+		"Shelly.PutTLSClientCert": (*Device).shellyPutTLSClientCert,
+		"Shelly.PutTLSClientKey":  (*Device).shellyPutTLSClientKey,
+		"Sys.GetStatus":           (*Device).sysGetStatus,
+		"Sys.GetConfig":           (*Device).sysGetConfig,
+		"Sys.SetConfig":           (*Device).sysSetConfig,
+		"WiFi.GetStatus":          (*Device).wifiGetStatus,
+		"WiFi.GetConfig":          (*Device).wifiGetConfig,
+		"WiFi.SetConfig":          (*Device).wifiSetConfig,
+		"WiFi.Scan":               (*Device).wifiScan,
+		"WiFi.ListAPClients":      (*Device).wifiListAPClients,
+		"MQTT.GetStatus":          (*Device).mqttGetStatus,
+		"MQTT.GetConfig":          (*Device).mqttGetConfig,
+		"MQTT.SetConfig":          (*Device).mqttSetConfig,
+		"Switch.GetStatus":        (*Device).switchGetStatus,
+		"Switch.GetConfig":        (*Device).switchGetConfig,
+		"Switch.SetConfig":        (*Device).switchSetConfig,
+		"Switch.Set":              (*Device).switchSet,
+		"Switch.Toggle":           (*Device).switchToggle,
+		"Light.GetStatus":         (*Device).lightGetStatus,
+		"Light.GetConfig":         (*Device).lightGetConfig,
+		"Light.SetConfig":         (*Device).lightSetConfig,
+		"Light.Set":               (*Device).lightSet,
+		"Light.Toggle":            (*Device).lightToggle,
+		"Input.GetStatus":         (*Device).inputGetStatus,
+		"Input.GetConfig":         (*Device).inputGetConfig,
+		"Input.SetConfig":         (*Device).inputSetConfig,
+	}
+
+	for method := range handlers {
+		methodNames[strings.ToLower(method)] = method
+	}
+}
+
+// hasMethod returns true if the model has the component the method belongs to. Method must be the name
+// in handlers.
+func (t *Device) hasMethod(method string) bool {
+
+	if _, ok := handlers[method]; !ok {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(method, "Switch."):
+		return len(t.switches) > 0
+	case strings.HasPrefix(method, "Light."):
+		return len(t.lights) > 0
+	case strings.HasPrefix(method, "Input."):
+		return len(t.inputs) > 0
+	}
+
+	return true
+}
+
+// call dispatches the method. It is called with the device locked. The result and the status of the
+// changed components are returned marshalled so that they are not read after the lock is released.
+func (t *Device) call(method string, raw json.RawMessage) (json.RawMessage, map[string]json.RawMessage, func(), *Error) {
+
+	name := methodNames[strings.ToLower(method)]
+
+	if !t.hasMethod(name) {
+		return nil, nil, nil, &Error{Code: types.ErrorCodeNotImplemented, Message: "No handler for " + method}
+	}
+
+	p := &params{}
+	if len(raw) > 0 && string(raw) != "null" {
+		err := json.Unmarshal(raw, p)
+		if err != nil {
+			return nil, nil, nil, invalidArgument(err.Error())
+		}
+	}
+
+	result, changed, after, rpcErr := handlers[name](t, p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, nil, nil, &Error{Code: types.ErrorCodeUnAvailable, Message: err.Error()}
+	}
+
+	return b, t.snapshot(changed), after, nil
+}
+
+// snapshot returns the marshalled status of the components. It is called with the device locked.
+func (t *Device) snapshot(keys []string) map[string]json.RawMessage {
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	result := map[string]json.RawMessage{}
+
+	for _, key := range keys {
+		b, err := json.Marshal(t.componentStatus(key))
+		if err == nil {
+			result[key] = b
+		}
+	}
+
+	return result
+}
+
+// componentStatus returns the status of the component with key, for example switch:0
+func (t *Device) componentStatus(key string) interface{} {
+
+	name, idStr, _ := strings.Cut(key, ":")
+
+	var id int
+	fmt.Sscanf(idStr, "%d", &id)
+
+	switch name {
+	case "sys":
+		return t.sysStatus()
+	case "wifi":
+		return t.wifiStatus()
+	case "mqtt":
+		return t.mqttStatus()
+	case "switch":
+		return t.switchStatus(id)
+	case "light":
+		return t.lightStatus(id)
+	case "input":
+		return t.inputStatus(id)
+	}
+
+	return nil
+}
+
+func invalidArgument(message string) *Error {
+	return &Error{Code: types.ErrorCodeInvalidArgument, Message: message}
+}
+
+func notFound(id int) *Error {
+	return &Error{Code: types.ErrorCodeNotFound, Message: fmt.Sprintf("Argument 'id', value %d not found!", id)}
+}
+
+// overlay decodes config onto a deep copy of current so that only the attributes present in config are
+// changed and current is left untouched if config is invalid
+func overlay(current interface{}, config json.RawMessage, target interface{}) *Error {
+
+	if len(config) == 0 {
+		return invalidArgument("Missing required argument 'config'!")
+	}
+
+	b, err := json.Marshal(current)
+	if err != nil {
+		return invalidArgument(err.Error())
+	}
+
+	err = json.Unmarshal(b, target)
+	if err != nil {
+		return invalidArgument(err.Error())
+	}
+
+	err = json.Unmarshal(config, target)
+	if err != nil {
+		return invalidArgument(err.Error())
+	}
+
+	return nil
+}
+
+// redact removes write only attributes like pass from a marshalled config
+func redact(v interface{}) interface{} {
+
+	b, _ := json.Marshal(v)
+
+	var m interface{}
+	json.Unmarshal(b, &m)
+
+	var walk func(interface{})
+	walk = func(v interface{}) {
+		if m, ok := v.(map[string]interface{}); ok {
+			delete(m, "pass")
+			for _, child := range m {
+				walk(child)
+			}
+		}
+	}
+
+	walk(m)
+	return m
+}
+
+func (t *Device) configChanged() []string {
+	t.cfgRev++
+	return []string{"sys"}
+}
+
+func setConfigResult() map[string]interface{} {
+	return map[string]interface{}{"restart_required": false}
+}
+
+// restartRequiredResult marks the device as requiring a restart until the next reboot
+func (t *Device) restartRequiredResult() map[string]interface{} {
+	t.restartRequired = true
+	return map[string]interface{}{"restart_required": true}
+}
+
+func (t *Device) shellyGetDeviceInfo(p *params) (interface{}, []string, func(), *Error) {
+
+	gen := float32(2)
+	fwID := t.fwID()
+
+	device := t.sys.Device
+	if device == nil {
+		device = &SystemDevice{}
+	}
+
+	info := &DeviceInfo{
+		Name:         device.Name,
+		ID:           &t.id,
+		MAC:          &t.mac,
+		Model:        &t.model.Model,
+		Generation:   &gen,
+		FirmwareID:   &fwID,
+		Version:      &t.version,
+		App:          &t.model.App,
+		AuthEnabled:  t.ha1 != "",
+		Discoverable: device.Discoverable != nil && *device.Discoverable,
+	}
+
+	if t.ha1 != "" {
+		info.AuthDomain = &t.id
+	}
+
+	return info, nil, nil, nil
+}
+
+func (t *Device) shellyGetStatus(p *params) (interface{}, []string, func(), *Error) {
+
+	result := map[string]interface{}{
+		"sys":  t.sysStatus(),
+		"wifi": t.wifiStatus(),
+		"mqtt": t.mqttStatus(),
+	}
+
+	for i := range t.switches {
+		result[fmt.Sprintf("switch:%d", i)] = t.switchStatus(i)
+	}
+
+	for i := range t.lights {
+		result[fmt.Sprintf("light:%d", i)] = t.lightStatus(i)
+	}
+
+	for i := range t.inputs {
+		result[fmt.Sprintf("input:%d", i)] = t.inputStatus(i)
+	}
+
+	return result, nil, nil, nil
+}
+
+func (t *Device) shellyGetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	result := map[string]interface{}{
+		"sys":  t.sys,
+		"wifi": redact(t.wifi),
+		"mqtt": redact(t.mqtt),
+	}
+
+	for i, v := range t.switches {
+		result[fmt.Sprintf("switch:%d", i)] = v.config
+	}
+
+	for i, v := range t.lights {
+		result[fmt.Sprintf("light:%d", i)] = v.config
+	}
+
+	for i, v := range t.inputs {
+		result[fmt.Sprintf("input:%d", i)] = v.config
+	}
+
+	return result, nil, nil, nil
+}
+
+func (t *Device) shellyListMethods(p *params) (interface{}, []string, func(), *Error) {
+
+	var methods []string
+	for method := range handlers {
+		if t.hasMethod(method) {
+			methods = append(methods, method)
+		}
+	}
+
+	sort.Strings(methods)
+
+	return map[string]interface{}{"methods": methods}, nil, nil, nil
+}
+
+func (t *Device) availableUpdates() *SystemAvailableUpdates {
+
+	updates := &SystemAvailableUpdates{}
+
+	if t.stable != "" && t.stable != t.version {
+		stable := t.stable
+		updates.Stable = &FirmwareStatus{Version: &stable}
+	}
+
+	if t.beta != "" && t.beta != t.version {
+		beta := t.beta
+		updates.Beta = &FirmwareStatus{Version: &beta}
+	}
+
+	return updates
+}
+
+func (t *Device) shellyCheckForUpdate(p *params) (interface{}, []string, func(), *Error) {
+	return t.availableUpdates(), nil, nil, nil
+}
+
+// shellyUpdate sends the ota events and reboots into the new version. If url is set the version is
+// taken from the file name of the url, for example .../1.0.3.zip. With otaError set the update fails
+// before the reboot and with otaRollback set the device reboots into the old version.
+func (t *Device) shellyUpdate(p *params) (interface{}, []string, func(), *Error) {
+
+	var version string
+
+	switch {
+
+	case p.URL != "":
+		version = strings.TrimSuffix(path.Base(p.URL), path.Ext(p.URL))
+
+	case p.Stage == "beta":
+		version = t.beta
+
+	case p.Stage == "" || p.Stage == "stable":
+		version = t.stable
+
+	default:
+		return nil, nil, nil, invalidArgument(fmt.Sprintf("Invalid stage '%s'", p.Stage))
+	}
+
+	if version == "" || version == t.version {
+		return nil, nil, nil, &Error{Code: types.ErrorCodeUnAvailable, Message: "No update info!"}
+	}
+
+	after := func() {
+
+		for _, progress := range []int{0, 50, 100} {
+			time.Sleep(otaStepDelay)
+			event := "ota_progress"
+			if progress == 0 {
+				event = "ota_begin"
+			}
+			t.notifyEvent(map[string]interface{}{
+				"component":        "sys",
+				"event":            event,
+				"msg":              "Update in progress",
+				"progress_percent": progress,
+			})
+		}
+
+		if t.otaError != "" {
+			t.notifyEvent(map[string]interface{}{
+				"component": "sys",
+				"event":     "ota_error",
+				"msg":       t.otaError,
+			})
+			return
+		}
+
+		t.notifyEvent(map[string]interface{}{
+			"component": "sys",
+			"event":     "ota_success",
+			"msg":       "Update applied, rebooting",
+		})
+
+		time.Sleep(rebootDelay)
+
+		if t.otaRollback {
+			t.Reboot()
+			return
+		}
+
+		t.mutex.Lock()
+		t.version = version
+		if t.sys.Device != nil {
+			fwID := t.fwID()
+			t.sys.Device.FwID = &fwID
+		}
+		t.mutex.Unlock()
+
+		t.Reboot()
+	}
+
+	return nil, nil, after, nil
+}
+
+func (t *Device) rebootAfterDelay() {
+	time.Sleep(rebootDelay)
+	t.Reboot()
+}
+
+func (t *Device) shellyReboot(p *params) (interface{}, []string, func(), *Error) {
+	return nil, nil, t.rebootAfterDelay, nil
+}
+
+func (t *Device) shellyFactoryReset(p *params) (interface{}, []string, func(), *Error) {
+	t.ha1 = ""
+	t.cfgRev = 0
+	t.userCA = ""
+	t.tlsClientCert = ""
+	t.tlsClientKey = ""
+	t.reset()
+	return nil, nil, t.rebootAfterDelay, nil
+}
+
+func (t *Device) shellyResetWiFiConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	isOpen := true
+	apSSID := t.id
+
+	t.wifi = &WifiConfig{
+		Ap: &WifiAPConfig{
+			SSID:   &apSSID,
+			IsOpen: &isOpen,
+			Enable: true,
+		},
+		Sta:  &WifiSTAConfig{},
+		Sta1: &WifiSTAConfig{},
+	}
+
+	return nil, t.configChanged(), t.rebootAfterDelay, nil
+}
+
+func (t *Device) shellySetAuth(p *params) (interface{}, []string, func(), *Error) {
+
+	if p.Ha1 == nil || *p.Ha1 == "" {
+		t.ha1 = ""
+		return nil, t.configChanged(), nil, nil
+	}
+
+	if p.User != types.ShellyUser {
+		return nil, nil, nil, invalidArgument(fmt.Sprintf("Invalid user '%s'", p.User))
+	}
+
+	if p.Realm != t.id {
+		return nil, nil, nil, invalidArgument(fmt.Sprintf("Invalid realm '%s'", p.Realm))
+	}
+
+	t.ha1 = *p.Ha1
+	return nil, t.configChanged(), nil, nil
+}
+
+// putData stores data uploaded in chunks. The data is removed if it is null and append is false.
+func putData(current *string, p *params) (interface{}, []string, func(), *Error) {
+
+	switch {
+	case p.Data == nil && !p.Append:
+		*current = ""
+	case p.Data == nil:
+		return nil, nil, nil, invalidArgument("Missing required argument 'data'!")
+	case p.Append:
+		*current += *p.Data
+	default:
+		*current = *p.Data
+	}
+
+	return map[string]interface{}{"len": len(*current)}, nil, nil, nil
+}
+
+func (t *Device) shellyPutUserCA(p *params) (interface{}, []string, func(), *Error) {
+	return putData(&t.userCA, p)
+}
+
+func (t *Device) shellyPutTLSClientCert(p *params) (interface{}, []string, func(), *Error) {
+	return putData(&t.tlsClientCert, p)
+}
+
+func (t *Device) shellyPutTLSClientKey(p *params) (interface{}, []string, func(), *Error) {
+	return putData(&t.tlsClientKey, p)
+}
+
+func (t *Device) sysStatus() *SystemStatus {
+
+	now := time.Now()
+	restartRequired := t.restartRequired
+	clock := now.Format("15:04")
+	unixtime := float64(now.Unix())
+	uptime := float64(int(now.Sub(t.booted).Seconds()))
+	ramSize := float64(246000)
+	ramFree := float64(150000)
+	fsSize := float64(458752)
+	fsFree := float64(184320)
+	cfgRev := float64(t.cfgRev)
+	zero := float64(0)
+
+	return &SystemStatus{
+		MAC:              &t.mac,
+		RestartRequired:  &restartRequired,
+		Time:             &clock,
+		Unixtime:         &unixtime,
+		Uptime:           &uptime,
+		RAMSize:          &ramSize,
+		RAMFree:          &ramFree,
+		FsSize:           &fsSize,
+		FsFree:           &fsFree,
+		CfgRev:           &cfgRev,
+		KvsRev:           &zero,
+		ScheduleRev:      &zero,
+		WebhookRev:       &zero,
+		AvailableUpdates: t.availableUpdates(),
+	}
+}
+
+func (t *Device) sysGetStatus(p *params) (interface{}, []string, func(), *Error) {
+	return t.sysStatus(), nil, nil, nil
+}
+
+func (t *Device) sysGetConfig(p *params) (interface{}, []string, func(), *Error) {
+	return t.sys, nil, nil, nil
+}
+
+func (t *Device) sysSetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	config := &SystemConfig{}
+
+	rpcErr := overlay(t.sys, p.Config, config)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	// Read only attributes
+	if config.Device != nil {
+		fwID := t.fwID()
+		config.Device.MAC = &t.mac
+		config.Device.FwID = &fwID
+	}
+
+	t.sys = config
+	return setConfigResult(), t.configChanged(), nil, nil
+}
+
+func (t *Device) wifiStatus() *WifiStatus {
+
+	status := &WifiStatus{
+		Status: "disconnected",
+	}
+
+	if t.wifi.Sta != nil && t.wifi.Sta.Enable && t.wifi.Sta.SSID != nil {
+		ip := "127.0.0.1"
+		rssi := -60
+		status.Status = "got ip"
+		status.StaIP = &ip
+		status.SSID = t.wifi.Sta.SSID
+		status.RSSI = &rssi
+	}
+
+	return status
+}
+
+func (t *Device) wifiGetStatus(p *params) (interface{}, []string, func(), *Error) {
+	return t.wifiStatus(), nil, nil, nil
+}
+
+func (t *Device) wifiGetConfig(p *params) (interface{}, []string, func(), *Error) {
+	return redact(t.wifi), nil, nil, nil
+}
+
+func (t *Device) wifiSetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	config := &WifiConfig{}
+
+	rpcErr := overlay(t.wifi, p.Config, config)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	if config.Ap != nil {
+		apSSID := t.id
+		config.Ap.SSID = &apSSID
+	}
+
+	t.wifi = config
+	return setConfigResult(), append(t.configChanged(), "wifi"), nil, nil
+}
+
+func (t *Device) wifiScan(p *params) (interface{}, []string, func(), *Error) {
+	return map[string]interface{}{
+		"results": []interface{}{
+			map[string]interface{}{"ssid": "simulated", "bssid": "00:00:5e:00:53:01", "auth": 3, "channel": 6, "rssi": -60},
+		},
+	}, nil, nil, nil
+}
+
+func (t *Device) wifiListAPClients(p *params) (interface{}, []string, func(), *Error) {
+	return map[string]interface{}{
+		"ts":         time.Now().Unix(),
+		"ap_clients": []interface{}{},
+	}, nil, nil, nil
+}
+
+func (t *Device) mqttStatus() *MqttStatus {
+	return &MqttStatus{
+		Connected: t.mqtt.Enable && t.mqtt.Server != nil,
+	}
+}
+
+func (t *Device) mqttGetStatus(p *params) (interface{}, []string, func(), *Error) {
+	return t.mqttStatus(), nil, nil, nil
+}
+
+func (t *Device) mqttGetConfig(p *params) (interface{}, []string, func(), *Error) {
+	return redact(t.mqtt), nil, nil, nil
+}
+
+func (t *Device) mqttSetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	config := &MqttConfig{}
+
+	rpcErr := overlay(t.mqtt, p.Config, config)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	// Like the real firmware the MQTT connection is only set up again after a reboot
+	t.mqtt = config
+	return t.restartRequiredResult(), append(t.configChanged(), "mqtt"), nil, nil
+}
+
+// aenergy returns the energy in Wh consumed by the switch up to now
+func (t *simSwitch) aenergy(load float64, now time.Time) float64 {
+
+	if !t.output {
+		return t.energy
+	}
+
+	return t.energy + load*now.Sub(t.onSince).Hours()
+}
+
+func (t *simSwitch) set(on bool) {
+
+	now := time.Now()
+
+	if t.output && !on {
+		t.energy = t.aenergy(0, now)
+	}
+
+	if on && !t.output {
+		t.onSince = now
+	}
+
+	t.output = on
+}
+
+func (t *Device) switchStatus(id int) *SwitchStatus {
+
+	s := t.switches[id]
+	source := "init"
+
+	status := &SwitchStatus{
+		ID:     id,
+		Source: &source,
+		Output: s.output,
+	}
+
+	if !t.model.PowerMetering {
+		return status
+	}
+
+	apower := float64(0)
+	if s.output {
+		apower = t.load
+	}
+
+	voltage := defaultVoltage
+	current := apower / voltage
+	pf := float64(1)
+	total := s.aenergy(t.load, time.Now())
+	minuteTs := int(time.Now().Unix())
+	tC := defaultTemperature
+	if s.output {
+		tC += 5
+	}
+	tF := tC*9/5 + 32
+
+	status.Apower = &apower
+	status.Voltage = &voltage
+	status.Current = &current
+	status.PowerFactor = &pf
+	status.Aenergy = &SwitchAenergy{
+		Total:    &total,
+		ByMinute: []float64{0, 0, 0},
+		MinuteTs: &minuteTs,
+	}
+	status.Temperature = &SwitchTemperature{
+		TC: &tC,
+		TF: &tF,
+	}
+
+	return status
+}
+
+func (t *Device) switchByID(p *params) (*simSwitch, int, *Error) {
+
+	if p.ID == nil {
+		return nil, 0, invalidArgument("Missing required argument 'id'!")
+	}
+
+	if *p.ID < 0 || *p.ID >= len(t.switches) {
+		return nil, 0, notFound(*p.ID)
+	}
+
+	return t.switches[*p.ID], *p.ID, nil
+}
+
+func (t *Device) switchGetStatus(p *params) (interface{}, []string, func(), *Error) {
+
+	_, id, rpcErr := t.switchByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	return t.switchStatus(id), nil, nil, nil
+}
+
+func (t *Device) switchGetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	s, _, rpcErr := t.switchByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	return s.config, nil, nil, nil
+}
+
+func (t *Device) switchSetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	s, id, rpcErr := t.switchByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	config := &SwitchConfig{}
+
+	rpcErr = overlay(s.config, p.Config, config)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	// Like the real firmware values out of range are clamped rather than rejected
+	if config.PowerLimit != nil && *config.PowerLimit > maxPowerLimit {
+		powerLimit := maxPowerLimit
+		config.PowerLimit = &powerLimit
+	}
+
+	config.ID = id
+	s.config = config
+
+	return setConfigResult(), t.configChanged(), nil, nil
+}
+
+func (t *Device) switchSet(p *params) (interface{}, []string, func(), *Error) {
+
+	s, id, rpcErr := t.switchByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	if p.On == nil {
+		return nil, nil, nil, invalidArgument("Missing required argument 'on'!")
+	}
+
+	wasOn := s.output
+	s.set(*p.On)
+
+	return map[string]interface{}{"was_on": wasOn}, []string{fmt.Sprintf("switch:%d", id)}, nil, nil
+}
+
+func (t *Device) switchToggle(p *params) (interface{}, []string, func(), *Error) {
+
+	s, id, rpcErr := t.switchByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	wasOn := s.output
+	s.set(!wasOn)
+
+	return map[string]interface{}{"was_on": wasOn}, []string{fmt.Sprintf("switch:%d", id)}, nil, nil
+}
+
+func (t *Device) lightStatus(id int) *LightStatus {
+
+	l := t.lights[id]
+	brightness := l.brightness
+
+	return &LightStatus{
+		ID:         id,
+		Source:     "init",
+		Output:     l.output,
+		Brightness: &brightness,
+	}
+}
+
+func (t *Device) lightByID(p *params) (*simLight, int, *Error) {
+
+	if p.ID == nil {
+		return nil, 0, invalidArgument("Missing required argument 'id'!")
+	}
+
+	if *p.ID < 0 || *p.ID >= len(t.lights) {
+		return nil, 0, notFound(*p.ID)
+	}
+
+	return t.lights[*p.ID], *p.ID, nil
+}
+
+func (t *Device) lightGetStatus(p *params) (interface{}, []string, func(), *Error) {
+
+	_, id, rpcErr := t.lightByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	return t.lightStatus(id), nil, nil, nil
+}
+
+func (t *Device) lightGetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	l, _, rpcErr := t.lightByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	return l.config, nil, nil, nil
+}
+
+func (t *Device) lightSetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	l, id, rpcErr := t.lightByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	config := &LightConfig{}
+
+	rpcErr = overlay(l.config, p.Config, config)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	config.ID = id
+	l.config = config
+
+	return setConfigResult(), t.configChanged(), nil, nil
+}
+
+func (t *Device) lightSet(p *params) (interface{}, []string, func(), *Error) {
+
+	l, id, rpcErr := t.lightByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	if p.On == nil && p.Brightness == nil {
+		return nil, nil, nil, invalidArgument("At least one of 'on' and 'brightness' is required!")
+	}
+
+	if p.Brightness != nil {
+		if *p.Brightness < 0 || *p.Brightness > 100 {
+			return nil, nil, nil, invalidArgument("Argument 'brightness' must be between 0 and 100!")
+		}
+		l.brightness = *p.Brightness
+	}
+
+	wasOn := l.output
+	if p.On != nil {
+		l.output = *p.On
+	}
+
+	return map[string]interface{}{"was_on": wasOn}, []string{fmt.Sprintf("light:%d", id)}, nil, nil
+}
+
+func (t *Device) lightToggle(p *params) (interface{}, []string, func(), *Error) {
+
+	l, id, rpcErr := t.lightByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	wasOn := l.output
+	l.output = !wasOn
+
+	return map[string]interface{}{"was_on": wasOn}, []string{fmt.Sprintf("light:%d", id)}, nil, nil
+}
+
+func (t *Device) inputStatus(id int) *InputStatus {
+
+	i := t.inputs[id]
+	state := i.state
+
+	if i.config.Invert != nil && *i.config.Invert {
+		state = !state
+	}
+
+	return &InputStatus{
+		ID:    &id,
+		State: &state,
+	}
+}
+
+func (t *Device) inputByID(p *params) (*simInput, int, *Error) {
+
+	if p.ID == nil {
+		return nil, 0, invalidArgument("Missing required argument 'id'!")
+	}
+
+	if *p.ID < 0 || *p.ID >= len(t.inputs) {
+		return nil, 0, notFound(*p.ID)
+	}
+
+	return t.inputs[*p.ID], *p.ID, nil
+}
+
+func (t *Device) inputGetStatus(p *params) (interface{}, []string, func(), *Error) {
+
+	_, id, rpcErr := t.inputByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	return t.inputStatus(id), nil, nil, nil
+}
+
+func (t *Device) inputGetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	i, _, rpcErr := t.inputByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	return i.config, nil, nil, nil
+}
+
+func (t *Device) inputSetConfig(p *params) (interface{}, []string, func(), *Error) {
+
+	i, id, rpcErr := t.inputByID(p)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	config := &InputConfig{}
+
+	rpcErr = overlay(i.config, p.Config, config)
+	if rpcErr != nil {
+		return nil, nil, nil, rpcErr
+	}
+
+	config.ID = id
+	i.config = config
+
+	return setConfigResult(), append(t.configChanged(), fmt.Sprintf("input:%d", id)), nil, nil
+}
+
+func sha256Hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
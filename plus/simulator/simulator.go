@@ -0,0 +1,693 @@
+package simulator
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Config config for a simulated device
+type Config struct {
+	// Model to mimic. Default is ModelPlus1PM
+	Model *Model
+	// ID of the device, also used as auth realm. Default is <app>-<mac> in lower case
+	ID string
+	// MAC address of the device. Default is random
+	MAC string
+	// Version of the firmware. Default is 1.0.0
+	Version string
+	// Password if set auth is enabled with user admin
+	Password string
+	// Addr address to listen on. Default is 127.0.0.1:0 (a free port)
+	Addr string
+	// Load power in watts drawn by each switch when it is on. Default is 100
+	Load float64
+	// StableUpdate stable version offered by Shelly.CheckForUpdate. Optional
+	StableUpdate string
+	// BetaUpdate beta version offered by Shelly.CheckForUpdate. Optional
+	BetaUpdate string
+	// NonceTTL time a nonce is accepted after it was issued. Default is 60s
+	NonceTTL time.Duration
+	// OTAError if set Shelly.Update fails with an ota_error event with this message and the device
+	// keeps its version. Optional
+	OTAError string
+	// OTARollback if true Shelly.Update reboots the device but it comes back with the old version
+	OTARollback bool
+}
+
+// Device is an in-process Shelly Gen2 device. It serves /rpc over WebSocket and HTTP, keeps the state
+// of its components in memory, enforces digest auth if a password is set and sends NotifyStatus to
+// connected WebSocket clients when the state of a component changes.
+type Device struct {
+	mutex    sync.Mutex
+	model    *Model
+	id       string
+	mac      string
+	version  string
+	ha1      string
+	nonces   map[int64]time.Time
+	nonceTTL time.Duration
+	booted   time.Time
+	cfgRev   int
+	load     float64
+	stable   string
+	beta     string
+	sys      *SystemConfig
+	wifi     *WifiConfig
+	mqtt     *MqttConfig
+	switches []*simSwitch
+	lights   []*simLight
+	inputs   []*simInput
+
+	// restartRequired is set by a config change that only takes effect after a reboot
+	restartRequired bool
+
+	// otaError and otaRollback make Shelly.Update fail
+	otaError    string
+	otaRollback bool
+
+	userCA        string
+	tlsClientCert string
+	tlsClientKey  string
+
+	addr     string
+	listener net.Listener
+	server   *http.Server
+	conns    map[*conn]bool
+}
+
+type simSwitch struct {
+	config  *SwitchConfig
+	output  bool
+	energy  float64
+	onSince time.Time
+}
+
+type simLight struct {
+	config     *LightConfig
+	output     bool
+	brightness float64
+}
+
+type simInput struct {
+	config *InputConfig
+	state  bool
+}
+
+// conn a WebSocket client connection
+type conn struct {
+	mutex sync.Mutex
+	ws    *gorilla.Conn
+	src   string
+}
+
+func (t *conn) write(v interface{}) error {
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.ws.WriteMessage(gorilla.TextMessage, b)
+}
+
+// New returns a new device. It does not listen until Start is called.
+func New(config *Config) *Device {
+
+	if config == nil {
+		config = &Config{}
+	}
+
+	model := config.Model
+	if model == nil {
+		model = ModelPlus1PM
+	}
+
+	mac := strings.ToUpper(config.MAC)
+	if mac == "" {
+		b := make([]byte, 6)
+		rand.Read(b)
+		mac = strings.ToUpper(hex.EncodeToString(b))
+	}
+
+	id := config.ID
+	if id == "" {
+		id = strings.ToLower("shelly" + model.App + "-" + mac)
+	}
+
+	version := config.Version
+	if version == "" {
+		version = defaultVersion
+	}
+
+	load := config.Load
+	if load <= 0 {
+		load = defaultLoad
+	}
+
+	addr := config.Addr
+	if addr == "" {
+		addr = defaultAddr
+	}
+
+	nonceTTL := config.NonceTTL
+	if nonceTTL <= 0 {
+		nonceTTL = defaultNonceTTL
+	}
+
+	t := &Device{
+		model:    model,
+		id:       id,
+		mac:      mac,
+		version:  version,
+		nonces:   map[int64]time.Time{},
+		nonceTTL: nonceTTL,
+		booted:   time.Now(),
+		load:     load,
+		stable:   config.StableUpdate,
+		beta:     config.BetaUpdate,
+		addr:     addr,
+		conns:    map[*conn]bool{},
+	}
+
+	t.otaError = config.OTAError
+	t.otaRollback = config.OTARollback
+
+	if config.Password != "" {
+		t.ha1 = sha256Hex(types.ShellyUser + ":" + id + ":" + config.Password)
+	}
+
+	t.reset()
+
+	return t
+}
+
+// reset sets the config and state of every component to its factory default
+func (t *Device) reset() {
+
+	name := t.model.Name
+	ecoMode := false
+	discoverable := true
+
+	t.sys = &SystemConfig{
+		Device: &SystemDevice{
+			Name:         &name,
+			EcoMode:      &ecoMode,
+			MAC:          &t.mac,
+			FwID:         stringPtr(t.fwID()),
+			Discoverable: &discoverable,
+		},
+	}
+
+	apSSID := t.id
+	isOpen := true
+	t.wifi = &WifiConfig{
+		Ap: &WifiAPConfig{
+			SSID:   &apSSID,
+			IsOpen: &isOpen,
+			Enable: true,
+		},
+		Sta:  &WifiSTAConfig{},
+		Sta1: &WifiSTAConfig{},
+	}
+
+	t.mqtt = &MqttConfig{}
+
+	t.switches = nil
+	for i := 0; i < t.model.Switches; i++ {
+		t.switches = append(t.switches, &simSwitch{
+			config: &SwitchConfig{ID: i, Name: nil},
+		})
+	}
+
+	t.lights = nil
+	for i := 0; i < t.model.Lights; i++ {
+		t.lights = append(t.lights, &simLight{
+			config:     &LightConfig{ID: i},
+			brightness: 100,
+		})
+	}
+
+	t.inputs = nil
+	for i := 0; i < t.model.Inputs; i++ {
+		inputType := "switch"
+		invert := false
+		t.inputs = append(t.inputs, &simInput{
+			config: &InputConfig{ID: i, Type: &inputType, Invert: &invert},
+		})
+	}
+}
+
+func (t *Device) fwID() string {
+	return t.booted.UTC().Format("20060102-150405") + "/" + t.version
+}
+
+// ID returns the id of the device
+func (t *Device) ID() string {
+	return t.id
+}
+
+// Start starts listening
+func (t *Device) Start() error {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.listener != nil {
+		return fmt.Errorf("device is already started")
+	}
+
+	listener, err := net.Listen("tcp", t.addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rpc", t.serveRPC)
+	mux.HandleFunc("/rpc/", t.serveRPC)
+
+	t.listener = listener
+	t.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: time.Duration(10) * time.Second,
+	}
+
+	go func(server *http.Server) {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.L().Error(fmt.Sprintf("simulator stopped: %v", err))
+		}
+	}(t.server)
+
+	zap.L().Debug(fmt.Sprintf("simulated %s %s listening on %s", t.model.Name, t.id, listener.Addr()))
+
+	return nil
+}
+
+// Hostname returns host:port of the device for use as hostname in the client config. It is empty if
+// the device is not started.
+func (t *Device) Hostname() string {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.listener == nil {
+		return ""
+	}
+
+	return t.listener.Addr().String()
+}
+
+// Close stops the device and closes all connections
+func (t *Device) Close() error {
+
+	t.dropConnections()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.server == nil {
+		return nil
+	}
+
+	err := t.server.Close()
+	t.server = nil
+	t.listener = nil
+	return err
+}
+
+// SetInput sets the state of an input as if it was switched externally and notifies the clients
+func (t *Device) SetInput(id int, state bool) error {
+
+	t.mutex.Lock()
+
+	if id < 0 || id >= len(t.inputs) {
+		t.mutex.Unlock()
+		return fmt.Errorf("input %d does not exist", id)
+	}
+
+	t.inputs[id].state = state
+	changed := t.snapshot([]string{fmt.Sprintf("input:%d", id)})
+
+	t.mutex.Unlock()
+
+	t.notifyStatus(changed)
+	return nil
+}
+
+// Reboot simulates a reboot: all connections are dropped and the uptime starts over
+func (t *Device) Reboot() {
+
+	t.dropConnections()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.booted = time.Now()
+	t.restartRequired = false
+
+	for _, v := range t.switches {
+		v.energy = v.aenergy(t.load, time.Now())
+		v.output = false
+	}
+
+	for _, v := range t.lights {
+		v.output = false
+	}
+}
+
+func (t *Device) dropConnections() {
+
+	t.mutex.Lock()
+	var conns []*conn
+	for c := range t.conns {
+		conns = append(conns, c)
+	}
+	t.conns = map[*conn]bool{}
+	t.mutex.Unlock()
+
+	for _, c := range conns {
+		c.ws.Close()
+	}
+}
+
+var upgrader = gorilla.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func (t *Device) serveRPC(w http.ResponseWriter, r *http.Request) {
+
+	if gorilla.IsWebSocketUpgrade(r) {
+		t.serveWebsocket(w, r)
+		return
+	}
+
+	t.serveHTTP(w, r)
+}
+
+func (t *Device) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := &conn{ws: ws}
+
+	t.mutex.Lock()
+	t.conns[c] = true
+	t.mutex.Unlock()
+
+	defer func() {
+		t.mutex.Lock()
+		delete(t.conns, c)
+		t.mutex.Unlock()
+		ws.Close()
+	}()
+
+	for {
+
+		_, b, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		req := &request{}
+		err = json.Unmarshal(b, req)
+		if err != nil {
+			c.write(&response{Src: t.id, Error: &Error{Code: types.ErrorCodeInvalidArgument, Message: err.Error()}})
+			continue
+		}
+
+		if req.Src != "" {
+			c.mutex.Lock()
+			c.src = req.Src
+			c.mutex.Unlock()
+		}
+
+		resp := t.handle(req, t.checkAuth(req.Method, req.Auth))
+		resp.Dst = req.Src
+
+		err = c.write(resp)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// serveHTTP serves POST /rpc with a JSON-RPC frame and GET /rpc/<method>?<params>. Auth is taken from
+// the frame or from an HTTP digest Authorization header.
+func (t *Device) serveHTTP(w http.ResponseWriter, r *http.Request) {
+
+	req := &request{}
+
+	switch r.Method {
+
+	case http.MethodPost:
+		err := json.NewDecoder(r.Body).Decode(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+	case http.MethodGet:
+		req.Method = strings.TrimPrefix(r.URL.Path, "/rpc/")
+		params := map[string]interface{}{}
+		for k, v := range r.URL.Query() {
+			var value interface{}
+			if json.Unmarshal([]byte(v[0]), &value) != nil {
+				value = v[0]
+			}
+			params[k] = value
+		}
+		if len(params) > 0 {
+			req.Params, _ = json.Marshal(params)
+		}
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authorized := t.checkAuth(req.Method, req.Auth) || t.checkHTTPDigest(r)
+	if !authorized {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Digest qop="auth", realm="%s", nonce="%d", algorithm=%s`, t.id, t.newNonce(), authAlgorithm))
+	}
+
+	resp := t.handle(req, authorized)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if resp.Error != nil && resp.Error.Code == types.ErrorCodeUnauthorized {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	if r.Method == http.MethodGet {
+		// GET returns the bare result like the real firmware
+		if resp.Error != nil {
+			json.NewEncoder(w).Encode(resp.Error)
+			return
+		}
+		json.NewEncoder(w).Encode(resp.Result)
+		return
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handle dispatches the request and sends NotifyStatus for the components it changed
+func (t *Device) handle(req *request, authorized bool) *response {
+
+	resp := &response{
+		ID:  req.ID,
+		Src: t.id,
+	}
+
+	if !authorized {
+		b, _ := json.Marshal(&authChallenge{
+			AuthType:   authType,
+			Nonce:      t.newNonce(),
+			NonceCount: 1,
+			Realm:      t.id,
+			Algorithm:  authAlgorithm,
+		})
+		resp.Error = &Error{Code: types.ErrorCodeUnauthorized, Message: string(b)}
+		return resp
+	}
+
+	t.mutex.Lock()
+	result, changed, after, err := t.call(req.Method, req.Params)
+	t.mutex.Unlock()
+
+	if err != nil {
+		resp.Error = err
+		return resp
+	}
+
+	if string(result) != "null" {
+		resp.Result = result
+	}
+
+	if len(changed) > 0 {
+		go t.notifyStatus(changed)
+	}
+
+	if after != nil {
+		go after()
+	}
+
+	return resp
+}
+
+// checkAuth returns true if auth is disabled, the method does not require auth or auth is valid
+func (t *Device) checkAuth(method string, auth *AuthResponse) bool {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.ha1 == "" || strings.EqualFold(method, "Shelly.GetDeviceInfo") {
+		return true
+	}
+
+	if auth == nil || auth.Realm != t.id || auth.Username != types.ShellyUser || auth.Algorithm != authAlgorithm {
+		return false
+	}
+
+	var nonce int64
+	_, err := fmt.Sscanf(auth.Nonce, "%d", &nonce)
+	if err != nil || !t.validNonce(nonce) {
+		return false
+	}
+
+	expected := sha256Hex(fmt.Sprintf("%s:%d:%d:%s:%s:%s", t.ha1, nonce, 1, auth.Cnonce, "auth", dummyHA2))
+
+	return auth.Response == expected
+}
+
+// checkHTTPDigest validates an RFC 7616 digest Authorization header with SHA-256
+func (t *Device) checkHTTPDigest(r *http.Request) bool {
+
+	header, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Digest ")
+	if !ok {
+		return false
+	}
+
+	fields := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		fields[k] = strings.Trim(v, `"`)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var nonce int64
+	_, err := fmt.Sscanf(fields["nonce"], "%d", &nonce)
+	if err != nil || !t.validNonce(nonce) || fields["realm"] != t.id || fields["username"] != types.ShellyUser {
+		return false
+	}
+
+	ha2 := sha256Hex(r.Method + ":" + fields["uri"])
+	expected := sha256Hex(strings.Join([]string{t.ha1, fields["nonce"], fields["nc"], fields["cnonce"], fields["qop"], ha2}, ":"))
+
+	return fields["response"] == expected
+}
+
+func (t *Device) newNonce() int64 {
+
+	b := make([]byte, 4)
+	rand.Read(b)
+	nonce := time.Now().Unix() + int64(binary.BigEndian.Uint32(b)%1000)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+
+	for k, issued := range t.nonces {
+		if now.Sub(issued) > t.nonceTTL {
+			delete(t.nonces, k)
+		}
+	}
+
+	t.nonces[nonce] = now
+
+	return nonce
+}
+
+// validNonce returns true if the nonce was issued and has not expired. The caller must hold the mutex.
+func (t *Device) validNonce(nonce int64) bool {
+	issued, ok := t.nonces[nonce]
+	return ok && time.Since(issued) <= t.nonceTTL
+}
+
+// notifyStatus sends NotifyStatus with the changed components to every WebSocket client
+func (t *Device) notifyStatus(changed map[string]json.RawMessage) {
+
+	params := map[string]interface{}{
+		"ts": float64(time.Now().UnixNano()) / 1e9,
+	}
+
+	for k, v := range changed {
+		params[k] = v
+	}
+
+	t.broadcast("NotifyStatus", params)
+}
+
+// notifyEvent sends NotifyEvent with a single event to every WebSocket client
+func (t *Device) notifyEvent(event map[string]interface{}) {
+
+	ts := float64(time.Now().UnixNano()) / 1e9
+	event["ts"] = ts
+
+	t.broadcast("NotifyEvent", map[string]interface{}{
+		"ts":     ts,
+		"events": []interface{}{event},
+	})
+}
+
+func (t *Device) broadcast(method string, params interface{}) {
+
+	t.mutex.Lock()
+	var conns []*conn
+	for c := range t.conns {
+		conns = append(conns, c)
+	}
+	t.mutex.Unlock()
+
+	for _, c := range conns {
+
+		c.mutex.Lock()
+		dst := c.src
+		c.mutex.Unlock()
+
+		// Like the real firmware notifications are only sent to clients that identified themselves
+		if dst == "" {
+			continue
+		}
+
+		c.write(&notification{
+			Src:    t.id,
+			Dst:    dst,
+			Method: method,
+			Params: params,
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package simulator
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// challengeMetrics counts the auth challenges
+type challengeMetrics struct {
+	types.NopMetrics
+	challenges int32
+}
+
+func (t *challengeMetrics) AuthChallenge(hostname string) {
+	atomic.AddInt32(&t.challenges, 1)
+}
+
+func TestNonceTTL(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		ttl        time.Duration
+		challenges int32
+	}{
+		{name: "valid", ttl: time.Minute, challenges: 1},
+		{name: "expired", ttl: 50 * time.Millisecond, challenges: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			device := New(&Config{Password: "secret", NonceTTL: tt.ttl})
+			err := device.Start()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer device.Close()
+
+			metrics := &challengeMetrics{}
+
+			client, err := plus.New(&msghandlers.Config{
+				Hostname: device.Hostname(),
+				Username: types.ShellyUser,
+				Password: "secret",
+				Metrics:  metrics,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			for i := 0; i < 2; i++ {
+
+				if i > 0 {
+					time.Sleep(100 * time.Millisecond)
+				}
+
+				_, err = client.System().GetConfig(ctx)
+				if err != nil {
+					t.Fatalf("request %d: %v", i, err)
+				}
+			}
+
+			if challenges := atomic.LoadInt32(&metrics.challenges); challenges != tt.challenges {
+				t.Errorf("auth challenges = %d, want %d", challenges, tt.challenges)
+			}
+		})
+	}
+}
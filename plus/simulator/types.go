@@ -0,0 +1,81 @@
+package simulator
+
+import (
+	"encoding/json"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type Request = types.Request
+type Error = types.Error
+type AuthResponse = types.AuthResponse
+type DeviceInfo = types.DeviceInfo
+type SystemConfig = types.SystemConfig
+type SystemStatus = types.SystemStatus
+type SystemDevice = types.SystemDevice
+type WifiConfig = types.WifiConfig
+type WifiStatus = types.WifiStatus
+type WifiSTAConfig = types.WifiSTAConfig
+type WifiAPConfig = types.WifiAPConfig
+type MqttConfig = types.MqttConfig
+type MqttStatus = types.MqttStatus
+type SwitchConfig = types.SwitchConfig
+type SwitchStatus = types.SwitchStatus
+type SwitchAenergy = types.SwitchAenergy
+type SwitchTemperature = types.SwitchTemperature
+type LightConfig = types.LightConfig
+type LightStatus = types.LightStatus
+type InputConfig = types.InputConfig
+type InputStatus = types.InputStatus
+type FirmwareStatus = types.FirmwareStatus
+type SystemAvailableUpdates = types.SystemAvailableUpdates
+
+// request JSON-RPC frame received from a client
+type request struct {
+	ID     *int            `json:"id"`
+	Src    string          `json:"src,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Auth   *AuthResponse   `json:"auth,omitempty"`
+}
+
+// response JSON-RPC frame sent to a client
+type response struct {
+	ID     *int        `json:"id"`
+	Src    string      `json:"src"`
+	Dst    string      `json:"dst,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  *Error      `json:"error,omitempty"`
+}
+
+// notification JSON-RPC frame sent to a client without a request
+type notification struct {
+	Src    string      `json:"src"`
+	Dst    string      `json:"dst,omitempty"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params"`
+}
+
+// authChallenge message of the 401 error sent when a request is not authenticated
+type authChallenge struct {
+	AuthType   string `json:"auth_type"`
+	Nonce      int64  `json:"nonce"`
+	NonceCount int    `json:"nc"`
+	Realm      string `json:"realm"`
+	Algorithm  string `json:"algorithm"`
+}
+
+// params union of the params of all the methods the simulator implements
+type params struct {
+	ID         *int            `json:"id"`
+	On         *bool           `json:"on"`
+	Brightness *float64        `json:"brightness"`
+	Config     json.RawMessage `json:"config"`
+	Stage      string          `json:"stage"`
+	URL        string          `json:"url"`
+	User       string          `json:"user"`
+	Realm      string          `json:"realm"`
+	Ha1        *string         `json:"ha1"`
+	Data       *string         `json:"data"`
+	Append     bool            `json:"append"`
+}
@@ -1,5 +0,0 @@
-package switchx
-
-const (
-	Component = "Switch"
-)
@@ -1,5 +0,0 @@
-package system
-
-const (
-	Component = "Sys"
-)
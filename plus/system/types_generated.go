@@ -1,17 +1,23 @@
+// Code generated by gen_components.go; DO NOT EDIT.
+
 package system
 
 import (
 	"github.com/jodydadescott/shelly-go-sdk/plus/types"
 )
 
+const (
+	Component = "Sys"
+)
+
 type Request = types.Request
 type Response = types.Response
 type Error = types.Error
 type MessageHandlerFactory = types.MessageHandlerFactory
 type MessageHandler = types.MessageHandler
 
-type Config = types.SystemConfig
 type Status = types.SystemStatus
+type Config = types.SystemConfig
 type DeviceConfig = types.SystemDevice
 type LocationConfig = types.SystemLocation
 type DebugConfig = types.SystemDebug
@@ -22,10 +28,14 @@ type MqttDebug = types.SystemMqtt
 type WebsocketDebug = types.SystemWebsocket
 type UDP = types.SystemUDP
 
+// Params internal use only
+type Params struct {
+	Config *Config `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
 // Result internal use only
 type Result struct {
-	RestartRequired *bool  `json:"restart_required,omitempty"`
-	Error           *Error `json:"error,omitempty"`
+	RestartRequired *bool `json:"restart_required,omitempty"`
 }
 
 // GetConfigResponse internal use only
@@ -45,8 +55,3 @@ type GetStatusResponse struct {
 	Response
 	Result *Status `json:"result,omitempty"`
 }
-
-// Params internal use only
-type Params struct {
-	Config *Config `json:"config,omitempty"`
-}
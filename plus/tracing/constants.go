@@ -0,0 +1,8 @@
+package tracing
+
+const (
+	Component = "Tracing"
+
+	// instrumentationName is the name of the tracer
+	instrumentationName = "github.com/jodydadescott/shelly-go-sdk/plus/tracing"
+)
@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+// Attribute keys set on the RPC spans. The device hostname, the request ID and whether the request was
+// retried after an auth challenge are set if the message handler reports them with types.SendInfo.
+const (
+	AttributeRPCSystem     = attribute.Key("rpc.system")
+	AttributeRPCService    = attribute.Key("rpc.service")
+	AttributeRPCMethod     = attribute.Key("rpc.method")
+	AttributeServerAddress = attribute.Key("server.address")
+	AttributeRequestID     = attribute.Key("rpc.jsonrpc.request_id")
+	AttributeAuthRetry     = attribute.Key("shelly.auth_retry")
+	AttributeErrorCode     = attribute.Key("rpc.jsonrpc.error_code")
+	AttributeErrorMessage  = attribute.Key("rpc.jsonrpc.error_message")
+)
+
+// Config config for the tracing interceptor
+type Config struct {
+	// TracerProvider provider of the tracer. Optional; by default the global provider is used
+	TracerProvider trace.TracerProvider
+}
+
+// NewInterceptor returns an interceptor that starts a client span per RPC as a child of the span in the
+// caller's context
+func NewInterceptor(config *Config) Interceptor {
+
+	provider := otel.GetTracerProvider()
+	if config != nil && config.TracerProvider != nil {
+		provider = config.TracerProvider
+	}
+
+	tracer := provider.Tracer(instrumentationName)
+
+	return func(ctx context.Context, request *Request, next SendFunc) ([]byte, error) {
+
+		method := ""
+		if request.Method != nil {
+			method = *request.Method
+		}
+
+		service, _, _ := strings.Cut(method, ".")
+
+		ctx, span := tracer.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				AttributeRPCSystem.String("jsonrpc"),
+				AttributeRPCService.String(service),
+				AttributeRPCMethod.String(method),
+			),
+		)
+
+		defer span.End()
+
+		info := &types.SendInfo{}
+
+		b, err := next(types.WithSendInfo(ctx, info), request)
+
+		if info.Hostname != "" {
+			span.SetAttributes(
+				AttributeServerAddress.String(info.Hostname),
+				AttributeRequestID.Int(info.RequestID),
+			)
+		}
+
+		if info.AuthRetry {
+			span.SetAttributes(AttributeAuthRetry.Bool(true))
+			span.AddEvent("auth challenge")
+		}
+
+		if err != nil {
+
+			if e, ok := types.AsError(err); ok {
+				span.SetAttributes(
+					AttributeErrorCode.Int(e.Code),
+					AttributeErrorMessage.String(e.Message),
+				)
+			}
+
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return b, err
+	}
+}
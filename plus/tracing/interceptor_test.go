@@ -0,0 +1,128 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus"
+	"github.com/jodydadescott/shelly-go-sdk/plus/msghandlers"
+	"github.com/jodydadescott/shelly-go-sdk/plus/simulator"
+)
+
+func TestInterceptorSpan(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		password  string
+		method    string
+		params    any
+		authRetry bool
+		errorCode int64
+	}{
+		{
+			name:   "success",
+			method: "Sys.GetConfig",
+		},
+		{
+			name:      "auth retry",
+			password:  "secret",
+			method:    "Sys.GetConfig",
+			authRetry: true,
+		},
+		{
+			name:      "device error",
+			method:    "Switch.GetConfig",
+			params:    map[string]any{"id": 5},
+			errorCode: -105,
+		},
+		{
+			name:      "device error after auth retry",
+			password:  "secret",
+			method:    "Switch.GetConfig",
+			params:    map[string]any{"id": 5},
+			authRetry: true,
+			errorCode: -105,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			device := simulator.New(&simulator.Config{Password: tt.password})
+			err := device.Start()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer device.Close()
+
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			client, err := plus.New(&msghandlers.Config{
+				Hostname: device.Hostname(),
+				Username: "admin",
+				Password: tt.password,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			client.Use(NewInterceptor(&Config{TracerProvider: provider}))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			_, err = plus.CallRaw(ctx, client, tt.method, tt.params)
+			if (err != nil) != (tt.errorCode != 0) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+
+			span := spans[0]
+			attributes := map[attribute.Key]attribute.Value{}
+			for _, v := range span.Attributes() {
+				attributes[v.Key] = v.Value
+			}
+
+			if span.Name() != tt.method || attributes[AttributeRPCMethod].AsString() != tt.method {
+				t.Errorf("span %s has method %s, want %s", span.Name(), attributes[AttributeRPCMethod].AsString(), tt.method)
+			}
+
+			if attributes[AttributeServerAddress].AsString() != device.Hostname() {
+				t.Errorf("server.address = %s, want %s", attributes[AttributeServerAddress].AsString(), device.Hostname())
+			}
+
+			if _, ok := attributes[AttributeRequestID]; !ok || attributes[AttributeRequestID].AsInt64() == 0 {
+				t.Errorf("request id is not set")
+			}
+
+			if attributes[AttributeAuthRetry].AsBool() != tt.authRetry {
+				t.Errorf("auth retry = %v, want %v", attributes[AttributeAuthRetry].AsBool(), tt.authRetry)
+			}
+
+			if attributes[AttributeErrorCode].AsInt64() != tt.errorCode {
+				t.Errorf("error code = %d, want %d", attributes[AttributeErrorCode].AsInt64(), tt.errorCode)
+			}
+
+			if (span.Status().Code == codes.Error) != (tt.errorCode != 0) {
+				t.Errorf("status = %v", span.Status())
+			}
+
+			if tt.errorCode != 0 && !strings.Contains(attributes[AttributeErrorMessage].AsString(), "not found") {
+				t.Errorf("error message = %s", attributes[AttributeErrorMessage].AsString())
+			}
+		})
+	}
+}
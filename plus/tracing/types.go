@@ -0,0 +1,9 @@
+package tracing
+
+import (
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+type Request = types.Request
+type Interceptor = types.Interceptor
+type SendFunc = types.SendFunc
@@ -0,0 +1,343 @@
+package types
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jinzhu/copier"
+)
+
+// ArchiveVersion is the version of the archive format written by this SDK. Archives with a higher
+// version are rejected by ReadArchive.
+const ArchiveVersion = 1
+
+const (
+	archiveManifest  = "archive.json"
+	archiveScriptDir = "scripts"
+)
+
+// Archive full device backup. It holds the component config, scripts, schedules, webhooks, KVS and the
+// device info of the source device. Passwords and TLS material can not be read from a device; the
+// config holds placeholders for passwords (see Markup) and TLS holds flags for the material referenced
+// by the config.
+type Archive struct {
+	// Version of the archive format
+	Version int `json:"version" yaml:"version"`
+	// Created time the backup was taken
+	Created time.Time `json:"created" yaml:"created"`
+	// DeviceInfo of the source device
+	DeviceInfo *DeviceInfo `json:"device_info,omitempty" yaml:"device_info,omitempty"`
+	// Config of the source device with placeholders for passwords. TLSClientCert, TLSClientKey and
+	// UserCA are always nil.
+	Config *ShellyConfig `json:"config,omitempty" yaml:"config,omitempty"`
+	// TLS material referenced by the config
+	TLS *ArchiveTLS `json:"tls,omitempty" yaml:"tls,omitempty"`
+	// Scripts including their code
+	Scripts []*Script `json:"scripts,omitempty" yaml:"scripts,omitempty"`
+	// Schedules jobs
+	Schedules []*Schedule `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+	// Webhooks
+	Webhooks []*Webhook `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	// KVS key value store items
+	KVS []*KVSItem `json:"kvs,omitempty" yaml:"kvs,omitempty"`
+	// Warnings items that could not be backed up, for example because the device does not support scripts
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// Clone return copy
+func (t *Archive) Clone() *Archive {
+	c := &Archive{}
+	copier.CopyWithOption(c, t, copier.Option{DeepCopy: true})
+	return c
+}
+
+// ArchiveTLS flags for TLS material referenced by the config of the source device. The material itself
+// can not be read from the device and must be provided on restore.
+type ArchiveTLS struct {
+	// UserCA true if the config uses user_ca.pem
+	UserCA bool `json:"user_ca" yaml:"user_ca"`
+	// TLSClientCert true if the config uses the client certificate and key
+	TLSClientCert bool `json:"tls_client_cert" yaml:"tls_client_cert"`
+}
+
+// Script Script component. Code is only set in an Archive.
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Script
+type Script struct {
+	// ID of the script
+	ID int `json:"id" yaml:"id"`
+	// Name of the script
+	Name *string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Enable true if the script runs on boot, false otherwise
+	Enable bool `json:"enable" yaml:"enable"`
+	// Running true if the script is running, false otherwise
+	Running bool `json:"running,omitempty" yaml:"running,omitempty"`
+	// Code source code of the script
+	Code string `json:"code,omitempty" yaml:"code,omitempty"`
+}
+
+// Clone return copy
+func (t *Script) Clone() *Script {
+	c := &Script{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// Schedule Schedule job
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Schedule
+type Schedule struct {
+	// ID of the job
+	ID *int `json:"id,omitempty" yaml:"id,omitempty"`
+	// Enable true to enable the execution of this job, false otherwise
+	Enable bool `json:"enable" yaml:"enable"`
+	// Timespec as defined by cron. Note that leading 0s are not supported (e.g.: for 8 AM you should set 8 instead of 08)
+	Timespec string `json:"timespec" yaml:"timespec"`
+	// Calls RPC methods and arguments to be invoked when the job gets executed. It must contain at least one valid object
+	Calls []*ScheduleCall `json:"calls" yaml:"calls"`
+}
+
+// Clone return copy
+func (t *Schedule) Clone() *Schedule {
+	c := &Schedule{}
+	copier.CopyWithOption(c, t, copier.Option{DeepCopy: true})
+	return c
+}
+
+// ScheduleCall RPC method invoked by a Schedule job
+type ScheduleCall struct {
+	// Method name of the RPC method
+	Method string `json:"method" yaml:"method"`
+	// Params parameters of the RPC method
+	Params map[string]interface{} `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// KVSItem KVS (Key-Value Store) item
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/KVS
+type KVSItem struct {
+	// Key of the item
+	Key string `json:"key" yaml:"key"`
+	// Value of the item
+	Value interface{} `json:"value" yaml:"value"`
+}
+
+// ArchiveRestoreOptions options for Restore
+type ArchiveRestoreOptions struct {
+	// IncludeName if true the device name (sys.device.name) is restored, otherwise the target keeps its name
+	IncludeName bool `json:"include_name,omitempty" yaml:"include_name,omitempty"`
+	// AllowModelMismatch if true the archive is restored onto a device of a different model
+	AllowModelMismatch bool `json:"allow_model_mismatch,omitempty" yaml:"allow_model_mismatch,omitempty"`
+	// ReplaceExisting if true existing scripts, schedules and webhooks on the target are deleted first,
+	// otherwise the archived items are added to the existing ones
+	ReplaceExisting bool `json:"replace_existing,omitempty" yaml:"replace_existing,omitempty"`
+	// Secrets resolves the password placeholders in the config. Components holding unresolved
+	// placeholders are not restored and reported with an error. Optional
+	Secrets SecretResolver `json:"-" yaml:"-"`
+	// UserCA, TLSClientCert and TLSClientKey TLS material to install on the target. Optional
+	UserCA        *ShellyUserCAConfig        `json:"user_ca,omitempty" yaml:"user_ca,omitempty"`
+	TLSClientCert *ShellyTLSClientCertConfig `json:"tls_client_cert,omitempty" yaml:"tls_client_cert,omitempty"`
+	TLSClientKey  *ShellyTLSClientKeyConfig  `json:"tls_client_key,omitempty" yaml:"tls_client_key,omitempty"`
+	// SetConfigOptions options used when the config is set. Optional
+	SetConfigOptions *ShellySetConfigOptions `json:"set_config_options,omitempty" yaml:"set_config_options,omitempty"`
+}
+
+// ArchiveItemReport result of restoring a single item
+type ArchiveItemReport struct {
+	// Item name of the item, for example script:1 or kvs:key
+	Item  string `json:"item" yaml:"item"`
+	Error error  `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ArchiveRestoreReport result of Restore
+type ArchiveRestoreReport struct {
+	// Config report of setting the config. Auth is reported separately as it is set last.
+	Config    *ShellyReport        `json:"config,omitempty" yaml:"config,omitempty"`
+	Scripts   []*ArchiveItemReport `json:"scripts,omitempty" yaml:"scripts,omitempty"`
+	Schedules []*ArchiveItemReport `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+	Webhooks  []*ArchiveItemReport `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	KVS       []*ArchiveItemReport `json:"kvs,omitempty" yaml:"kvs,omitempty"`
+	Auth      *ArchiveItemReport   `json:"auth,omitempty" yaml:"auth,omitempty"`
+	// Warnings items that were skipped, for example TLS material that was not provided
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// Error returns all errors or nil if every item was restored
+func (t *ArchiveRestoreReport) Error() error {
+
+	var errors *multierror.Error
+
+	if t.Config != nil {
+		if err := t.Config.Error(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Config :: %v", err))
+		}
+	}
+
+	for _, list := range [][]*ArchiveItemReport{t.Scripts, t.Schedules, t.Webhooks, t.KVS, {t.Auth}} {
+		for _, v := range list {
+			if v != nil && v.Error != nil {
+				errors = multierror.Append(errors, fmt.Errorf("%s :: %v", v.Item, v.Error))
+			}
+		}
+	}
+
+	return errors.ErrorOrNil()
+}
+
+// JSON returns the archive as a single indented JSON document
+func (t *Archive) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+// WriteTarGz writes the archive as a gzipped tarball. The tarball holds archive.json and the code of
+// each script as scripts/<id>.js so that scripts can be read and diffed with standard tools.
+func (t *Archive) WriteTarGz(w io.Writer) error {
+
+	manifest := t.Clone()
+
+	for _, v := range manifest.Scripts {
+		v.Code = ""
+	}
+
+	manifestBytes, err := manifest.JSON()
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	writeFile := func(name string, b []byte) error {
+
+		err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(b)),
+			ModTime: t.Created,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tw.Write(b)
+		return err
+	}
+
+	err = writeFile(archiveManifest, manifestBytes)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range t.Scripts {
+		err = writeFile(fmt.Sprintf("%s/%d.js", archiveScriptDir, v.ID), []byte(v.Code))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// ReadArchive reads an archive written by JSON or WriteTarGz. The format is detected from the content.
+func ReadArchive(r io.Reader) (*Archive, error) {
+
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	var archive *Archive
+
+	if magic[0] == 0x1f && magic[1] == 0x8b {
+		archive, err = readTarGz(br)
+	} else {
+		archive = &Archive{}
+		err = json.NewDecoder(br).Decode(archive)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return nil, fmt.Errorf("archive version %d is not supported; supported version is %d", archive.Version, ArchiveVersion)
+	}
+
+	return archive, nil
+}
+
+func readTarGz(r io.Reader) (*Archive, error) {
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+
+	var archive *Archive
+	code := map[int]string{}
+
+	for {
+
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		_, err = io.Copy(&buf, tr)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+
+		case header.Name == archiveManifest:
+			archive = &Archive{}
+			err = json.Unmarshal(buf.Bytes(), archive)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", archiveManifest, err)
+			}
+
+		case path.Dir(header.Name) == archiveScriptDir && strings.HasSuffix(header.Name, ".js"):
+			id, err := strconv.Atoi(strings.TrimSuffix(path.Base(header.Name), ".js"))
+			if err != nil {
+				return nil, fmt.Errorf("%s: script file name is not an id", header.Name)
+			}
+			code[id] = buf.String()
+
+		}
+	}
+
+	if archive == nil {
+		return nil, fmt.Errorf("%s is missing from the tarball", archiveManifest)
+	}
+
+	for _, v := range archive.Scripts {
+		v.Code = code[v.ID]
+	}
+
+	return archive, nil
+}
@@ -63,6 +63,23 @@ func (t *BluetoothConfig) Sanatize() {
 	t.Observer.Sanatize()
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *BluetoothConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *BluetoothConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	t.RPC.validate(joinPath(path, "rpc"), v)
+	t.Observer.validate(joinPath(path, "observer"), v)
+}
+
 // BluetoothRPC configuration of the rpc service
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/BLE#configuration
 type BluetoothRPC struct {
@@ -95,6 +112,21 @@ func (t *BluetoothRPC) Sanatize() {
 
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *BluetoothRPC) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *BluetoothRPC) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+}
+
 // BluetoothObserver configuration of the BT LE observer
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/BLE#configuration
 type BluetoothObserver struct {
@@ -126,3 +158,18 @@ func (t *BluetoothObserver) Sanatize() {
 	}
 
 }
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *BluetoothObserver) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *BluetoothObserver) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+}
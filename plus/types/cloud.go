@@ -53,3 +53,18 @@ func (t *CloudConfig) Sanatize() {
 
 	t.Server = nil
 }
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *CloudConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *CloudConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/json"
+
 	"github.com/jinzhu/copier"
 )
 
@@ -16,7 +18,9 @@ type FirmwareStatus struct {
 
 // Request generic request
 type Request struct {
-	ID     *int          `json:"id"`
+	ID *int `json:"id"`
+	// Src identifies the client. The device only sends notifications to clients that set it.
+	Src    *string       `json:"src,omitempty" yaml:"src,omitempty"`
 	Method *string       `json:"method,omitempty" yaml:"method,omitempty"`
 	Params interface{}   `json:"params,omitempty" yaml:"params,omitempty"`
 	Auth   *AuthResponse `json:"auth,omitempty" yaml:"auth,omitempty"`
@@ -43,6 +47,59 @@ func (t *Response) Clone() *Response {
 	return c
 }
 
+// RawResponse generic response with the result left undecoded
+type RawResponse struct {
+	ID     *int            `json:"id" yaml:"id"`
+	Src    *string         `json:"src" yaml:"src"`
+	Result json.RawMessage `json:"result,omitempty" yaml:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Notification message sent by the device without a request, such as NotifyStatus and NotifyEvent
+// https://shelly-api-docs.shelly.cloud/gen2/General/Notifications
+type Notification struct {
+	Src    *string         `json:"src,omitempty" yaml:"src,omitempty"`
+	Dst    *string         `json:"dst,omitempty" yaml:"dst,omitempty"`
+	Method string          `json:"method" yaml:"method"`
+	Params json.RawMessage `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// Events returns the events of a NotifyEvent notification or nil for other notifications
+func (t *Notification) Events() ([]*NotificationEvent, error) {
+
+	if t.Method != "NotifyEvent" {
+		return nil, nil
+	}
+
+	params := &struct {
+		Events []*NotificationEvent `json:"events"`
+	}{}
+
+	err := json.Unmarshal(t.Params, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return params.Events, nil
+}
+
+// NotificationEvent event of a NotifyEvent notification
+// https://shelly-api-docs.shelly.cloud/gen2/General/Notifications#notifyevent
+type NotificationEvent struct {
+	// Component that emitted the event, for example sys or switch:0
+	Component string `json:"component" yaml:"component"`
+	// ID of the component instance (if applicable)
+	ID *int `json:"id,omitempty" yaml:"id,omitempty"`
+	// Event name, for example ota_progress
+	Event string `json:"event" yaml:"event"`
+	// Ts Unix timestamp (in UTC) of the event
+	Ts *float64 `json:"ts,omitempty" yaml:"ts,omitempty"`
+	// ProgressPercent progress of an update in percent (only for ota_progress)
+	ProgressPercent *int `json:"progress_percent,omitempty" yaml:"progress_percent,omitempty"`
+	// Msg message of the event, for example the reason of an ota_error
+	Msg *string `json:"msg,omitempty" yaml:"msg,omitempty"`
+}
+
 // type SetStatus string
 
 // const (
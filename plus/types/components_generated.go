@@ -0,0 +1,66 @@
+// Code generated by gen_components.go; DO NOT EDIT.
+
+package types
+
+import (
+	"github.com/jinzhu/copier"
+)
+
+// Scan WiFi component object
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi
+type WifiNet struct {
+	SSID    *string `json:"ssid" yaml:"ssid"`
+	BSSID   *string `json:"bssid" yaml:"bssid"`
+	Auth    *int    `json:"auth" yaml:"auth"`
+	Channel *int    `json:"channel" yaml:"channel"`
+	RSSI    *int    `json:"rssi" yaml:"rssi"`
+}
+
+// Clone return copy
+func (t *WifiNet) Clone() *WifiNet {
+	c := &WifiNet{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// WifiAPClient WiFi component object
+// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi
+type WifiAPClient struct {
+	MAC      *string `json:"mac" yaml:"mac"`
+	IP       *string `json:"ip" yaml:"ip"`
+	IPStatic *bool   `json:"ip_static" yaml:"ip_static"`
+	Mport    *int    `json:"mport" yaml:"mport"`
+	Since    *int    `json:"since" yaml:"since"`
+}
+
+// Clone return copy
+func (t *WifiAPClient) Clone() *WifiAPClient {
+	c := &WifiAPClient{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// WifiScanResults Wifi Scan Results
+type WifiScanResults struct {
+	Results []WifiNet `json:"results" yaml:"results"`
+}
+
+// Clone return copy
+func (t *WifiScanResults) Clone() *WifiScanResults {
+	c := &WifiScanResults{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// WifiAPClients Wifi AP Clients
+type WifiAPClients struct {
+	Ts      *int           `json:"ts" yaml:"ts"`
+	Clients []WifiAPClient `json:"ap_clients" yaml:"ap_clients"`
+}
+
+// Clone return copy
+func (t *WifiAPClients) Clone() *WifiAPClients {
+	c := &WifiAPClients{}
+	copier.Copy(&c, &t)
+	return c
+}
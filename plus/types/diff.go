@@ -0,0 +1,354 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeType the type of a change. The values match the JSON Patch (RFC 6902) operations.
+type ChangeType string
+
+const (
+	// ChangeTypeAdd value was not set and is now set
+	ChangeTypeAdd ChangeType = "add"
+	// ChangeTypeRemove value was set and is now not set
+	ChangeTypeRemove ChangeType = "remove"
+	// ChangeTypeReplace value was set and has changed
+	ChangeTypeReplace ChangeType = "replace"
+)
+
+// Diff list of changes between two snapshots
+type Diff []*Change
+
+// DiffConfig returns the structural difference between two configs. Light, Input and Switch
+// components are matched by ID so that list order does not matter. Pointers are compared by value.
+func DiffConfig(old, new *ShellyConfig) Diff {
+
+	if old == nil {
+		old = &ShellyConfig{}
+	}
+
+	if new == nil {
+		new = &ShellyConfig{}
+	}
+
+	return diffDocuments(reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem())
+}
+
+// DiffStatus returns the structural difference between two status snapshots. Light, Input and
+// Switch components are matched by ID so that list order does not matter.
+func DiffStatus(old, new *ShellyStatus) Diff {
+
+	if old == nil {
+		old = &ShellyStatus{}
+	}
+
+	if new == nil {
+		new = &ShellyStatus{}
+	}
+
+	return diffDocuments(reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem())
+}
+
+// String returns the diff in human readable form with one change per line
+func (t Diff) String() string {
+
+	if len(t) == 0 {
+		return "no changes"
+	}
+
+	var b strings.Builder
+
+	for _, v := range t {
+
+		switch v.Type {
+
+		case ChangeTypeAdd:
+			b.WriteString("+ ")
+
+		case ChangeTypeRemove:
+			b.WriteString("- ")
+
+		default:
+			b.WriteString("~ ")
+
+		}
+
+		b.WriteString(v.String())
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// JSONPatchOperation a single RFC 6902 operation
+type JSONPatchOperation struct {
+	Op    string      `json:"op" yaml:"op"`
+	Path  string      `json:"path" yaml:"path"`
+	Value interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// JSONPatch returns the diff as a RFC 6902 JSON Patch that transforms the JSON encoding of the
+// old snapshot into the new snapshot
+func (t Diff) JSONPatch() ([]byte, error) {
+
+	operations := []*JSONPatchOperation{}
+
+	for _, v := range t {
+
+		if v.Pointer == "" {
+			return nil, fmt.Errorf("change %s has no JSON pointer", v.Path)
+		}
+
+		operation := &JSONPatchOperation{
+			Op:   string(v.Type),
+			Path: v.Pointer,
+		}
+
+		if v.Type != ChangeTypeRemove {
+			operation.Value = v.New
+		}
+
+		operations = append(operations, operation)
+	}
+
+	return json.Marshal(operations)
+}
+
+func diffDocuments(old, new reflect.Value) Diff {
+
+	var changes Diff
+
+	for i := 0; i < new.NumField(); i++ {
+
+		field := new.Type().Field(i)
+
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Ptr &&
+			field.Type.Elem().Elem().Kind() == reflect.Struct {
+			changes = append(changes, diffComponentList(name, old.Field(i), new.Field(i))...)
+			continue
+		}
+
+		changes = append(changes, diffValues(name, "/"+name, field, old.Field(i), new.Field(i), false)...)
+	}
+
+	return changes
+}
+
+// diffComponentList matches elements by ID. Changes to matched elements are returned first, followed
+// by removed elements in descending index order and then added elements; this keeps the JSON pointer
+// indexes valid when the patch is applied in order.
+func diffComponentList(name string, old, new reflect.Value) Diff {
+
+	var changes, removed, added Diff
+
+	oldIndex := map[int]int{}
+
+	for i := 0; i < old.Len(); i++ {
+		if id, ok := componentID(old.Index(i)); ok {
+			oldIndex[id] = i
+		}
+	}
+
+	newIDs := map[int]bool{}
+
+	for i := 0; i < new.Len(); i++ {
+
+		id, ok := componentID(new.Index(i))
+		if !ok {
+			continue
+		}
+
+		newIDs[id] = true
+		path := fmt.Sprintf("%s:%d", name, id)
+
+		index, found := oldIndex[id]
+		if !found {
+			added = append(added, &Change{
+				Type:    ChangeTypeAdd,
+				Path:    path,
+				Pointer: "/" + name + "/-",
+				New:     new.Index(i).Interface(),
+			})
+			continue
+		}
+
+		pointer := "/" + name + "/" + strconv.Itoa(index)
+		changes = append(changes, diffValues(path, pointer, reflect.StructField{}, old.Index(index), new.Index(i), false)...)
+	}
+
+	for i := old.Len() - 1; i >= 0; i-- {
+
+		id, ok := componentID(old.Index(i))
+		if !ok || newIDs[id] {
+			continue
+		}
+
+		removed = append(removed, &Change{
+			Type:    ChangeTypeRemove,
+			Path:    fmt.Sprintf("%s:%d", name, id),
+			Pointer: "/" + name + "/" + strconv.Itoa(i),
+			Old:     old.Index(i).Interface(),
+		})
+	}
+
+	sort.SliceStable(added, func(i, j int) bool {
+		return added[i].Path < added[j].Path
+	})
+
+	changes = append(changes, removed...)
+	return append(changes, added...)
+}
+
+func componentID(v reflect.Value) (int, bool) {
+
+	v = indirect(v)
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	id := indirect(v.FieldByName("ID"))
+	if !id.IsValid() || id.Kind() != reflect.Int {
+		return 0, false
+	}
+
+	return int(id.Int()), true
+}
+
+// indirect dereferences pointers and interfaces. An invalid value is returned for nil.
+func indirect(v reflect.Value) reflect.Value {
+
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// isAbsent returns true if the value would not be present in the JSON encoding
+func isAbsent(field reflect.StructField, v reflect.Value) bool {
+
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+
+	}
+
+	if strings.Contains(field.Tag.Get("json"), ",omitempty") {
+		return v.IsZero()
+	}
+
+	return false
+}
+
+// diffValues compares old and new. If partial is true only fields set in new are compared, this is
+// used when planning a config where nil means leave unchanged.
+func diffValues(path, pointer string, field reflect.StructField, old, new reflect.Value, partial bool) Diff {
+
+	oldAbsent := isAbsent(field, old)
+	newAbsent := isAbsent(field, new)
+
+	if newAbsent && (partial || oldAbsent) {
+		return nil
+	}
+
+	old = indirect(old)
+	new = indirect(new)
+
+	if new.IsValid() && new.Kind() == reflect.Struct && (partial || !oldAbsent) {
+
+		var changes Diff
+
+		for i := 0; i < new.NumField(); i++ {
+
+			field := new.Type().Field(i)
+
+			name := jsonName(field)
+			if name == "" {
+				continue
+			}
+
+			var oldField reflect.Value
+			if !oldAbsent {
+				oldField = old.Field(i)
+			}
+
+			changes = append(changes, diffValues(path+"."+name, pointer+"/"+escapePointer(name), field,
+				oldField, new.Field(i), partial)...)
+		}
+
+		return changes
+	}
+
+	change := &Change{
+		Path:    path,
+		Pointer: pointer,
+	}
+
+	switch {
+
+	case oldAbsent:
+		change.Type = ChangeTypeAdd
+		change.New = new.Interface()
+
+	case newAbsent:
+		change.Type = ChangeTypeRemove
+		change.Old = old.Interface()
+
+	default:
+		if reflect.DeepEqual(old.Interface(), new.Interface()) {
+			return nil
+		}
+		change.Type = ChangeTypeReplace
+		change.Old = old.Interface()
+		change.New = new.Interface()
+
+	}
+
+	return Diff{change}
+}
+
+// escapePointer escapes a JSON pointer reference token per RFC 6901
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// jsonName returns the JSON name of the field or empty if the field is not serialized
+func jsonName(field reflect.StructField) string {
+
+	if field.PkgPath != "" {
+		return ""
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
@@ -0,0 +1,210 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDiffConfigJSONPatch(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		old, new   *ShellyConfig
+		operations []string
+	}{
+		{
+			name:       "reordered list has no changes",
+			old:        &ShellyConfig{Switch: []*SwitchConfig{{ID: 0, Name: ptr("a")}, {ID: 1, Name: ptr("b")}}},
+			new:        &ShellyConfig{Switch: []*SwitchConfig{{ID: 1, Name: ptr("b")}, {ID: 0, Name: ptr("a")}}},
+			operations: nil,
+		},
+		{
+			name: "matched by id",
+			old:  &ShellyConfig{Switch: []*SwitchConfig{{ID: 0, Name: ptr("a")}, {ID: 1, Name: ptr("b")}}},
+			new:  &ShellyConfig{Switch: []*SwitchConfig{{ID: 1, Name: ptr("c")}, {ID: 0, Name: ptr("a")}}},
+			operations: []string{
+				"replace /switch/1/name",
+			},
+		},
+		{
+			name: "removals in descending index order before additions",
+			old: &ShellyConfig{Switch: []*SwitchConfig{{ID: 0, Name: ptr("a")}, {ID: 1, Name: ptr("b")},
+				{ID: 2, Name: ptr("c")}}},
+			new: &ShellyConfig{Switch: []*SwitchConfig{{ID: 1, Name: ptr("x")}, {ID: 4}, {ID: 3}}},
+			operations: []string{
+				"replace /switch/1/name",
+				"remove /switch/2",
+				"remove /switch/0",
+				"add /switch/-",
+				"add /switch/-",
+			},
+		},
+		{
+			name: "fields",
+			old:  &ShellyConfig{Mqtt: &MqttConfig{Enable: true, Server: ptr("broker"), User: ptr("u")}},
+			new:  &ShellyConfig{Mqtt: &MqttConfig{Enable: false, Server: ptr("broker"), ClientID: ptr("c")}},
+			operations: []string{
+				"replace /mqtt/enable",
+				"add /mqtt/client_id",
+				"remove /mqtt/user",
+			},
+		},
+		{
+			name: "component added and removed",
+			old:  &ShellyConfig{Cloud: &CloudConfig{Enable: true}},
+			new:  &ShellyConfig{Mqtt: &MqttConfig{Enable: false}},
+			operations: []string{
+				"remove /cloud",
+				"add /mqtt",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			diff := DiffConfig(tt.old, tt.new)
+
+			b, err := diff.JSONPatch()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var patch []*JSONPatchOperation
+			err = json.Unmarshal(b, &patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var operations []string
+			for _, v := range patch {
+				operations = append(operations, v.Op+" "+v.Path)
+			}
+
+			if strings.Join(operations, "\n") != strings.Join(tt.operations, "\n") {
+				t.Fatalf("operations =\n%s\nwant\n%s", strings.Join(operations, "\n"), strings.Join(tt.operations, "\n"))
+			}
+
+			doc := toJSONValue(t, tt.old)
+			for _, v := range patch {
+				doc = applyOperation(t, doc, v)
+			}
+
+			want := toJSONValue(t, tt.new)
+			sortByID(doc)
+			sortByID(want)
+
+			if !reflect.DeepEqual(doc, want) {
+				t.Errorf("patched document =\n%v\nwant\n%v", doc, want)
+			}
+		})
+	}
+}
+
+func toJSONValue(t *testing.T, v interface{}) interface{} {
+
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value interface{}
+	err = json.Unmarshal(b, &value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return value
+}
+
+// applyOperation applies a single add, remove or replace operation to doc and returns the result
+func applyOperation(t *testing.T, doc interface{}, operation *JSONPatchOperation) interface{} {
+
+	t.Helper()
+
+	tokens := strings.Split(strings.TrimPrefix(operation.Path, "/"), "/")
+	value := toJSONValue(t, operation.Value)
+
+	var apply func(node interface{}, tokens []string) interface{}
+	apply = func(node interface{}, tokens []string) interface{} {
+
+		token := strings.ReplaceAll(strings.ReplaceAll(tokens[0], "~1", "/"), "~0", "~")
+		last := len(tokens) == 1
+
+		switch n := node.(type) {
+
+		case map[string]interface{}:
+			if !last {
+				n[token] = apply(n[token], tokens[1:])
+				return n
+			}
+			if operation.Op == "remove" {
+				if _, ok := n[token]; !ok {
+					t.Fatalf("%s: %s does not exist", operation.Path, token)
+				}
+				delete(n, token)
+				return n
+			}
+			n[token] = value
+			return n
+
+		case []interface{}:
+			if last && token == "-" && operation.Op == "add" {
+				return append(n, value)
+			}
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				t.Fatalf("%s: index %s is out of range", operation.Path, token)
+			}
+			if !last {
+				n[i] = apply(n[i], tokens[1:])
+				return n
+			}
+			if operation.Op == "remove" {
+				return append(n[:i], n[i+1:]...)
+			}
+			n[i] = value
+			return n
+		}
+
+		t.Fatalf("%s: can not apply to %T", operation.Path, node)
+		return nil
+	}
+
+	return apply(doc, tokens)
+}
+
+// sortByID orders the component lists by id so that documents can be compared regardless of order
+func sortByID(doc interface{}) {
+
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, v := range m {
+		list, ok := v.([]interface{})
+		if !ok {
+			continue
+		}
+		for i := 1; i < len(list); i++ {
+			for j := i; j > 0 && listID(list[j]) < listID(list[j-1]); j-- {
+				list[j], list[j-1] = list[j-1], list[j]
+			}
+		}
+	}
+}
+
+func listID(v interface{}) float64 {
+	if m, ok := v.(map[string]interface{}); ok {
+		if id, ok := m["id"].(float64); ok {
+			return id
+		}
+	}
+	return 0
+}
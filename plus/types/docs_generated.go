@@ -0,0 +1,657 @@
+// Code generated by gen_docs.go; DO NOT EDIT.
+
+package types
+
+// typeDocs doc comments of the types in this package. The outer key is the type name, the inner key
+// is the Go field name or "" for the doc comment of the type itself.
+var typeDocs = map[string]map[string]string{
+	"Archive": {
+		"":           "Archive full device backup. It holds the component config, scripts, schedules, webhooks, KVS and the device info of the source device. Passwords and TLS material can not be read from a device; the config holds placeholders for passwords (see Markup) and TLS holds flags for the material referenced by the config.",
+		"Config":     "Config of the source device with placeholders for passwords. TLSClientCert, TLSClientKey and UserCA are always nil.",
+		"Created":    "Created time the backup was taken",
+		"DeviceInfo": "DeviceInfo of the source device",
+		"KVS":        "KVS key value store items",
+		"Schedules":  "Schedules jobs",
+		"Scripts":    "Scripts including their code",
+		"TLS":        "TLS material referenced by the config",
+		"Version":    "Version of the archive format",
+		"Warnings":   "Warnings items that could not be backed up, for example because the device does not support scripts",
+		"Webhooks":   "Webhooks",
+	},
+	"ArchiveItemReport": {
+		"":     "ArchiveItemReport result of restoring a single item",
+		"Item": "Item name of the item, for example script:1 or kvs:key",
+	},
+	"ArchiveRestoreOptions": {
+		"":                   "ArchiveRestoreOptions options for Restore",
+		"AllowModelMismatch": "AllowModelMismatch if true the archive is restored onto a device of a different model",
+		"IncludeName":        "IncludeName if true the device name (sys.device.name) is restored, otherwise the target keeps its name",
+		"ReplaceExisting":    "ReplaceExisting if true existing scripts, schedules and webhooks on the target are deleted first, otherwise the archived items are added to the existing ones",
+		"Secrets":            "Secrets resolves the password placeholders in the config. Components holding unresolved placeholders are not restored and reported with an error. Optional",
+		"SetConfigOptions":   "SetConfigOptions options used when the config is set. Optional",
+		"UserCA":             "UserCA, TLSClientCert and TLSClientKey TLS material to install on the target. Optional",
+	},
+	"ArchiveRestoreReport": {
+		"":         "ArchiveRestoreReport result of Restore",
+		"Config":   "Config report of setting the config. Auth is reported separately as it is set last.",
+		"Warnings": "Warnings items that were skipped, for example TLS material that was not provided",
+	},
+	"ArchiveTLS": {
+		"":              "ArchiveTLS flags for TLS material referenced by the config of the source device. The material itself can not be read from the device and must be provided on restore.",
+		"TLSClientCert": "TLSClientCert true if the config uses the client certificate and key",
+		"UserCA":        "UserCA true if the config uses user_ca.pem",
+	},
+	"AuthResponse": {
+		"":          "Auth RFC7616 HTTP Digest Access Authentication https://www.rfc-editor.org/rfc/rfc7616",
+		"Algorithm": "algorithm: string, SHA-256. Required",
+		"Cnonce":    "cnonce: number, client nonce, random number generated by the client. Required",
+		"Nonce":     "nonce: number, random or pseudo-random number to prevent replay attacks, taken from the error message. Required",
+		"Realm":     "realm: string, device_id of the Shelly device. Required",
+		"Response":  "response: string, encoding of the string <ha1> + \":\" + <nonce> + \":\" + <nc> + \":\" + <cnonce> + \":\" + \"auth\" + \":\" + <ha2> in SHA256. Required ha1: string, <user>:<realm>:<password> encoded in SHA256 ha2: string, \"dummy_method:dummy_uri\" encoded in SHA256",
+		"Username":  "username: string, must be set to admin. Required",
+	},
+	"BluetoothConfig": {
+		"":         "BluetoothConfig configuration of the Bluetooth Low Energy component shows whether the bluetooth connection is enabled. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/BLE#configuration",
+		"Enable":   "Enable True if bluetooth is enabled, false otherwise",
+		"Observer": "Observer configuration of the BT LE observer",
+		"RPC":      "RPC configuration of the rpc service",
+	},
+	"BluetoothObserver": {
+		"":       "BluetoothObserver configuration of the BT LE observer https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/BLE#configuration",
+		"Enable": "Enable true if BT LE observer is enabled, false otherwise",
+	},
+	"BluetoothRPC": {
+		"":       "BluetoothRPC configuration of the rpc service https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/BLE#configuration",
+		"Enable": "Enable True if rpc service is enabled, false otherwise",
+	},
+	"BluetoothStatus": {
+		"": "BluetoothStatus status of the BLE component contains information about the bluetooth on/off state and does not own any status properties. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/BLE#status",
+	},
+	"Change": {
+		"":               "Change is a single field level change between two configs",
+		"New":            "New value of the field; nil if not set",
+		"Old":            "Old value of the field; nil if not set or unknown",
+		"Path":           "Path of the field using the JSON names, for example wifi.sta.ssid or switch:0.name",
+		"Pointer":        "Pointer RFC 6901 JSON pointer of the field in the old snapshot; only set by DiffConfig and DiffStatus",
+		"RebootRequired": "RebootRequired true if the change is known to require a reboot",
+		"Type":           "Type of the change",
+		"Unverifiable":   "Unverifiable true if the field is write only and the change can not be compared with or verified against the device. Old and New are not set so that secrets are not shown.",
+	},
+	"CloudConfig": {
+		"":       "CloudConfig configuration of the Cloud component shows information about the connection to the cloud https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Cloud#configuration",
+		"Enable": "Enable true if cloud connection is enabled, false otherwise",
+		"Server": "Server name of the server to which the device is connected",
+	},
+	"CloudStatus": {
+		"":          "CloudStatus status of the Cloud component it can be checked whether the device is connected to the cloud. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Cloud#status",
+		"Connected": "Connected true if the device is connected to the Shelly cloud, false otherwise",
+	},
+	"ComponentPlan": {
+		"":        "ComponentPlan the changes planned for a single component",
+		"Changes": "Changes field level changes",
+		"ID":      "ID of the component instance (only for Light, Input and Switch)",
+	},
+	"ComponentReport": {
+		"Mismatches": "Mismatches fields that differ between the config sent and the config read back from the device. Old is the value on the device and New is the value sent. Only set if verify is enabled.",
+	},
+	"DeviceInfo": {
+		"":             "DeviceInfo Shelly component top level device info https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellygetdeviceinfo",
+		"App":          "App name",
+		"AuthDomain":   "AuthDomain name of the domain (null if authentication is not enabled)",
+		"AuthEnabled":  "AuthEnabled true if authentication is enabled, false otherwise",
+		"Batch":        "Batch used to provision the device, present only when the ident parameter is set to true",
+		"Discoverable": "Discoverable present only when false. If true, device is shown in 'Discovered devices'. If false, the device is hidden.",
+		"FirmwareID":   "FirmwareID Id of the firmware of the device",
+		"FwSbits":      "FwSbits Shelly internal flags, present only when the ident parameter is set to true",
+		"Generation":   "Generation of the device",
+		"ID":           "ID Id of the device",
+		"Key":          "Key cloud key of the device (see note below), present only when the ident parameter is set to true",
+		"MAC":          "MAC address of the device",
+		"Model":        "Model of the device",
+		"Profile":      "Profile name of the device profile (only applicable for multi-profile devices)",
+		"Version":      "Version of the firmware of the device",
+	},
+	"EnvSecretResolver": {
+		"":       "EnvSecretResolver resolves secrets from environment variables. The variable name is the prefix followed by the secret name in upper case with every character that is not a letter or digit replaced by an underscore. With the default prefix the secret wifi.sta.pass is read from SHELLY_WIFI_STA_PASS.",
+		"Prefix": "Prefix of the environment variables. Default is ShellyEnvVar followed by an underscore",
+	},
+	"Error": {
+		"": "Error Shelly Error",
+	},
+	"EthernetConfig": {
+		"":           "EthernetConfig Ethernet component top level config https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Eth#configuration",
+		"Enable":     "Enable True if the configuration is enabled, false otherwise",
+		"Gateway":    "Gateway to use when ipv4mode is static",
+		"IP":         "IP Ip to use when ipv4mode is static",
+		"Ipv4Mode":   "Ipv4Mode IPv4 mode. Range of values: dhcp, static",
+		"Nameserver": "Nameserver to use when ipv4mode is static",
+		"Netmask":    "Netmask to use when ipv4mode is static",
+	},
+	"EthernetStatus": {
+		"":   "EthernetStatus Ethernet component top level status https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Eth#status",
+		"IP": "IP of the device in the network",
+	},
+	"Fault": {
+		"":        "Fault fault injected into a single request",
+		"Kind":    "Kind of the fault",
+		"Latency": "Latency added before the request is passed to the device. Used by every kind; required for FaultLatency",
+		"Method":  "Method if set a scripted fault is injected into the next request for this method. Optional",
+	},
+	"FileSecretResolver": {
+		"":    "FileSecretResolver resolves secrets from files in a directory. Each secret is a file named after the secret, as used by Docker and Kubernetes secrets. A single trailing newline is removed.",
+		"Dir": "Dir directory holding the secret files. Required",
+	},
+	"FirmwareImage": {
+		"":        "FirmwareImage firmware image served by the firmware server",
+		"App":     "App name of the firmware, for example Plus1PM",
+		"Model":   "Model of the device the image is built for, for example SNSW-001X16EU",
+		"Path":    "Path of the image relative to the firmware directory",
+		"Size":    "Size of the image in bytes",
+		"Version": "Version of the firmware, for example 1.0.3",
+	},
+	"FirmwareServerConfig": {
+		"":        "FirmwareServerConfig config for the firmware server",
+		"Addr":    "Addr address the server listens on, for example 192.168.1.10:8080. Default is :8080",
+		"BaseURL": "BaseURL URL the devices use to reach the server, for example http://192.168.1.10:8080. Optional if Addr holds a host; required if the server listens on all interfaces",
+		"Dir":     "Dir directory holding the images as <model>/<app>/<version>.zip",
+	},
+	"FirmwareStatus": {
+		"":        "FirmwareStatus is common for components Sys and Shelly https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#status & https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#status",
+		"BuildID": "BuildID Id of the new build",
+		"Version": "Version of the new firmware",
+	},
+	"InputConfig": {
+		"":                "InputConfig configuration of the Input component contains information about the type, invert and factory reset settings of the chosen input instance. To Get/Set the configuration of the Input component its id must be specified. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Input#configuration",
+		"FactoryReset":    "FactoryReset (only for type switch, button) True if input-triggered factory reset option is enabled, false otherwise (shown if applicable)",
+		"ID":              "ID of the Input component instance",
+		"Invert":          "Invert (only for type switch, button) True if the logical state of the associated input is inverted, false otherwise. For the change to be applied, the physical switch has to be toggled once after invert is set.",
+		"Name":            "Name of the input instance",
+		"ReportThreshold": "ReportThreshold (only for type analog) Analog input report threshold in percent. Accepted range is device-specific, default [1.0..50.0]% unless specified otherwise",
+		"Type":            "Type of associated input. Range of values switch, button, analog (only if applicable).",
+	},
+	"InputStatus": {
+		"":        "InputStatus status of the Input component contains information about the state of the chosen input instance. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Input#status",
+		"Errors":  "Errors shown only if at least one error is present. May contain out_of_range, read",
+		"ID":      "ID Id of the Input component instance",
+		"Percent": "Percent (only for type analog) Analog value in percent (null if valid value could not be obtained)",
+		"State":   "State (only for type switch, button) State of the input (null if the input instance is stateless, i.e. for type button)",
+	},
+	"JSONPatchOperation": {
+		"": "JSONPatchOperation a single RFC 6902 operation",
+	},
+	"JSONSchema": {
+		"": "JSONSchema a JSON Schema document or sub schema. Only the keywords needed to describe the types in this package are supported.",
+	},
+	"KVSItem": {
+		"":      "KVSItem KVS (Key-Value Store) item https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/KVS",
+		"Key":   "Key of the item",
+		"Value": "Value of the item",
+	},
+	"LightConfig": {
+		"AutoOff":                "AutoOff True if the \"Automatic OFF\" function is enabled, false otherwise",
+		"AutoOffDelay":           "AutoOffDelay Seconds to pass until the component is switched back off",
+		"AutoOn":                 "AutoOn True if the \"Automatic ON\" function is enabled, false otherwise",
+		"AutoOnDelay":            "AutoOnDelay Seconds to pass until the component is switched back on",
+		"DefaultBrightness":      "DefaultBrightness brightness level (in percent) after power on",
+		"ID":                     "ID Id of the Switch component instance",
+		"InitialState":           "InitialState range of values: off, on, restore_last, match_input",
+		"Name":                   "Name of the switch instance",
+		"NightModeActiveBetween": "NightModeActiveBetween containing 2 elements of type string, the first element indicates the start of the period during which the night mode will be active, the second indicates the end of that period. Both start and end are strings in the format HH:MM, where HH and MM are hours and minutes with optinal leading zeros",
+		"NightModeBrightness":    "NightModeBrightness brightness level limit when night mode is active",
+		"NightModeEnable":        "NightModeEnable Enable or disable night mode",
+	},
+	"LightStatus": {
+		"":               "LightStatus status of the Light component contains information about the brightness level and output state of the light instance. To obtain the status of the Light component its id must be specified. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Light#status",
+		"Brightness":     "Brightness current brightness level (in percent)",
+		"ID":             "ID Id of the Switch component instance",
+		"Output":         "Output true if the output channel is currently on, false otherwise",
+		"Source":         "Source of the last command, for example: init, WS_in, http, ...",
+		"TimerDuration":  "TimerDuration duration of the timer in seconds (shown if the timer is triggered)",
+		"TimerStartedAt": "TimerStartedAt Unix timestamp, start time of the timer (in UTC) (shown if the timer is triggered)",
+	},
+	"MqttConfig": {
+		"":              "MqttConfig configuration of the MQTT component contains information about the credentials and prefix used and the protection and notifications settings of the MQTT connection. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Mqtt#configuration",
+		"ClientID":      "ClientID identifies each MQTT client that connects to an MQTT brokers",
+		"Enable":        "Enable true if MQTT connection is enabled, false otherwise",
+		"EnableControl": "EnableControl enable the MQTT control feature. Defalut value: true",
+		"EnableRPC":     "EnableRPC enable RPC",
+		"Pass":          "Pass password for the MQTT Server",
+		"RPCNtf":        "RPCNtf enables RPC notifications (NotifyStatus and NotifyEvent) to be published on <device_id|topic_prefix>/events/rpc (<topic_prefix> when a custom prefix is set, <device_id> otherwise). Default value: true.",
+		"Server":        "Server host name of the MQTT server. Can be followed by port number - host:port",
+		"SslCa":         "SslCa type of the TCP sockets: null : Plain TCP connection user_ca.pem : TLS connection verified by the user-provided CA ca.pem : TLS connection verified by the built-in CA bundle",
+		"StatusNtf":     "StatusNtf enables publishing the complete component status on <device_id|topic_prefix>/status/<component>:<id> (<topic_prefix> when a custom prefix is set, <device_id> otherwise). The complete status will be published if a signifficant change occurred. Default value: false",
+		"TopicPrefix":   "TopicPrefix prefix of the topics on which device publish/subscribe. Limited to 300 characters. Could not start with $ and #, +, %, ? are not allowed. Values null : Device id is used as topic prefix",
+		"UseClientCert": "UseClientCert enable or diable usage of client certifactes to use MQTT with encription, default: false",
+		"User":          "User username for the MQTT Server",
+	},
+	"MqttStatus": {
+		"": "MqttStatus MQTT component top level status https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Mqtt",
+	},
+	"NopMetrics": {
+		"": "NopMetrics Metrics that discards all measurements. It is used if no metrics are configured.",
+	},
+	"Notification": {
+		"": "Notification message sent by the device without a request, such as NotifyStatus and NotifyEvent https://shelly-api-docs.shelly.cloud/gen2/General/Notifications",
+	},
+	"NotificationEvent": {
+		"":                "NotificationEvent event of a NotifyEvent notification https://shelly-api-docs.shelly.cloud/gen2/General/Notifications#notifyevent",
+		"Component":       "Component that emitted the event, for example sys or switch:0",
+		"Event":           "Event name, for example ota_progress",
+		"ID":              "ID of the component instance (if applicable)",
+		"Msg":             "Msg message of the event, for example the reason of an ota_error",
+		"ProgressPercent": "ProgressPercent progress of an update in percent (only for ota_progress)",
+		"Ts":              "Ts Unix timestamp (in UTC) of the event",
+	},
+	"RandomFaultConfig": {
+		"":             "RandomFaultConfig rates of a seeded random fault policy. Each rate is the probability from 0 to 1 that a request gets the fault. At most one fault other than latency is injected per request.",
+		"Disconnect":   "Disconnect rate of FaultDisconnect",
+		"DropRequest":  "DropRequest rate of FaultDropRequest",
+		"DropResponse": "DropResponse rate of FaultDropResponse",
+		"Duplicate":    "Duplicate rate of FaultDuplicate",
+		"ExpiredNonce": "ExpiredNonce rate of FaultExpiredNonce",
+		"MaxLatency":   "MaxLatency maximum latency added to every request. Optional",
+		"Methods":      "Methods if set only requests for these methods get faults. Optional",
+		"MinLatency":   "MinLatency minimum latency added to every request. Optional",
+		"OutOfOrder":   "OutOfOrder rate of FaultOutOfOrder",
+		"Seed":         "Seed of the random generator. The same seed yields the same faults for the same requests",
+	},
+	"RawResponse": {
+		"": "RawResponse generic response with the result left undecoded",
+	},
+	"RecordedExchange": {
+		"":               "RecordedExchange a single request and its response or error",
+		"Error":          "Error returned by the device, if any",
+		"Method":         "Method of the request",
+		"Params":         "Params of the request, null if the request has none",
+		"Response":       "Response raw response frame, empty if the request failed",
+		"TransportError": "TransportError error that is not a device error, for example timeout waiting for response",
+	},
+	"RecordedTransportError": {
+		"":        "RecordedTransportError transport error of a recorded exchange",
+		"Kind":    "Kind timeout, not_connected, closed, auth_required, malformed_response or error",
+		"Message": "Message of the error",
+	},
+	"Recording": {
+		"":          "Recording request and response frames captured by the recording message handler factory",
+		"Exchanges": "Exchanges in the order they were recorded",
+		"Version":   "Version of the recording file format",
+	},
+	"Request": {
+		"":    "Request generic request",
+		"Src": "Src identifies the client. The device only sends notifications to clients that set it.",
+	},
+	"Response": {
+		"": "Response generic response",
+	},
+	"RetryPolicy": {
+		"":                  "RetryPolicy policy for retrying RPCs that failed with a transient error. Only read-only methods (Get*, List* and CheckForUpdate), the methods in IdempotentMethods and requests sent with a context returned by WithIdempotent are retried. Transient errors are ErrTimeout, ErrNotConnected and the device errors unavailable (-114) and deadline exceeded (-104).",
+		"IdempotentMethods": "IdempotentMethods write methods that are safe to retry, for example Switch.Set. Optional",
+		"InitialBackoff":    "InitialBackoff wait time before the first retry. Default is 200ms",
+		"Jitter":            "Jitter fraction of the backoff that is randomized, 0 to 1. Default is 0.2",
+		"MaxAttempts":       "MaxAttempts maximum number of attempts including the first one. Default is 3",
+		"MaxBackoff":        "MaxBackoff maximum wait time between retries. Default is 5s",
+		"Multiplier":        "Multiplier factor the backoff grows by after each retry. Default is 2",
+	},
+	"RolloutDevice": {
+		"":         "RolloutDevice a device in the fleet",
+		"Hostname": "Hostname hostname or IP of the device",
+		"Name":     "Name unique name of the device. Used as key in the state file",
+	},
+	"RolloutDeviceState": {
+		"":            "RolloutDeviceState state of a device in a rollout",
+		"Finished":    "Finished time the device was processed",
+		"FromVersion": "FromVersion firmware version before the update",
+		"Hostname":    "Hostname of the device",
+		"Name":        "Name of the device",
+		"Reason":      "Reason the device was skipped or failed",
+		"Status":      "Status pending, updated, skipped or failed",
+		"ToVersion":   "ToVersion firmware version after the update",
+		"Wave":        "Wave index of the wave the device belongs to. Wave 0 is the canary wave if canaries are configured",
+	},
+	"RolloutOptions": {
+		"":              "RolloutOptions options for a fleet rollout",
+		"BatchSize":     "BatchSize number of devices per wave after the canary wave. Default is 10",
+		"Canary":        "Canary number of devices updated in the first wave. The rollout halts if any canary fails. Optional",
+		"Concurrency":   "Concurrency number of devices updated at the same time within a wave. Default is 1",
+		"Force":         "Force if true a halted rollout is resumed without retrying the failed devices. The failures of previous runs no longer halt the rollout",
+		"HealthDelay":   "HealthDelay time to wait after a device updated before the health gate is checked. Optional",
+		"MaxFailures":   "MaxFailures number of failed devices tolerated before the rollout halts. Default is 0",
+		"Progress":      "Progress is called every time a device was processed. Optional",
+		"RetryFailed":   "RetryFailed if true devices that failed in a previous run are retried on resume",
+		"Stage":         "Stage of the firmware, either stable or beta. Default is stable",
+		"StateFile":     "StateFile file the state is saved to after every device. If the file exists the rollout is resumed. Optional",
+		"UpdateTimeout": "UpdateTimeout maximum time to wait for a single device to update. Optional",
+	},
+	"RolloutState": {
+		"":           "RolloutState state of a rollout. It is saved after every device so that an interrupted or halted rollout can be resumed.",
+		"Devices":    "Devices state of each device by name",
+		"HaltReason": "HaltReason reason the rollout was halted",
+		"Halted":     "Halted true if the rollout was halted because the failure threshold was exceeded",
+		"Stage":      "Stage of the firmware, stable or beta",
+		"Started":    "Started time the rollout was started",
+		"Updated":    "Updated time the state was last saved",
+		"Version":    "Version of the state file format",
+		"Waves":      "Waves in the order they are rolled out",
+	},
+	"RolloutWave": {
+		"":        "RolloutWave a group of devices updated together",
+		"Canary":  "Canary true if this is the canary wave",
+		"Devices": "Devices names of the devices in the wave",
+	},
+	"Schedule": {
+		"":         "Schedule Schedule job https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Schedule",
+		"Calls":    "Calls RPC methods and arguments to be invoked when the job gets executed. It must contain at least one valid object",
+		"Enable":   "Enable true to enable the execution of this job, false otherwise",
+		"ID":       "ID of the job",
+		"Timespec": "Timespec as defined by cron. Note that leading 0s are not supported (e.g.: for 8 AM you should set 8 instead of 08)",
+	},
+	"ScheduleCall": {
+		"":       "ScheduleCall RPC method invoked by a Schedule job",
+		"Method": "Method name of the RPC method",
+		"Params": "Params parameters of the RPC method",
+	},
+	"Script": {
+		"":        "Script Script component. Code is only set in an Archive. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Script",
+		"Code":    "Code source code of the script",
+		"Enable":  "Enable true if the script runs on boot, false otherwise",
+		"ID":      "ID of the script",
+		"Name":    "Name of the script",
+		"Running": "Running true if the script is running, false otherwise",
+	},
+	"SendInfo": {
+		"":          "SendInfo details of a request that are only known to the message handler. An interceptor passes an empty SendInfo in the context with WithSendInfo and reads it after the request was sent; the message handler fills it in if it finds one in the context.",
+		"AuthRetry": "AuthRetry true if the request was sent again after an auth challenge",
+		"Hostname":  "Hostname of the device",
+		"RequestID": "RequestID JSON-RPC ID of the request",
+	},
+	"ShellyAuthConfig": {
+		"":       "ShellyAuthConfig Shelly Auth Config https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration",
+		"Enable": "Enable true if MQTT connection is enabled, false otherwise",
+		"Pass":   "Pass password",
+	},
+	"ShellyConfig": {
+		"": "ShellyConfig Shelly component config. The config is composed of each components config. Shelly devices can have zero or more 'Light', 'Input' and 'Switch' types. Because these are explicity named and not members of a JSON array we have statically created them. This seemed to be a cleaner solution then a customized JSON/YAML encoder/decoder. We have created 8 for each which is currently more then enough as the max for any Shelly product as of today is 4. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration",
+	},
+	"ShellyPlan": {
+		"":        "ShellyPlan the difference between the current config of a device and a desired config. Only components with changes are applied.",
+		"Desired": "Desired the sanatized desired config the plan was made from",
+	},
+	"ShellyRPCMethods": {
+		"":        "ShellyRPCMethods lists of all available RPC methods. It takes into account both ACL and authentication restrictions and only lists the methods allowed for the particular user/channel that's making the request. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellylistmethods",
+		"Methods": "Methods names of the methods allowed",
+	},
+	"ShellyReport": {
+		"Reboot": "Reboot is set if the device was rebooted after the config was set",
+		"Verify": "Verify is set if the config was verified after it was set. Mismatches are recorded on each component.",
+	},
+	"ShellySetConfigOptions": {
+		"":              "ShellySetConfigOptions options for Shelly SetConfigWithOptions",
+		"Reboot":        "Reboot if true and one or more components report that a reboot is required the device is rebooted once after all components have been set. The call then waits for the device to reconnect and for restart_required to clear before verifying the applied config.",
+		"RebootTimeout": "RebootTimeout maximum time to wait for the device to come back after the reboot. Optional",
+		"Verify":        "Verify if true the config is read back from the device after it has been set (and after the reboot if one was done) and fields that differ from what was sent are recorded in the component reports. Verify is implied by Reboot.",
+	},
+	"ShellyStatus": {
+		"": "ShellyStatus status of all the components of the device. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly",
+	},
+	"ShellyTLSClientCertConfig": {
+		"":       "ShellyTLSClientCertConfig Shelly TLS Client Cert config https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration",
+		"Data":   "Data is used by the following methods: PutUserCA : Contents of the PEM file (null if you want to delete the existing data). Required PutTLSClientCert : Contents of the client.crt file (null if you want to delete the existing data). Required PutTLSClientKey : Contents of the client.key file (null if you want to delete the existing data). Required",
+		"Enable": "Enable true if MQTT connection is enabled, false otherwise",
+	},
+	"ShellyTLSClientKeyConfig": {
+		"":       "ShellyTLSClientKeyConfig Shelly TLS Client Key config https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration",
+		"Data":   "Data is used by the following methods: PutUserCA : Contents of the PEM file (null if you want to delete the existing data). Required PutTLSClientCert : Contents of the client.crt file (null if you want to delete the existing data). Required PutTLSClientKey : Contents of the client.key file (null if you want to delete the existing data). Required",
+		"Enable": "Enable true if MQTT connection is enabled, false otherwise",
+	},
+	"ShellyTemplate": {
+		"": "ShellyTemplate a ShellyConfig in YAML (or JSON) form with placeholders. Placeholders are either Go templates such as {{ .name }} or {{ .name | quote }} or ${name} references. Go templates are executed first as text, then ${name} references are replaced in the parsed YAML so that a value such as \"Kitchen #2\" or \"on\" is never read as YAML. Rendering fails if a variable is missing.",
+	},
+	"ShellyTemplateVariables": {
+		"": "ShellyTemplateVariables variables for rendering a ShellyTemplate for a number of devices. Example: vars: mqtt_server: broker.local:1883 devices: plug-1: name: Plug 1 ip: 192.168.1.21 Vars are shared by all devices and can be overridden per device. The variable device is set to the device key unless it is set explicitly.",
+	},
+	"ShellyUpdateConfig": {
+		"":      "ShellyUpdateConfig Shelly firmware update config https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration",
+		"Stage": "Stage is used by the following methods: Update : The type of the new version - either stable or beta. By default updates to stable version. Optional",
+		"Url":   "Url is used by the following methods: Update : Url address of the update. Optional",
+	},
+	"ShellyUpdateOptions": {
+		"":         "ShellyUpdateOptions options for Shelly UpdateAndWait",
+		"Progress": "Progress is called for every ota event (ota_begin, ota_progress, ota_success, ota_error) reported by the device. Optional",
+		"Stage":    "Stage of the update, either stable or beta. Default is stable",
+		"Timeout":  "Timeout maximum time to wait for the update to complete. Optional",
+	},
+	"ShellyUserCAConfig": {
+		"":       "ShellyUserCAConfig Shelly UserCA config https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration",
+		"Data":   "Data is used by the following methods: PutUserCA : Contents of the PEM file (null if you want to delete the existing data). Required PutTLSClientCert : Contents of the client.crt file (null if you want to delete the existing data). Required PutTLSClientKey : Contents of the client.key file (null if you want to delete the existing data). Required",
+		"Enable": "Enable true if MQTT connection is enabled, false otherwise",
+	},
+	"SwitchAenergy": {
+		"":         "SwitchAenergy information about the active energy counter (shown if applicable) https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Switch#status",
+		"ByMinute": "ByMinute energy consumption by minute (in Milliwatt-hours) for the last three minutes (the lower the index of the element in the array, the closer to the current moment the minute)",
+		"MinuteTs": "MinuteTs Unix timestamp of the first second of the last minute (in UTC)",
+		"Total":    "Total energy consumed in Watt-hours",
+	},
+	"SwitchConfig": {
+		"":                         "SwitchConfig configuration of the Switch component contains information about the input mode, the timers and the protection settings of the chosen switch instance. To Get/Set the configuration of the Switch component its id must be specified. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Switch#configuration",
+		"AutoOff":                  "AutoOff True if the \"Automatic OFF\" function is enabled, false otherwise",
+		"AutoOffDelay":             "AutoOffDelay Seconds to pass until the component is switched back off",
+		"AutoOn":                   "AutoOn True if the \"Automatic ON\" function is enabled, false otherwise",
+		"AutoOnDelay":              "AutoOnDelay Seconds to pass until the component is switched back on",
+		"AutorecoverVoltageErrors": "AutorecoverVoltageErrors True if switch output state should be restored after over/undervoltage error is cleared, false otherwise (shown if applicable)",
+		"CurrentLimit":             "CurrentLimit Number, limit (in Amperes) over which overcurrent condition occurs (shown if applicable)",
+		"ID":                       "ID Id of the Switch component instance",
+		"InMode":                   "InMode range of values: momentary, follow, flip, detached",
+		"InitialState":             "InitialState range of values: off, on, restore_last, match_input",
+		"InputID":                  "InputID Id of the Input component which controls the Switch. Applicable only to Pro1 and Pro1PM devices. Valid values: 0, 1",
+		"Name":                     "Name of the switch instance",
+		"PowerLimit":               "PowerLimit Limit (in Watts) over which overpower condition occurs (shown if applicable)",
+		"UndervoltageLimit":        "UndervoltageLimit Limit (in Volts) under which undervoltage condition occurs (shown if applicable)",
+		"VoltageLimit":             "VoltageLimit Limit (in Volts) over which overvoltage condition occurs (shown if applicable)",
+	},
+	"SwitchStatus": {
+		"":               "SwitchStatus status of the Switch component contains information about the temperature, voltage, energy level and other physical characteristics of the switch instance. To obtain the status of the Switch component its id must be specified. For switches with power metering capabilities the status payload contains an additional set of properties with information about instantaneous power, supply voltage parameters and energy counters. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Switch#status",
+		"Aenergy":        "Aenergy information about the active energy counter (shown if applicable)",
+		"Apower":         "Apower last measured instantaneous active power (in Watts) delivered to the attached load (shown if applicable)",
+		"Current":        "Current last measured current in Amperes (shown if applicable)",
+		"Errors":         "Error conditions occurred. May contain overtemp, overpower, overvoltage, undervoltage, (shown if at least one error is present)",
+		"ID":             "ID Id of the Switch component instance",
+		"Output":         "Output true if the output channel is currently on, false otherwise",
+		"PowerFactor":    "PowerFactor last measured power factor (shown if applicable)",
+		"Source":         "Source of the last command, for example: init, WS_in, http, ...",
+		"Temperature":    "Temperature information about the temperature",
+		"TimerDuration":  "TimerDuration duration of the timer in seconds (shown if the timer is triggered)",
+		"TimerStartedAt": "TimerStartedAt Unix timestamp, start time of the timer (in UTC) (shown if the timer is triggered)",
+		"Voltage":        "Voltage last measured voltage in Volts (shown if applicable)",
+	},
+	"SwitchTemperature": {
+		"":   "SwitchTemperature System component object https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#status",
+		"TC": "TC temperature in Celsius (null if temperature is out of the measurement range)",
+		"TF": "TF temperature in Fahrenheit (null if temperature is out of the measurement",
+	},
+	"SystemAvailableUpdates": {
+		"":       "SystemAvailableUpdates Information about available updates, similar to the one returned by Shelly.CheckForUpdate (empty object: {}, if no updates available). This information is automatically updated every 24 hours. Note that build_id and url for an update are not displayed here https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys/#status",
+		"Beta":   "Beta shown only if beta update is available",
+		"Stable": "Stable version of the new firmware. Shown only if stable update is available",
+	},
+	"SystemConfig": {
+		"":         "SystemConfig System component config https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration",
+		"CfgRev":   "CfgRev Configuration revision. This number will be incremented for every configuration change of a device component. If the new config value is the same as the old one there will be no change of this property. Can not be modified explicitly by a call to Sys.SetConfig",
+		"Debug":    "Debug configuration of the device's debug logs.",
+		"Device":   "Device information about the device",
+		"Location": "Location information about the current location of the device",
+		"RPCUDP":   "RPCUDP configuration for the RPC over UDP",
+		"Sntp":     "Sntp configuration for the sntp server",
+		"UIData":   "UIData user interface data",
+	},
+	"SystemDebug": {
+		"":          "DebugConfig Configuration of the device's debug logs https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration https://shelly-api-docs.shelly.cloud/gen2/General/DebugLogs",
+		"Mqtt":      "Mqtt configuration of logs streamed over MQTT",
+		"UDP":       "UDP Configuration of logs streamed over UDP",
+		"Websocket": "Websocket configuration of logs streamed over websocket. Attention: Access to log streams over websocket is not restricted, even when authentication is enabled!",
+	},
+	"SystemDevice": {
+		"":             "SystemDevice information about the device https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration",
+		"AddonType":    "AddonType enable/disable addon board (if supported). Range of values: sensor; null to disable.",
+		"Discoverable": "Discoverable if true, device is shown in 'Discovered devices'. If false, the device is hidden.",
+		"EcoMode":      "EcoMode experimental Decreases power consumption when set to true, at the cost of reduced execution speed and increased network latency",
+		"FwID":         "FwID read-only build identifier of the current firmware image",
+		"MAC":          "MAC read-only base MAC address of the device",
+		"Name":         "Name of the device",
+		"Profile":      "Profile name of the device profile (only applicable for multi-profile devices)",
+	},
+	"SystemLocation": {
+		"":    "SystemLocationConfig Information about the current location of the device https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration",
+		"Lat": "Lat latitude in degrees (null if unavailable)",
+		"Lon": "Lon longitude in degrees (null if unavailable)",
+		"Tz":  "Timezone (null if unavailable)",
+	},
+	"SystemMqtt": {
+		"": "SystemMqtt Configuration of logs streamed over MQTT https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration",
+	},
+	"SystemRPCUDP": {
+		"":           "SystemRPCUDP configuration for the RPC over UDP https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration",
+		"DstAddr":    "DstAddr destination IP address",
+		"ListenPort": "ListenPort port number for inbound UDP RPC channel, null disables. Restart is required for changes to apply",
+	},
+	"SystemSntp": {
+		"":       "SntpConfig configuration for the sntp server https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration",
+		"Server": "Server name of the sntp server",
+	},
+	"SystemStatus": {
+		"":                 "SystemStatus status contains information about network state, system time and other common attributes of the Shelly device. Presence of some keys is optional, depending on the underlying hardware components. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#Status",
+		"AvailableUpdates": "AvailableUpdates Information about available updates, similar to the one returned by Shelly.CheckForUpdate (empty object: {}, if no updates available). This information is automatically updated every 24 hours. Note that build_id and url for an update are not displayed here",
+		"CfgRev":           "CfgRev Configuration revision number",
+		"FsFree":           "FsFree Size of the free file system in Bytes",
+		"FsSize":           "FsSize Total size of the file system in Bytes",
+		"KvsRev":           "KvsRev KVS (Key-Value Store) revision number",
+		"MAC":              "MAC address of the device",
+		"RAMFree":          "RAMFree Size of the free RAM in the system in Bytes",
+		"RAMSize":          "RAMSize Total size of the RAM in the system in Bytes",
+		"RestartRequired":  "RestartRequired true if restart is required, false otherwise",
+		"ScheduleRev":      "ScheduleRev Schedules revision number, present if schedules are enabled",
+		"Time":             "Time Current time in the format HH:MM (24-hour time format in the current timezone with leading zero). null when time is not synced from NTP server.",
+		"Unixtime":         "Unixtime Unix timestamp (in UTC), null when time is not synced from NTP server.",
+		"Uptime":           "Uptime Time in seconds since last reboot",
+		"WakeupPeriod":     "WakeupPeriod Period (in seconds) at which device wakes up and sends \"keep-alive\" packet to cloud, readonly. Count starts from last full wakeup",
+		"WakeupReason":     "WakeupReason Information about boot type and cause (only for battery-operated devices)",
+		"WebhookRev":       "WebhookRev Webhooks revision number, present if webhooks are enabled",
+	},
+	"SystemUDP": {
+		"": "SystemUDP Configuration of logs streamed over UDP. Used by component System. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration",
+	},
+	"SystemUIData": {
+		"": "SystemUIData user interface data. Used by component System. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration",
+	},
+	"SystemWakeupReason": {
+		"":      "SystemWakeupReason information about boot type and cause (only for battery-operated devices) https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys",
+		"Boot":  "Boot type, one of: poweron, software_restart, deepsleep_wake, internal (e.g. brownout detection, watchdog timeout, etc.), unknown",
+		"Cause": "Cause one of: button, usb, periodic, status_update, alarm, alarm_test, undefined (in case of deep sleep, reset was not caused by exit from deep sleep)",
+	},
+	"SystemWebsocket": {
+		"":       "SystemWebsocket Configuration of logs streamed over websocket. Attention: Access to log streams over websocket is not restricted, even when authentication is enabled! https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration",
+		"Enable": "True if enabled, false otherwise",
+	},
+	"UpdateProgress": {
+		"":        "UpdateProgress progress of a firmware update as reported by the device",
+		"Event":   "Event ota_begin, ota_progress, ota_success or ota_error",
+		"Message": "Message reported by the device",
+		"Percent": "Percent progress in percent (only for ota_progress)",
+	},
+	"UpdateResult": {
+		"":            "UpdateResult result of UpdateAndWait",
+		"FromVersion": "FromVersion firmware version before the update",
+		"Reason":      "Reason the update was skipped or failed",
+		"Stage":       "Stage of the update",
+		"Status":      "Status updated, skipped or failed",
+		"ToVersion":   "ToVersion firmware version after the update or the version offered if the update failed",
+	},
+	"UpdatesReport": {
+		"": "UpdatesReport checks for new firmware version for the device and returns information about it. If no update is available returns empty JSON object as result. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellycheckforupdate",
+	},
+	"ValidationError": {
+		"": "ValidationError is returned by Validate and holds all violations found",
+	},
+	"Violation": {
+		"":        "Violation a single validation failure",
+		"Message": "Message describing the problem",
+		"Path":    "Path of the field using the JSON names, for example wifi.sta.netmask or switch:0.in_mode",
+	},
+	"Webhook": {
+		"":              "Webhook component https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Webhook/#webhookcreate & https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Webhook/#webhookupdate",
+		"ActiveBetween": "ActiveBetween the first element indicates the start of the period during which the webhook will be active, the second indicates the end of that period. Both start and end are strings in the format HH:MM, where HH and MM are hours and minutes with optional leading zeros. To clear active_between its value should be set to empty array or null. When active_between is empty, this attribute is not visible in Webhook.List and the webhook is active all the time. Optional",
+		"Cid":           "Cid Id of the component Required",
+		"Condition":     "Condition hook trigger condition associated with event. Optional",
+		"Enable":        "Enable true to be enabled, false otherwise. It is false by default. Optional",
+		"Event":         "Event which will trigger the execution of the webhook. Valid events are listed by Webhook.ListSupported. Example values: switch.on, input.toggle_off. Required",
+		"ID":            "ID of the webhook",
+		"Name":          "Name user-defined name for the webhook instance. Optional",
+		"RepeatPeriod":  "RepeatPeriod minimum interval for invocations of the hook. If set to negative the hook will be invoked only once when the condition changes from false to true. If set to 0 the hook will be invoked every time the triggering event occurs. Default is 0.Optional",
+		"SslCa":         "SslCa type of the TCP sockets: null : Plain TCP connection user_ca.pem : TLS connection verified by the user-provided CA ca.pem : TLS connection verified by the built-in CA bundle. Optional",
+		"URLs":          "URLs containing url addresses that will be called when the webhook event occurs. Each url address is limited to 300 characters and the total number of url addresses associate with one webhook is 5. At least one url address is Required",
+	},
+	"WebsocketConfig": {
+		"":       "WebsocketConfig configuration https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Ws#configuration",
+		"Enable": "Enable true if websocket outbound connection is enabled, false otherwise",
+		"Server": "Server name of the server to which the device is connected. When prefixed with wss:// a TLS socket will be used",
+		"SslCa":  "SslCa type of the TCP sockets",
+	},
+	"WebsocketStatus": {
+		"":          "WebsocketStatus status https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Ws#status",
+		"Connected": "Connected true if device is connected to a websocket outbound connection or false otherwise.",
+	},
+	"WifiAPClient": {
+		"": "WifiAPClient WiFi component object https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi",
+	},
+	"WifiAPClients": {
+		"": "WifiAPClients Wifi AP Clients",
+	},
+	"WifiAPConfig": {
+		"":              "WifiAPConfig WiFi component object https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration",
+		"Enable":        "Enable true if the access point is enabled, false otherwise",
+		"IsOpen":        "IsOpen True if the access point is open, false otherwise",
+		"Pass":          "Pass password for the ssid, writeonly. Must be provided if you provide ssid",
+		"RangeExtender": "RangeExtender range extender configuration object, available only when range extender functionality is present.",
+		"SSID":          "SSID readonly SSID of the access point",
+	},
+	"WifiConfig": {
+		"":     "WifiConfig configuration of the WiFi component contains information about the access point of the device, the network stations and the roaming settings. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration",
+		"Ap":   "Ap Information about the access point",
+		"Roam": "Roam WiFi roaming configuration",
+		"Sta":  "Sta information about the sta configuration",
+		"Sta1": "Sta1 information about the sta configuration",
+	},
+	"WifiNet": {
+		"": "Scan WiFi component object https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi",
+	},
+	"WifiRangeExtenderConfig": {
+		"": "WifiRangeExtenderConfig Range extender configuration object, available only when range extender functionality is present. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration",
+	},
+	"WifiRoamConfig": {
+		"":              "WifiRoamConfig WiFi roaming configuration https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration",
+		"Interval":      "Interval at which to scan for better access points. Enabled if set to positive number, disabled if set to 0. Default value: 60",
+		"RSSIThreshold": "RSSIThreshold - when reached will trigger the access point roaming. Default value: -80",
+	},
+	"WifiSTAConfig": {
+		"":           "WifiSTAConfig WiFi component object https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration",
+		"Enable":     "Enable True if the configuration is enabled, false otherwise",
+		"Gateway":    "Gateway to use when ipv4mode is static",
+		"IP":         "IP Ip to use when ipv4mode is static",
+		"Ipv4Mode":   "Ipv4Mode IPv4 mode. Range of values: dhcp, static",
+		"IsOpen":     "IsOpen true if the network is open, i.e. no password is set, false otherwise, readonly",
+		"Nameserver": "Nameserver to use when ipv4mode is static",
+		"Netmask":    "Netmask to use when ipv4mode is static",
+		"Pass":       "Password for the ssid, writeonly. Must be provided if you provide ssid",
+		"SSID":       "SSID of the network",
+	},
+	"WifiScanResults": {
+		"": "WifiScanResults Wifi Scan Results",
+	},
+	"WifiStatus": {
+		"":              "WifiStatus status of the WiFi component contains information about the state of the WiFi connection of the device. https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#status",
+		"ApClientCount": "ApClientCount Number of clients connected to the access point. Present only when AP is enabled and range extender functionality is present and enabled.",
+		"RSSI":          "Rssi Strength of the signal in dBms",
+		"SSID":          "Ssid of the network (null if disconnected)",
+		"StaIP":         "StaIP Ip of the device in the network (null if disconnected)",
+		"Status":        "Status of the connection. Range of values: disconnected, connecting, connected, got ip",
+	},
+}
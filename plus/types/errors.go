@@ -1,9 +1,29 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 )
 
+var (
+	// ErrTimeout the device did not respond within the send timeout or before the deadline of the context
+	ErrTimeout = errors.New("timeout waiting for response")
+	// ErrNotConnected the websocket to the device is not connected. Returned together with ErrTimeout if
+	// the connection was down when the send timeout expired.
+	ErrNotConnected = errors.New("not connected")
+	// ErrClosed the handle or client was closed while waiting for the response
+	ErrClosed = errors.New("channel closed")
+	// ErrAuthRequired the device requires authentication and no credentials are configured. A device error
+	// with code 401 matches as well.
+	ErrAuthRequired = errors.New("authentication required")
+	// ErrMalformedResponse the response could not be decoded or is missing the result
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrRolloutHalted the rollout halted because a canary failed or too many devices failed
+	ErrRolloutHalted = errors.New("rollout halted")
+	// ErrAlreadyUpToDate the device already runs the requested firmware version
+	ErrAlreadyUpToDate = errors.New("already up to date")
+)
+
 // Error Shelly Error
 type Error struct {
 	Code    int    `json:"code,omitempty" yaml:"code,omitempty"`
@@ -25,6 +45,67 @@ func (t *Error) Error() string {
 	return fmt.Sprintf("status %d: err %s", t.Code, t.Message)
 }
 
+// Is returns true if target is ErrAuthRequired and the code is 401 so that errors.Is can be used
+// for authentication failures reported by the device
+func (t *Error) Is(target error) bool {
+	return target == ErrAuthRequired && t.Code == ErrorCodeUnauthorized
+}
+
+// AsError returns the device error wrapped in err
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) && e != nil {
+		return e, true
+	}
+	return nil, false
+}
+
+// HasErrorCode returns true if err wraps a device error with code
+func HasErrorCode(err error, code int) bool {
+	e, ok := AsError(err)
+	return ok && e.Code == code
+}
+
+// IsInvalidArgument returns true if err wraps a device error with code -103
+func IsInvalidArgument(err error) bool {
+	return HasErrorCode(err, ErrorCodeInvalidArgument)
+}
+
+// IsDeadlineExceeded returns true if err wraps a device error with code -104
+func IsDeadlineExceeded(err error) bool {
+	return HasErrorCode(err, ErrorCodeDeadLineExceeded)
+}
+
+// IsNotFound returns true if err wraps a device error with code -105
+func IsNotFound(err error) bool {
+	return HasErrorCode(err, ErrorCodeNotFound)
+}
+
+// IsResourceExhausted returns true if err wraps a device error with code -108
+func IsResourceExhausted(err error) bool {
+	return HasErrorCode(err, ErrorCodeResourceExhausted)
+}
+
+// IsFailedPrecondition returns true if err wraps a device error with code -109
+func IsFailedPrecondition(err error) bool {
+	return HasErrorCode(err, ErrorCodeFailedPrecondition)
+}
+
+// IsUnavailable returns true if err wraps a device error with code -114
+func IsUnavailable(err error) bool {
+	return HasErrorCode(err, ErrorCodeUnAvailable)
+}
+
+// IsNotImplemented returns true if err wraps a device error with code 404
+func IsNotImplemented(err error) bool {
+	return HasErrorCode(err, ErrorCodeNotImplemented)
+}
+
+// IsAuthRequired returns true if credentials are missing or the device rejected them
+func IsAuthRequired(err error) bool {
+	return errors.Is(err, ErrAuthRequired)
+}
+
 type ErrorCode int
 
 var getErrorCodeMap = func() map[int]string {
@@ -36,6 +117,7 @@ var getErrorCodeMap = func() map[int]string {
 		ErrorCodeFailedPrecondition: "precondition for a requested action is not satisfied. For example, when you try to turn a switch on in a situation of overpower condition, or when a reboot has been scheduled and the device is shutting down",
 		ErrorCodeUnAvailable:        "service is unavailable. The service can be internal - a sensor could be unreachable, or external. External services are - timezone information, firmware update or HTTP requests in Scripts.",
 		ErrorCodeNotImplemented:     "method is not implemented on this device or caller is not authorized",
+		ErrorCodeUnauthorized:       "authentication is required or the credentials were rejected",
 	}
 }
 
@@ -47,6 +129,7 @@ const (
 	ErrorCodeFailedPrecondition = -109
 	ErrorCodeUnAvailable        = -114
 	ErrorCodeNotImplemented     = 404
+	ErrorCodeUnauthorized       = 401
 )
 
 var errorCodeMap = getErrorCodeMap()
@@ -59,3 +59,19 @@ func (t *EthernetConfig) Sanatize() {
 	}
 
 }
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *EthernetConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *EthernetConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	v.ipv4Config(path, t.Ipv4Mode, t.IP, t.Netmask, t.Gateway, t.Nameserver)
+}
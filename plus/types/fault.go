@@ -0,0 +1,86 @@
+package types
+
+import (
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+// FaultKind kind of fault injected into a request by the fault proxy or injector
+type FaultKind string
+
+const (
+	// FaultLatency delays the request by Fault.Latency before it is passed to the device
+	FaultLatency FaultKind = "latency"
+	// FaultDropRequest the request is not passed to the device and the call fails with ErrTimeout
+	FaultDropRequest FaultKind = "drop_request"
+	// FaultDropResponse the request is passed to the device but the response is lost and the call fails
+	// with ErrTimeout
+	FaultDropResponse FaultKind = "drop_response"
+	// FaultDuplicate the response is delivered twice
+	FaultDuplicate FaultKind = "duplicate"
+	// FaultDisconnect the request is passed to the device and the connection is closed before the
+	// response arrives. The client has to reconnect.
+	FaultDisconnect FaultKind = "disconnect"
+	// FaultExpiredNonce the auth is removed from the request so that the device responds with an auth
+	// challenge as if the nonce expired
+	FaultExpiredNonce FaultKind = "expired_nonce"
+	// FaultOutOfOrder the response is held back and delivered after the response of the next request or,
+	// if no other response follows, after a short hold
+	FaultOutOfOrder FaultKind = "out_of_order"
+)
+
+// Fault fault injected into a single request
+type Fault struct {
+	// Kind of the fault
+	Kind FaultKind `json:"kind" yaml:"kind"`
+	// Method if set a scripted fault is injected into the next request for this method. Optional
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	// Latency added before the request is passed to the device. Used by every kind; required for
+	// FaultLatency
+	Latency time.Duration `json:"latency,omitempty" yaml:"latency,omitempty"`
+}
+
+// Clone return copy
+func (t *Fault) Clone() *Fault {
+	c := &Fault{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// FaultPolicy decides which fault, if any, is injected into a request. It returns nil for no fault.
+type FaultPolicy interface {
+	Fault(request *Request) *Fault
+}
+
+// RandomFaultConfig rates of a seeded random fault policy. Each rate is the probability from 0 to 1 that
+// a request gets the fault. At most one fault other than latency is injected per request.
+type RandomFaultConfig struct {
+	// Seed of the random generator. The same seed yields the same faults for the same requests
+	Seed int64 `json:"seed" yaml:"seed"`
+	// Methods if set only requests for these methods get faults. Optional
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	// MinLatency minimum latency added to every request. Optional
+	MinLatency time.Duration `json:"min_latency,omitempty" yaml:"min_latency,omitempty"`
+	// MaxLatency maximum latency added to every request. Optional
+	MaxLatency time.Duration `json:"max_latency,omitempty" yaml:"max_latency,omitempty"`
+	// DropRequest rate of FaultDropRequest
+	DropRequest float64 `json:"drop_request,omitempty" yaml:"drop_request,omitempty"`
+	// DropResponse rate of FaultDropResponse
+	DropResponse float64 `json:"drop_response,omitempty" yaml:"drop_response,omitempty"`
+	// Duplicate rate of FaultDuplicate
+	Duplicate float64 `json:"duplicate,omitempty" yaml:"duplicate,omitempty"`
+	// Disconnect rate of FaultDisconnect
+	Disconnect float64 `json:"disconnect,omitempty" yaml:"disconnect,omitempty"`
+	// ExpiredNonce rate of FaultExpiredNonce
+	ExpiredNonce float64 `json:"expired_nonce,omitempty" yaml:"expired_nonce,omitempty"`
+	// OutOfOrder rate of FaultOutOfOrder
+	OutOfOrder float64 `json:"out_of_order,omitempty" yaml:"out_of_order,omitempty"`
+}
+
+// Clone return copy
+func (t *RandomFaultConfig) Clone() *RandomFaultConfig {
+	c := &RandomFaultConfig{}
+	copier.CopyWithOption(c, t, copier.Option{DeepCopy: true})
+	return c
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"github.com/jinzhu/copier"
+)
+
+// FirmwareImage firmware image served by the firmware server
+type FirmwareImage struct {
+	// Model of the device the image is built for, for example SNSW-001X16EU
+	Model string `json:"model" yaml:"model"`
+	// App name of the firmware, for example Plus1PM
+	App string `json:"app" yaml:"app"`
+	// Version of the firmware, for example 1.0.3
+	Version string `json:"version" yaml:"version"`
+	// Path of the image relative to the firmware directory
+	Path string `json:"path" yaml:"path"`
+	// Size of the image in bytes
+	Size int64 `json:"size" yaml:"size"`
+}
+
+// Clone return copy
+func (t *FirmwareImage) Clone() *FirmwareImage {
+	c := &FirmwareImage{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// FirmwareServerConfig config for the firmware server
+type FirmwareServerConfig struct {
+	// Dir directory holding the images as <model>/<app>/<version>.zip
+	Dir string `json:"dir" yaml:"dir"`
+	// Addr address the server listens on, for example 192.168.1.10:8080. Default is :8080
+	Addr string `json:"addr,omitempty" yaml:"addr,omitempty"`
+	// BaseURL URL the devices use to reach the server, for example http://192.168.1.10:8080. Optional if
+	// Addr holds a host; required if the server listens on all interfaces
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+}
@@ -0,0 +1,138 @@
+//go:build ignore
+
+// gen_docs extracts the doc comments of the types in this package and writes them to docs_generated.go
+// so that they are available at runtime, for example as JSON Schema descriptions.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const output = "docs_generated.go"
+
+func main() {
+
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return fi.Name() != output && !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	docs := map[string]map[string]string{}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range gen.Specs {
+
+					typeSpec := spec.(*ast.TypeSpec)
+
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok || !typeSpec.Name.IsExported() {
+						continue
+					}
+
+					fields := map[string]string{}
+
+					doc := typeSpec.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+
+					if text := clean(doc); text != "" {
+						fields[""] = text
+					}
+
+					for _, field := range structType.Fields.List {
+
+						text := clean(field.Doc)
+						if text == "" {
+							text = clean(field.Comment)
+						}
+
+						if text == "" {
+							continue
+						}
+
+						for _, name := range field.Names {
+							fields[name.Name] = text
+						}
+					}
+
+					if len(fields) > 0 {
+						docs[typeSpec.Name.Name] = fields
+					}
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by gen_docs.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package types\n\n")
+	buf.WriteString("// typeDocs doc comments of the types in this package. The outer key is the type name, the inner key\n")
+	buf.WriteString("// is the Go field name or \"\" for the doc comment of the type itself.\n")
+	buf.WriteString("var typeDocs = map[string]map[string]string{\n")
+
+	for _, typeName := range sortedKeys(docs) {
+		fmt.Fprintf(&buf, "%s: {\n", strconv.Quote(typeName))
+		for _, fieldName := range sortedKeys(docs[typeName]) {
+			fmt.Fprintf(&buf, "%s: %s,\n", strconv.Quote(fieldName), strconv.Quote(docs[typeName][fieldName]))
+		}
+		buf.WriteString("},\n")
+	}
+
+	buf.WriteString("}\n")
+
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(output, b, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// clean joins the lines of the comment into a single line
+func clean(doc *ast.CommentGroup) string {
+
+	if doc == nil {
+		return ""
+	}
+
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
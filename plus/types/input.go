@@ -69,3 +69,24 @@ func (t *InputConfig) Sanatize() {
 	}
 
 }
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *InputConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *InputConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	v.enum(joinPath(path, "type"), t.Type, "switch", "button", "analog")
+	v.rangeFloat(joinPath(path, "report_thr"), t.ReportThreshold, 1.0, 50.0)
+
+	if t.Type != nil && *t.Type != "analog" && t.ReportThreshold != nil {
+		v.add(joinPath(path, "report_thr"), "only applies to type analog")
+	}
+}
@@ -79,3 +79,24 @@ func (t *LightConfig) Sanatize() {
 		return
 	}
 }
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *LightConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *LightConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	v.enum(joinPath(path, "initial_state"), t.InitialState, "off", "on", "restore_last", "match_input")
+	v.nonNegative(joinPath(path, "auto_on_delay"), t.AutoOnDelay)
+	v.nonNegative(joinPath(path, "auto_off_delay"), t.AutoOffDelay)
+	v.rangeFloat(joinPath(path, "default.brightness"), t.DefaultBrightness, 0, 100)
+	v.rangeFloat(joinPath(path, "night_mode.brightness"), t.NightModeBrightness, 0, 100)
+	v.activeBetween(joinPath(path, "night_mode.active_between"), t.NightModeActiveBetween)
+}
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"time"
+)
+
+// Metrics receives measurements of the SDK itself, such as RPC latency and reconnects. Implementations
+// must be safe for concurrent use as one instance is usually shared by the clients of many devices.
+type Metrics interface {
+	// RPC is called after every RPC attempt with its duration and error, nil on success
+	RPC(hostname, method string, duration time.Duration, err error)
+	// AuthChallenge is called every time the device responds with an auth challenge
+	AuthChallenge(hostname string)
+	// Connect is called every time a connection to the device is established. The first call is the
+	// initial connection; every further call is a reconnect.
+	Connect(hostname string, count int)
+	// EgressQueueDepth is called with the number of requests waiting to be written to the device
+	EgressQueueDepth(hostname string, depth int)
+}
+
+// MetricsConfig is optionally implemented by the config passed to plus.New to collect metrics
+type MetricsConfig interface {
+	GetMetrics() Metrics
+}
+
+// NopMetrics Metrics that discards all measurements. It is used if no metrics are configured.
+type NopMetrics struct{}
+
+func (NopMetrics) RPC(hostname, method string, duration time.Duration, err error) {}
+
+func (NopMetrics) AuthChallenge(hostname string) {}
+
+func (NopMetrics) Connect(hostname string, count int) {}
+
+func (NopMetrics) EgressQueueDepth(hostname string, depth int) {}
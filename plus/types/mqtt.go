@@ -1,6 +1,8 @@
 package types
 
 import (
+	"strings"
+
 	"github.com/jinzhu/copier"
 )
 
@@ -104,3 +106,36 @@ func (t *MqttConfig) Sanatize() {
 	}
 
 }
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *MqttConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *MqttConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	if t.Enable {
+		v.required(joinPath(path, "server"), t.Server != nil, "when enable is true")
+	}
+
+	v.hostPort(joinPath(path, "server"), t.Server)
+	v.enum(joinPath(path, "ssl_ca"), t.SslCa, "user_ca.pem", "ca.pem")
+
+	if t.TopicPrefix != nil {
+		v.maxLength(joinPath(path, "topic_prefix"), t.TopicPrefix, 300)
+
+		if strings.HasPrefix(*t.TopicPrefix, "$") {
+			v.add(joinPath(path, "topic_prefix"), "must not start with $")
+		}
+
+		if strings.ContainsAny(*t.TopicPrefix, "#+%?") {
+			v.add(joinPath(path, "topic_prefix"), "must not contain #, +, %% or ?")
+		}
+	}
+}
@@ -14,3 +14,59 @@ type MessageHandler interface {
 	Close()
 	IsAuthEnabled() bool
 }
+
+// ConnectionNotifier is optionally implemented by a MessageHandlerFactory that keeps a persistent
+// connection to the device. It allows callers to wait for the device to come back after a reboot.
+type ConnectionNotifier interface {
+	// ConnectCount returns the number of times a connection to the device has been established
+	ConnectCount() int
+	// WaitConnect blocks until a connection has been established more than count times
+	WaitConnect(ctx context.Context, count int) error
+}
+
+// NotificationNotifier is optionally implemented by a MessageHandlerFactory that can receive
+// notifications from the device, such as NotifyEvent.
+type NotificationNotifier interface {
+	// Subscribe registers fn to be called for every notification and returns a function that removes
+	// the subscription. fn is called from the receiving goroutine and must not block.
+	Subscribe(fn func(*Notification)) func()
+}
+
+// SendFunc sends a request and returns the raw response
+type SendFunc func(ctx context.Context, request *Request) ([]byte, error)
+
+// Interceptor is called for every request sent by a component client. It may inspect or modify the
+// request, call next to send it and inspect or replace the response and error. Interceptors are used
+// for logging, metrics, tracing and rate limiting.
+type Interceptor func(ctx context.Context, request *Request, next SendFunc) ([]byte, error)
+
+// InterceptorConfig is optionally implemented by the config passed to plus.New to register
+// interceptors. The first interceptor is the outermost.
+type InterceptorConfig interface {
+	GetInterceptors() []Interceptor
+}
+
+// SendInfo details of a request that are only known to the message handler. An interceptor passes an
+// empty SendInfo in the context with WithSendInfo and reads it after the request was sent; the message
+// handler fills it in if it finds one in the context.
+type SendInfo struct {
+	// Hostname of the device
+	Hostname string
+	// RequestID JSON-RPC ID of the request
+	RequestID int
+	// AuthRetry true if the request was sent again after an auth challenge
+	AuthRetry bool
+}
+
+type sendInfoKey struct{}
+
+// WithSendInfo returns a copy of ctx that carries info
+func WithSendInfo(ctx context.Context, info *SendInfo) context.Context {
+	return context.WithValue(ctx, sendInfoKey{}, info)
+}
+
+// SendInfoFromContext returns the SendInfo carried by ctx or nil
+func SendInfoFromContext(ctx context.Context) *SendInfo {
+	info, _ := ctx.Value(sendInfoKey{}).(*SendInfo)
+	return info
+}
@@ -0,0 +1,450 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/copier"
+)
+
+// writeOnlyPaths are config fields that the device will accept but never return. They can not be
+// compared with the current config; a change is planned whenever desired sets them to a value other
+// than a placeholder and is marked as unverifiable.
+var writeOnlyPaths = map[string]bool{
+	"auth.pass":            true,
+	"tls_client_cert.data": true,
+	"tls_client_key.data":  true,
+	"user_ca.data":         true,
+	"mqtt.pass":            true,
+	"wifi.ap.pass":         true,
+	"wifi.sta.pass":        true,
+	"wifi.sta1.pass":       true,
+}
+
+// rebootPaths are config paths (or path prefixes) that are known to require a reboot of the device
+// before the change takes effect. This is a best effort prediction, the device has the final say
+// and reports it with restart_required when the config is applied.
+var rebootPaths = []string{
+	"ble",
+	"cloud",
+	"eth",
+	"mqtt",
+	"ws",
+	"sys.device.profile",
+	"sys.rpc_udp.listen_port",
+}
+
+func isRebootPath(path string) bool {
+	for _, v := range rebootPaths {
+		if path == v || strings.HasPrefix(path, v+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Change is a single field level change between two configs
+type Change struct {
+	// Type of the change
+	Type ChangeType `json:"type,omitempty" yaml:"type,omitempty"`
+	// Path of the field using the JSON names, for example wifi.sta.ssid or switch:0.name
+	Path string `json:"path" yaml:"path"`
+	// Pointer RFC 6901 JSON pointer of the field in the old snapshot; only set by DiffConfig and DiffStatus
+	Pointer string `json:"pointer,omitempty" yaml:"pointer,omitempty"`
+	// Old value of the field; nil if not set or unknown
+	Old interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+	// New value of the field; nil if not set
+	New interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+	// RebootRequired true if the change is known to require a reboot
+	RebootRequired bool `json:"reboot_required,omitempty" yaml:"reboot_required,omitempty"`
+	// Unverifiable true if the field is write only and the change can not be compared with or verified
+	// against the device. Old and New are not set so that secrets are not shown.
+	Unverifiable bool `json:"unverifiable,omitempty" yaml:"unverifiable,omitempty"`
+}
+
+// Clone return copy
+func (t *Change) Clone() *Change {
+	c := &Change{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// String returns the change in human readable form
+func (t *Change) String() string {
+
+	s := fmt.Sprintf("%s: %s => %s", t.Path, formatValue(t.Old), formatValue(t.New))
+
+	if t.Unverifiable {
+		s = t.Path + ": (write only, can not be verified)"
+	}
+
+	if t.RebootRequired {
+		return s + " (reboot required)"
+	}
+
+	return s
+}
+
+func formatValue(v interface{}) string {
+
+	if v == nil {
+		return "<nil>"
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
+
+// ComponentPlan the changes planned for a single component
+type ComponentPlan struct {
+	// ID of the component instance (only for Light, Input and Switch)
+	ID *int `json:"id,omitempty" yaml:"id,omitempty"`
+	// Changes field level changes
+	Changes []*Change `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// Clone return copy
+func (t *ComponentPlan) Clone() *ComponentPlan {
+	c := &ComponentPlan{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+// HasChanges returns true if the component has one or more changes
+func (t *ComponentPlan) HasChanges() bool {
+
+	if t == nil {
+		return false
+	}
+
+	return len(t.Changes) > 0
+}
+
+// RebootRequired returns true if any change for the component is known to require a reboot
+func (t *ComponentPlan) RebootRequired() bool {
+
+	if t == nil {
+		return false
+	}
+
+	for _, v := range t.Changes {
+		if v.RebootRequired {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShellyPlan the difference between the current config of a device and a desired config. Only
+// components with changes are applied.
+type ShellyPlan struct {
+	Auth          *ComponentPlan   `json:"auth,omitempty" yaml:"auth,omitempty"`
+	TLSClientCert *ComponentPlan   `json:"tls_client_cert,omitempty" yaml:"tls_client_cert,omitempty"`
+	TLSClientKey  *ComponentPlan   `json:"tls_client_key,omitempty" yaml:"tls_client_key,omitempty"`
+	UserCA        *ComponentPlan   `json:"user_ca,omitempty" yaml:"user_ca,omitempty"`
+	Bluetooth     *ComponentPlan   `json:"ble,omitempty" yaml:"ble,omitempty"`
+	Cloud         *ComponentPlan   `json:"cloud,omitempty" yaml:"cloud,omitempty"`
+	Mqtt          *ComponentPlan   `json:"mqtt,omitempty" yaml:"mqtt,omitempty"`
+	Ethernet      *ComponentPlan   `json:"eth,omitempty" yaml:"eth,omitempty"`
+	System        *ComponentPlan   `json:"sys,omitempty" yaml:"sys,omitempty"`
+	Wifi          *ComponentPlan   `json:"wifi,omitempty" yaml:"wifi,omitempty"`
+	Websocket     *ComponentPlan   `json:"ws,omitempty" yaml:"ws,omitempty"`
+	Light         []*ComponentPlan `json:"light,omitempty" yaml:"light,omitempty"`
+	Input         []*ComponentPlan `json:"input,omitempty" yaml:"input,omitempty"`
+	Switch        []*ComponentPlan `json:"switch,omitempty" yaml:"switch,omitempty"`
+	// Desired the sanatized desired config the plan was made from
+	Desired *ShellyConfig `json:"-" yaml:"-"`
+}
+
+// NewShellyPlan returns the plan to get from the current config to the desired config. Components
+// that are nil in desired are left untouched, as are nil fields within a component. Write only fields
+// such as passwords are planned as unverifiable changes if desired sets them to a value other than a
+// placeholder. The components Auth, TLSClientCert, TLSClientKey and UserCA can not
+// be fully read back from the device; if they are set in desired they are always planned.
+func NewShellyPlan(current, desired *ShellyConfig) *ShellyPlan {
+
+	if current == nil {
+		current = &ShellyConfig{}
+	}
+
+	if desired == nil {
+		desired = &ShellyConfig{}
+	}
+
+	plan := &ShellyPlan{
+		Desired: desired,
+	}
+
+	if desired.Auth != nil {
+		plan.Auth = planComponent("auth", nil, current.Auth, desired.Auth)
+	}
+
+	if desired.TLSClientCert != nil {
+		plan.TLSClientCert = planComponent("tls_client_cert", nil, nil, desired.TLSClientCert)
+	}
+
+	if desired.TLSClientKey != nil {
+		plan.TLSClientKey = planComponent("tls_client_key", nil, nil, desired.TLSClientKey)
+	}
+
+	if desired.UserCA != nil {
+		plan.UserCA = planComponent("user_ca", nil, nil, desired.UserCA)
+	}
+
+	if desired.Bluetooth != nil {
+		plan.Bluetooth = planComponent("ble", nil, current.Bluetooth, desired.Bluetooth)
+	}
+
+	if desired.Cloud != nil {
+		plan.Cloud = planComponent("cloud", nil, current.Cloud, desired.Cloud)
+	}
+
+	if desired.Mqtt != nil {
+		plan.Mqtt = planComponent("mqtt", nil, current.Mqtt, desired.Mqtt)
+	}
+
+	if desired.Ethernet != nil {
+		plan.Ethernet = planComponent("eth", nil, current.Ethernet, desired.Ethernet)
+	}
+
+	if desired.System != nil {
+		plan.System = planComponent("sys", nil, current.System, desired.System)
+	}
+
+	if desired.Wifi != nil {
+		plan.Wifi = planComponent("wifi", nil, current.Wifi, desired.Wifi)
+	}
+
+	if desired.Websocket != nil {
+		plan.Websocket = planComponent("ws", nil, current.Websocket, desired.Websocket)
+	}
+
+	for _, v := range desired.Light {
+		id := v.ID
+		plan.Light = append(plan.Light, planComponent("light", &id, current.GetLight(id), v))
+	}
+
+	for _, v := range desired.Input {
+		id := v.ID
+		plan.Input = append(plan.Input, planComponent("input", &id, current.GetInput(id), v))
+	}
+
+	for _, v := range desired.Switch {
+		id := v.ID
+		plan.Switch = append(plan.Switch, planComponent("switch", &id, current.GetSwitch(id), v))
+	}
+
+	return plan
+}
+
+func planComponent(name string, id *int, current, desired interface{}) *ComponentPlan {
+
+	path := name
+	if id != nil {
+		path = fmt.Sprintf("%s:%d", name, *id)
+	}
+
+	plan := &ComponentPlan{
+		ID: id,
+	}
+
+	for _, change := range diffValues(path, "", reflect.StructField{}, reflect.ValueOf(current), reflect.ValueOf(desired), true) {
+		if writeOnlyPaths[stripID(change.Path)] {
+			value, ok := change.New.(string)
+			if !ok || value == "" || IsPlaceholder(value) {
+				continue
+			}
+			change.Old = nil
+			change.New = nil
+			change.Unverifiable = true
+		}
+		change.Pointer = ""
+		change.RebootRequired = isRebootPath(stripID(change.Path))
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	return plan
+}
+
+// stripID removes the instance ID from a path so that switch:1.name becomes switch.name
+func stripID(path string) string {
+
+	head, tail, found := strings.Cut(path, ".")
+
+	if name, _, ok := strings.Cut(head, ":"); ok {
+		head = name
+	}
+
+	if found {
+		return head + "." + tail
+	}
+
+	return head
+}
+
+// Clone return copy
+func (t *ShellyPlan) Clone() *ShellyPlan {
+	c := &ShellyPlan{}
+	copier.Copy(&c, &t)
+	return c
+}
+
+func (t *ShellyPlan) components() []*ComponentPlan {
+
+	components := []*ComponentPlan{t.Auth, t.TLSClientCert, t.TLSClientKey, t.UserCA, t.Bluetooth,
+		t.Cloud, t.Mqtt, t.Ethernet, t.System, t.Wifi, t.Websocket}
+
+	components = append(components, t.Light...)
+	components = append(components, t.Input...)
+	components = append(components, t.Switch...)
+
+	return components
+}
+
+// HasChanges returns true if one or more components have changes
+func (t *ShellyPlan) HasChanges() bool {
+
+	for _, v := range t.components() {
+		if v.HasChanges() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RebootRequired returns true if one or more changes are known to require a reboot
+func (t *ShellyPlan) RebootRequired() bool {
+
+	for _, v := range t.components() {
+		if v.RebootRequired() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Changes returns all changes sorted by path
+func (t *ShellyPlan) Changes() []*Change {
+
+	var changes []*Change
+
+	for _, v := range t.components() {
+		if v != nil {
+			changes = append(changes, v.Changes...)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+// Config returns the desired config reduced to the components that have changes
+func (t *ShellyPlan) Config() *ShellyConfig {
+
+	c := &ShellyConfig{}
+
+	if t.Desired == nil {
+		return c
+	}
+
+	if t.Auth.HasChanges() {
+		c.Auth = t.Desired.Auth
+	}
+
+	if t.TLSClientCert.HasChanges() {
+		c.TLSClientCert = t.Desired.TLSClientCert
+	}
+
+	if t.TLSClientKey.HasChanges() {
+		c.TLSClientKey = t.Desired.TLSClientKey
+	}
+
+	if t.UserCA.HasChanges() {
+		c.UserCA = t.Desired.UserCA
+	}
+
+	if t.Bluetooth.HasChanges() {
+		c.Bluetooth = t.Desired.Bluetooth
+	}
+
+	if t.Cloud.HasChanges() {
+		c.Cloud = t.Desired.Cloud
+	}
+
+	if t.Mqtt.HasChanges() {
+		c.Mqtt = t.Desired.Mqtt
+	}
+
+	if t.Ethernet.HasChanges() {
+		c.Ethernet = t.Desired.Ethernet
+	}
+
+	if t.System.HasChanges() {
+		c.System = t.Desired.System
+	}
+
+	if t.Wifi.HasChanges() {
+		c.Wifi = t.Desired.Wifi
+	}
+
+	if t.Websocket.HasChanges() {
+		c.Websocket = t.Desired.Websocket
+	}
+
+	for _, v := range t.Light {
+		if v.HasChanges() {
+			c.Light = append(c.Light, t.Desired.GetLight(*v.ID))
+		}
+	}
+
+	for _, v := range t.Input {
+		if v.HasChanges() {
+			c.Input = append(c.Input, t.Desired.GetInput(*v.ID))
+		}
+	}
+
+	for _, v := range t.Switch {
+		if v.HasChanges() {
+			c.Switch = append(c.Switch, t.Desired.GetSwitch(*v.ID))
+		}
+	}
+
+	return c
+}
+
+// String returns the plan as a human readable diff with one change per line
+func (t *ShellyPlan) String() string {
+
+	changes := t.Changes()
+
+	if len(changes) == 0 {
+		return "no changes"
+	}
+
+	var b strings.Builder
+
+	for _, v := range changes {
+		b.WriteString("~ ")
+		b.WriteString(v.String())
+		b.WriteString("\n")
+	}
+
+	if t.RebootRequired() {
+		b.WriteString("reboot required\n")
+	}
+
+	return b.String()
+}
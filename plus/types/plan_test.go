@@ -0,0 +1,88 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestNewShellyPlanWriteOnly(t *testing.T) {
+
+	current := &ShellyConfig{
+		Mqtt: &MqttConfig{Enable: true, Server: ptr("broker:1883")},
+		Wifi: &WifiConfig{Sta: &WifiSTAConfig{SSID: ptr("home"), Enable: true}},
+	}
+
+	tests := []struct {
+		name         string
+		desired      *ShellyConfig
+		changes      []string
+		unverifiable bool
+	}{
+		{
+			name:    "password set",
+			desired: &ShellyConfig{Mqtt: &MqttConfig{Enable: true, Server: ptr("broker:1883"), Pass: ptr("secret")}},
+			changes: []string{"mqtt.pass"}, unverifiable: true,
+		},
+		{
+			name:    "sta password set",
+			desired: &ShellyConfig{Wifi: &WifiConfig{Sta: &WifiSTAConfig{SSID: ptr("home"), Pass: ptr("secret"), Enable: true}}},
+			changes: []string{"wifi.sta.pass"}, unverifiable: true,
+		},
+		{
+			name:    "placeholder",
+			desired: &ShellyConfig{Mqtt: &MqttConfig{Enable: true, Server: ptr("broker:1883"), Pass: ptr(genericPassword)}},
+		},
+		{
+			name:    "password not set",
+			desired: &ShellyConfig{Mqtt: &MqttConfig{Enable: true, Server: ptr("broker:1883")}},
+		},
+		{
+			name:    "password and server",
+			desired: &ShellyConfig{Mqtt: &MqttConfig{Enable: true, Server: ptr("other:1883"), Pass: ptr("secret")}},
+			changes: []string{"mqtt.pass", "mqtt.server"}, unverifiable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			plan := NewShellyPlan(current, tt.desired)
+
+			var paths []string
+			unverifiable := false
+			for _, change := range plan.Changes() {
+				paths = append(paths, change.Path)
+				if change.Unverifiable {
+					unverifiable = true
+					if change.Old != nil || change.New != nil {
+						t.Errorf("%s: write only values must not be set in the plan", change.Path)
+					}
+				}
+			}
+
+			if strings.Join(paths, ",") != strings.Join(tt.changes, ",") {
+				t.Fatalf("changes = %v, want %v", paths, tt.changes)
+			}
+
+			if unverifiable != tt.unverifiable {
+				t.Errorf("unverifiable = %v, want %v", unverifiable, tt.unverifiable)
+			}
+
+			if plan.HasChanges() != (len(tt.changes) > 0) {
+				t.Errorf("HasChanges = %v", plan.HasChanges())
+			}
+
+			if len(tt.changes) > 0 && plan.Config().Mqtt == nil && plan.Config().Wifi == nil {
+				t.Errorf("component with changes is missing from the config to apply")
+			}
+
+			if strings.Contains(plan.String(), "secret") {
+				t.Errorf("plan shows the password: %s", plan.String())
+			}
+		})
+	}
+}
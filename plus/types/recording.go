@@ -0,0 +1,146 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// RecordingVersion is the version of the recording file format
+const RecordingVersion = 1
+
+// Recording request and response frames captured by the recording message handler factory
+type Recording struct {
+	// Version of the recording file format
+	Version int `json:"version" yaml:"version"`
+	// Exchanges in the order they were recorded
+	Exchanges []*RecordedExchange `json:"exchanges" yaml:"exchanges"`
+}
+
+// RecordedExchange a single request and its response or error
+type RecordedExchange struct {
+	// Method of the request
+	Method string `json:"method" yaml:"method"`
+	// Params of the request, null if the request has none
+	Params json.RawMessage `json:"params,omitempty" yaml:"params,omitempty"`
+	// Response raw response frame, empty if the request failed
+	Response json.RawMessage `json:"response,omitempty" yaml:"response,omitempty"`
+	// Error returned by the device, if any
+	Error *Error `json:"error,omitempty" yaml:"error,omitempty"`
+	// TransportError error that is not a device error, for example timeout waiting for response
+	TransportError *RecordedTransportError `json:"transport_error,omitempty" yaml:"transport_error,omitempty"`
+}
+
+// RecordedTransportError transport error of a recorded exchange
+type RecordedTransportError struct {
+	// Kind timeout, not_connected, closed, auth_required, malformed_response or error
+	Kind string `json:"kind" yaml:"kind"`
+	// Message of the error
+	Message string `json:"message" yaml:"message"`
+}
+
+// Err returns the error of the exchange or nil
+func (t *RecordedExchange) Err() error {
+
+	if t.Error != nil {
+		return t.Error
+	}
+
+	if t.TransportError == nil {
+		return nil
+	}
+
+	sentinel, ok := transportErrorKinds[t.TransportError.Kind]
+	if !ok {
+		return errors.New(t.TransportError.Message)
+	}
+
+	return fmt.Errorf("%w (recorded: %s)", sentinel, t.TransportError.Message)
+}
+
+// NewRecordedTransportError returns the recorded form of err
+func NewRecordedTransportError(err error) *RecordedTransportError {
+
+	result := &RecordedTransportError{
+		Kind:    "error",
+		Message: err.Error(),
+	}
+
+	// not_connected is checked before timeout as both are set if the connection was down
+	for _, kind := range []string{"not_connected", "timeout", "closed", "auth_required", "malformed_response"} {
+		if errors.Is(err, transportErrorKinds[kind]) {
+			result.Kind = kind
+			break
+		}
+	}
+
+	return result
+}
+
+var transportErrorKinds = map[string]error{
+	"timeout":            ErrTimeout,
+	"not_connected":      ErrNotConnected,
+	"closed":             ErrClosed,
+	"auth_required":      ErrAuthRequired,
+	"malformed_response": ErrMalformedResponse,
+}
+
+// CanonicalParams returns params encoded as JSON with sorted object keys so that equal params always
+// have the same encoding. Nil params return nil.
+func CanonicalParams(params interface{}) (json.RawMessage, error) {
+
+	if params == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	err = json.Unmarshal(b, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return nil, nil
+	}
+
+	// encoding/json writes map keys in sorted order
+	return json.Marshal(v)
+}
+
+// LoadRecording reads a recording file
+func LoadRecording(file string) (*Recording, error) {
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	recording := &Recording{}
+	err = json.Unmarshal(b, recording)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	if recording.Version < 1 || recording.Version > RecordingVersion {
+		return nil, fmt.Errorf("%s: recording version %d is not supported; supported version is %d", file, recording.Version, RecordingVersion)
+	}
+
+	return recording, nil
+}
+
+// Save writes the recording to file
+func (t *Recording) Save(file string) error {
+
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, append(b, '\n'), 0644)
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+// RetryPolicy policy for retrying RPCs that failed with a transient error. Only read-only methods
+// (Get*, List* and CheckForUpdate), the methods in IdempotentMethods and requests sent with a context
+// returned by WithIdempotent are retried. Transient errors are ErrTimeout, ErrNotConnected and the
+// device errors unavailable (-114) and deadline exceeded (-104).
+type RetryPolicy struct {
+	// MaxAttempts maximum number of attempts including the first one. Default is 3
+	MaxAttempts int `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`
+	// InitialBackoff wait time before the first retry. Default is 200ms
+	InitialBackoff time.Duration `json:"initial_backoff,omitempty" yaml:"initial_backoff,omitempty"`
+	// MaxBackoff maximum wait time between retries. Default is 5s
+	MaxBackoff time.Duration `json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`
+	// Multiplier factor the backoff grows by after each retry. Default is 2
+	Multiplier float64 `json:"multiplier,omitempty" yaml:"multiplier,omitempty"`
+	// Jitter fraction of the backoff that is randomized, 0 to 1. Default is 0.2
+	Jitter float64 `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+	// IdempotentMethods write methods that are safe to retry, for example Switch.Set. Optional
+	IdempotentMethods []string `json:"idempotent_methods,omitempty" yaml:"idempotent_methods,omitempty"`
+}
+
+// RetryConfig is optionally implemented by the config passed to plus.New to enable retries
+type RetryConfig interface {
+	GetRetryPolicy() *RetryPolicy
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent returns a context that marks the requests sent with it as idempotent so that they are
+// retried by the retry policy regardless of the method
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// IsIdempotent returns true if ctx was returned by WithIdempotent
+func IsIdempotent(ctx context.Context) bool {
+	idempotent, _ := ctx.Value(idempotentKey{}).(bool)
+	return idempotent
+}
+
+// Clone return copy
+func (t *RetryPolicy) Clone() *RetryPolicy {
+	c := &RetryPolicy{}
+	copier.CopyWithOption(c, t, copier.Option{DeepCopy: true})
+	return c
+}
+
+// WithDefaults returns a copy with unset fields set to their default
+func (t *RetryPolicy) WithDefaults() *RetryPolicy {
+
+	c := t.Clone()
+
+	if c.MaxAttempts < 1 {
+		c.MaxAttempts = 3
+	}
+
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = time.Duration(200) * time.Millisecond
+	}
+
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Duration(5) * time.Second
+	}
+
+	if c.Multiplier < 1 {
+		c.Multiplier = 2
+	}
+
+	if c.Jitter <= 0 || c.Jitter > 1 {
+		c.Jitter = 0.2
+	}
+
+	return c
+}
+
+// CanRetry returns true if the method may be retried
+func (t *RetryPolicy) CanRetry(ctx context.Context, method string) bool {
+
+	if IsIdempotent(ctx) {
+		return true
+	}
+
+	for _, v := range t.IdempotentMethods {
+		if v == method {
+			return true
+		}
+	}
+
+	_, name, _ := strings.Cut(method, ".")
+
+	return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List") || name == "CheckForUpdate"
+}
+
+// IsRetryable returns true if err is transient
+func (t *RetryPolicy) IsRetryable(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, ErrNotConnected) || IsUnavailable(err) || IsDeadlineExceeded(err)
+}
+
+// Backoff returns the wait time before retry (1 for the first retry)
+func (t *RetryPolicy) Backoff(retry int) time.Duration {
+
+	backoff := float64(t.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		backoff *= t.Multiplier
+		if backoff >= float64(t.MaxBackoff) {
+			backoff = float64(t.MaxBackoff)
+			break
+		}
+	}
+
+	backoff += backoff * t.Jitter * (rand.Float64()*2 - 1)
+
+	return time.Duration(backoff)
+}
@@ -0,0 +1,195 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+// RolloutStateVersion is the version of the rollout state file format
+const RolloutStateVersion = 1
+
+// RolloutDevice a device in the fleet
+type RolloutDevice struct {
+	// Name unique name of the device. Used as key in the state file
+	Name string `json:"name" yaml:"name"`
+	// Hostname hostname or IP of the device
+	Hostname string `json:"hostname" yaml:"hostname"`
+}
+
+// RolloutOptions options for a fleet rollout
+type RolloutOptions struct {
+	// Stage of the firmware, either stable or beta. Default is stable
+	Stage string `json:"stage,omitempty" yaml:"stage,omitempty"`
+	// Canary number of devices updated in the first wave. The rollout halts if any canary fails. Optional
+	Canary int `json:"canary,omitempty" yaml:"canary,omitempty"`
+	// BatchSize number of devices per wave after the canary wave. Default is 10
+	BatchSize int `json:"batch_size,omitempty" yaml:"batch_size,omitempty"`
+	// Concurrency number of devices updated at the same time within a wave. Default is 1
+	Concurrency int `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	// MaxFailures number of failed devices tolerated before the rollout halts. Default is 0
+	MaxFailures int `json:"max_failures,omitempty" yaml:"max_failures,omitempty"`
+	// StateFile file the state is saved to after every device. If the file exists the rollout is
+	// resumed. Optional
+	StateFile string `json:"state_file,omitempty" yaml:"state_file,omitempty"`
+	// RetryFailed if true devices that failed in a previous run are retried on resume
+	RetryFailed bool `json:"retry_failed,omitempty" yaml:"retry_failed,omitempty"`
+	// Force if true a halted rollout is resumed without retrying the failed devices. The failures of
+	// previous runs no longer halt the rollout
+	Force bool `json:"force,omitempty" yaml:"force,omitempty"`
+	// UpdateTimeout maximum time to wait for a single device to update. Optional
+	UpdateTimeout time.Duration `json:"update_timeout,omitempty" yaml:"update_timeout,omitempty"`
+	// HealthDelay time to wait after a device updated before the health gate is checked. Optional
+	HealthDelay time.Duration `json:"health_delay,omitempty" yaml:"health_delay,omitempty"`
+	// Progress is called every time a device was processed. Optional
+	Progress func(*RolloutDeviceState) `json:"-" yaml:"-"`
+}
+
+// RolloutDeviceStatus status of a device in a rollout
+type RolloutDeviceStatus string
+
+const (
+	// RolloutDeviceStatusPending the device has not been processed yet
+	RolloutDeviceStatusPending RolloutDeviceStatus = "pending"
+	// RolloutDeviceStatusUpdated the device was updated and passed the health gate
+	RolloutDeviceStatusUpdated RolloutDeviceStatus = "updated"
+	// RolloutDeviceStatusSkipped no update was available for the device
+	RolloutDeviceStatusSkipped RolloutDeviceStatus = "skipped"
+	// RolloutDeviceStatusFailed the update or the health gate failed
+	RolloutDeviceStatusFailed RolloutDeviceStatus = "failed"
+)
+
+// RolloutDeviceState state of a device in a rollout
+type RolloutDeviceState struct {
+	// Name of the device
+	Name string `json:"name" yaml:"name"`
+	// Hostname of the device
+	Hostname string `json:"hostname" yaml:"hostname"`
+	// Wave index of the wave the device belongs to. Wave 0 is the canary wave if canaries are configured
+	Wave int `json:"wave" yaml:"wave"`
+	// Status pending, updated, skipped or failed
+	Status RolloutDeviceStatus `json:"status" yaml:"status"`
+	// Reason the device was skipped or failed
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// FromVersion firmware version before the update
+	FromVersion *string `json:"from_version,omitempty" yaml:"from_version,omitempty"`
+	// ToVersion firmware version after the update
+	ToVersion *string `json:"to_version,omitempty" yaml:"to_version,omitempty"`
+	// Finished time the device was processed
+	Finished *time.Time `json:"finished,omitempty" yaml:"finished,omitempty"`
+}
+
+// RolloutWave a group of devices updated together
+type RolloutWave struct {
+	// Canary true if this is the canary wave
+	Canary bool `json:"canary,omitempty" yaml:"canary,omitempty"`
+	// Devices names of the devices in the wave
+	Devices []string `json:"devices" yaml:"devices"`
+}
+
+// RolloutState state of a rollout. It is saved after every device so that an interrupted or halted
+// rollout can be resumed.
+type RolloutState struct {
+	// Version of the state file format
+	Version int `json:"version" yaml:"version"`
+	// Stage of the firmware, stable or beta
+	Stage string `json:"stage" yaml:"stage"`
+	// Started time the rollout was started
+	Started time.Time `json:"started" yaml:"started"`
+	// Updated time the state was last saved
+	Updated time.Time `json:"updated" yaml:"updated"`
+	// Waves in the order they are rolled out
+	Waves []*RolloutWave `json:"waves" yaml:"waves"`
+	// Devices state of each device by name
+	Devices map[string]*RolloutDeviceState `json:"devices" yaml:"devices"`
+	// Halted true if the rollout was halted because the failure threshold was exceeded
+	Halted bool `json:"halted,omitempty" yaml:"halted,omitempty"`
+	// HaltReason reason the rollout was halted
+	HaltReason string `json:"halt_reason,omitempty" yaml:"halt_reason,omitempty"`
+}
+
+// Clone return copy
+func (t *RolloutState) Clone() *RolloutState {
+	c := &RolloutState{}
+	copier.CopyWithOption(c, t, copier.Option{DeepCopy: true})
+	return c
+}
+
+// Count returns the number of devices with status
+func (t *RolloutState) Count(status RolloutDeviceStatus) int {
+
+	count := 0
+
+	for _, v := range t.Devices {
+		if v.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Done returns true if no device is pending
+func (t *RolloutState) Done() bool {
+	return t.Count(RolloutDeviceStatusPending) == 0
+}
+
+// LoadRolloutState reads the state file. It returns nil and no error if the file does not exist.
+func LoadRolloutState(file string) (*RolloutState, error) {
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	state := &RolloutState{}
+
+	err = json.Unmarshal(b, state)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	if state.Version < 1 || state.Version > RolloutStateVersion {
+		return nil, fmt.Errorf("%s: state version %d is not supported; supported version is %d", file, state.Version, RolloutStateVersion)
+	}
+
+	return state, nil
+}
+
+// Save writes the state to file. The file is replaced atomically so that it is never left partially
+// written if the process is interrupted.
+func (t *RolloutState) Save(file string) error {
+
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(b)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
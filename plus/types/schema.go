@@ -0,0 +1,313 @@
+package types
+
+//go:generate go run gen_docs.go
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect of the generated schemas
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// enumRegex matches documented value ranges such as "Range of values: dhcp, static" or "Valid values: 0, 1"
+var enumRegex = regexp.MustCompile(`(?i)(?:range of values|valid values):?\s+([^.;()]+)`)
+
+// enumValueRegex matches a single enum value
+var enumValueRegex = regexp.MustCompile(`^[a-z0-9_ ]+$`)
+
+// boundsRegex matches documented numeric ranges such as [1.0..50.0]
+var boundsRegex = regexp.MustCompile(`\[(-?\d+(?:\.\d+)?)\.\.(-?\d+(?:\.\d+)?)\]`)
+
+// JSONSchema a JSON Schema document or sub schema. Only the keywords needed to describe the types in
+// this package are supported.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty" yaml:"title,omitempty"`
+	Description          string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Type                 interface{}            `json:"type,omitempty" yaml:"type,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty" yaml:"required,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty" yaml:"$defs,omitempty"`
+}
+
+// NewJSONSchema returns the JSON Schema for v, which must be a struct or a pointer to a struct. Descriptions
+// are taken from the doc comments and enums and ranges from the documented value ranges. Fields that are
+// not pointers and not omitempty are required and pointer fields accept null. If v is a config (it has a
+// Sanatize method) unknown properties are not allowed.
+func NewJSONSchema(v interface{}) (*JSONSchema, error) {
+
+	rt := reflect.TypeOf(v)
+	for rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type %T is not a struct", v)
+	}
+
+	_, strict := reflect.New(rt).Interface().(interface{ Sanatize() })
+
+	builder := &schemaBuilder{
+		strict: strict,
+		defs:   map[string]*JSONSchema{},
+	}
+
+	root := builder.structSchema(rt)
+	root.Schema = JSONSchemaDraft
+	root.Title = rt.Name()
+
+	// The root type is inlined; every other struct type is a definition
+	delete(builder.defs, rt.Name())
+
+	if len(builder.defs) > 0 {
+		root.Defs = builder.defs
+	}
+
+	return root, nil
+}
+
+// JSONSchemas returns the schemas for ShellyConfig, ShellyStatus and the config and status of each
+// component keyed by file name, for example shelly_config.json
+func JSONSchemas() (map[string]*JSONSchema, error) {
+
+	types := map[string]interface{}{
+		"shelly_config.json":    &ShellyConfig{},
+		"shelly_status.json":    &ShellyStatus{},
+		"bluetooth_config.json": &BluetoothConfig{},
+		"bluetooth_status.json": &BluetoothStatus{},
+		"cloud_config.json":     &CloudConfig{},
+		"cloud_status.json":     &CloudStatus{},
+		"ethernet_config.json":  &EthernetConfig{},
+		"ethernet_status.json":  &EthernetStatus{},
+		"input_config.json":     &InputConfig{},
+		"input_status.json":     &InputStatus{},
+		"light_config.json":     &LightConfig{},
+		"light_status.json":     &LightStatus{},
+		"mqtt_config.json":      &MqttConfig{},
+		"mqtt_status.json":      &MqttStatus{},
+		"switch_config.json":    &SwitchConfig{},
+		"switch_status.json":    &SwitchStatus{},
+		"system_config.json":    &SystemConfig{},
+		"system_status.json":    &SystemStatus{},
+		"webhook_config.json":   &WebhookConfig{},
+		"websocket_config.json": &WebsocketConfig{},
+		"websocket_status.json": &WebsocketStatus{},
+		"wifi_config.json":      &WifiConfig{},
+		"wifi_status.json":      &WifiStatus{},
+	}
+
+	schemas := map[string]*JSONSchema{}
+
+	for name, v := range types {
+
+		schema, err := NewJSONSchema(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		schemas[name] = schema
+	}
+
+	return schemas, nil
+}
+
+type schemaBuilder struct {
+	strict bool
+	defs   map[string]*JSONSchema
+}
+
+func (t *schemaBuilder) structSchema(rt reflect.Type) *JSONSchema {
+
+	schema := &JSONSchema{
+		Type:        "object",
+		Description: typeDoc(rt.Name(), ""),
+		Properties:  map[string]*JSONSchema{},
+	}
+
+	// Registered before the fields are walked so recursive types terminate
+	t.defs[rt.Name()] = schema
+
+	if t.strict {
+		schema.AdditionalProperties = false
+	}
+
+	for i := 0; i < rt.NumField(); i++ {
+
+		field := rt.Field(i)
+
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+
+		property := t.fieldSchema(field.Type)
+
+		doc := typeDoc(rt.Name(), field.Name)
+		if doc != "" {
+			property.Description = doc
+			applyDocumentedRange(property, field.Type, doc)
+		}
+
+		schema.Properties[name] = property
+
+		omitempty := strings.Contains(field.Tag.Get("json"), ",omitempty")
+		if !omitempty && field.Type.Kind() != reflect.Pointer && field.Type.Kind() != reflect.Slice && field.Type.Kind() != reflect.Map {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+func (t *schemaBuilder) fieldSchema(rt reflect.Type) *JSONSchema {
+
+	if rt.Kind() != reflect.Pointer {
+		return t.typeSchema(rt)
+	}
+
+	schema := t.typeSchema(rt.Elem())
+
+	if schema.Ref != "" {
+		return &JSONSchema{
+			AnyOf: []*JSONSchema{schema, {Type: "null"}},
+		}
+	}
+
+	if s, ok := schema.Type.(string); ok {
+		schema.Type = []string{s, "null"}
+	}
+
+	return schema
+}
+
+func (t *schemaBuilder) typeSchema(rt reflect.Type) *JSONSchema {
+
+	switch rt.Kind() {
+
+	case reflect.Pointer:
+		return t.fieldSchema(rt)
+
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+
+	case reflect.Slice, reflect.Array:
+		elem := rt.Elem()
+		if elem.Kind() == reflect.Pointer {
+			// Lists of configs never hold null elements
+			elem = elem.Elem()
+		}
+		return &JSONSchema{Type: "array", Items: t.typeSchema(elem)}
+
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: t.typeSchema(rt.Elem())}
+
+	case reflect.Struct:
+		if _, ok := t.defs[rt.Name()]; !ok {
+			t.structSchema(rt)
+		}
+		return &JSONSchema{Ref: "#/$defs/" + rt.Name()}
+
+	}
+
+	// interface{} and anything else accepts any value
+	return &JSONSchema{}
+}
+
+// applyDocumentedRange sets enum, minimum and maximum from the value ranges documented in doc
+func applyDocumentedRange(schema *JSONSchema, rt reflect.Type, doc string) {
+
+	nullable := rt.Kind() == reflect.Pointer
+	if nullable {
+		rt = rt.Elem()
+	}
+
+	if match := enumRegex.FindStringSubmatch(doc); match != nil {
+
+		var enum []interface{}
+
+	values:
+		for _, s := range strings.Split(match[1], ",") {
+
+			s = strings.TrimSpace(s)
+			if !enumValueRegex.MatchString(s) {
+				enum = nil
+				break
+			}
+
+			switch rt.Kind() {
+
+			case reflect.String:
+				enum = append(enum, s)
+
+			case reflect.Int:
+				i, err := strconv.Atoi(s)
+				if err != nil {
+					enum = nil
+					break values
+				}
+				enum = append(enum, i)
+
+			}
+		}
+
+		if len(enum) > 0 {
+			if nullable {
+				enum = append(enum, nil)
+			}
+			schema.Enum = enum
+		}
+	}
+
+	if match := boundsRegex.FindStringSubmatch(doc); match != nil && (rt.Kind() == reflect.Float64 || rt.Kind() == reflect.Int) {
+		min, _ := strconv.ParseFloat(match[1], 64)
+		max, _ := strconv.ParseFloat(match[2], 64)
+		schema.Minimum = &min
+		schema.Maximum = &max
+	}
+}
+
+// typeDoc returns the doc comment of the type (field "") or field. The leading name is removed unless it
+// is part of the sentence as in "Name of the switch instance".
+func typeDoc(typeName, fieldName string) string {
+
+	doc := typeDocs[typeName][fieldName]
+
+	name := fieldName
+	if name == "" {
+		name = typeName
+	}
+
+	rest, ok := strings.CutPrefix(doc, name+" ")
+	if !ok {
+		return doc
+	}
+
+	next, _, _ := strings.Cut(rest, " ")
+	switch next {
+	case "of", "which", "is", "to", "for":
+		return doc
+	}
+
+	return rest
+}
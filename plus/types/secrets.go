@@ -0,0 +1,301 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrSecretNotFound is returned by a SecretResolver when it does not know the secret
+var ErrSecretNotFound = errors.New("secret not found")
+
+// placeholders are the strings written by Markup in place of values that can not be retrieved from
+// the device. They must never be sent to a device.
+var placeholders = []string{
+	shellyUserCAExample,
+	shellyTLSClientCertExample,
+	shellyTLSClientKey,
+	genericPassword,
+	passwordIfEnabled,
+	passwordIfNotEnabled,
+}
+
+// secretRefRegex matches ${secret:name} references
+var secretRefRegex = regexp.MustCompile(`\$\{secret:([^}]+)\}`)
+
+// IsPlaceholder returns true if s is one of the placeholder strings written by Markup
+func IsPlaceholder(s string) bool {
+	for _, v := range placeholders {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// SecretResolver resolves secrets by name. Names are either the name used in a ${secret:name}
+// reference or the path of a field holding a placeholder, for example wifi.sta.pass. Implementations
+// must return ErrSecretNotFound (or an error wrapping it) if the secret is not known.
+type SecretResolver interface {
+	Resolve(name string) (string, error)
+}
+
+// EnvSecretResolver resolves secrets from environment variables. The variable name is the prefix
+// followed by the secret name in upper case with every character that is not a letter or digit
+// replaced by an underscore. With the default prefix the secret wifi.sta.pass is read from
+// SHELLY_WIFI_STA_PASS.
+type EnvSecretResolver struct {
+	// Prefix of the environment variables. Default is ShellyEnvVar followed by an underscore
+	Prefix *string
+}
+
+// Resolve returns the secret or an error
+func (t *EnvSecretResolver) Resolve(name string) (string, error) {
+
+	prefix := ShellyEnvVar + "_"
+	if t.Prefix != nil {
+		prefix = *t.Prefix
+	}
+
+	key := prefix + strings.ToUpper(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name))
+
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrSecretNotFound, key)
+	}
+
+	return value, nil
+}
+
+// FileSecretResolver resolves secrets from files in a directory. Each secret is a file named after
+// the secret, as used by Docker and Kubernetes secrets. A single trailing newline is removed.
+type FileSecretResolver struct {
+	// Dir directory holding the secret files. Required
+	Dir string
+}
+
+// Resolve returns the secret or an error
+func (t *FileSecretResolver) Resolve(name string) (string, error) {
+
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("secret name %q is not a valid file name", name)
+	}
+
+	b, err := os.ReadFile(filepath.Join(t.Dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("%w: %v", ErrSecretNotFound, err)
+		}
+		return "", err
+	}
+
+	s := strings.TrimSuffix(string(b), "\n")
+	return strings.TrimSuffix(s, "\r"), nil
+}
+
+// MapSecretResolver resolves secrets from a map
+type MapSecretResolver map[string]string
+
+// Resolve returns the secret or an error
+func (t MapSecretResolver) Resolve(name string) (string, error) {
+
+	value, ok := t[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+
+	return value, nil
+}
+
+// ChainSecretResolver tries each resolver in order and returns the first secret found
+type ChainSecretResolver []SecretResolver
+
+// Resolve returns the secret or an error
+func (t ChainSecretResolver) Resolve(name string) (string, error) {
+
+	for _, v := range t {
+
+		value, err := v.Resolve(name)
+		if err == nil {
+			return value, nil
+		}
+
+		if !errors.Is(err, ErrSecretNotFound) {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+}
+
+// ResolveSecrets replaces secrets in v, which must be a pointer to a config. Every ${secret:name}
+// reference in a string is replaced with the secret name. A field holding a placeholder string is
+// replaced with the secret named after the field path (for example wifi.sta.pass) if the resolver
+// knows it, otherwise it is left as is. An error is returned if a ${secret:name} reference can not
+// be resolved.
+func ResolveSecrets(v interface{}, resolver SecretResolver) error {
+
+	if resolver == nil {
+		return fmt.Errorf("resolver is required")
+	}
+
+	return walkStrings("", reflect.ValueOf(v), func(path string, s reflect.Value) error {
+
+		value := s.String()
+
+		if IsPlaceholder(value) {
+
+			secret, err := resolver.Resolve(path)
+			if err == nil {
+				s.SetString(secret)
+				return nil
+			}
+
+			if errors.Is(err, ErrSecretNotFound) {
+				return nil
+			}
+
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		var errs []string
+
+		resolved := secretRefRegex.ReplaceAllStringFunc(value, func(ref string) string {
+
+			name := secretRefRegex.FindStringSubmatch(ref)[1]
+
+			secret, err := resolver.Resolve(name)
+			if err != nil {
+				errs = append(errs, err.Error())
+				return ref
+			}
+
+			return secret
+		})
+
+		if len(errs) > 0 {
+			return fmt.Errorf("%s: %s", path, strings.Join(errs, "; "))
+		}
+
+		s.SetString(resolved)
+		return nil
+	})
+}
+
+// SendableConfig is a config that is sent to a device after it was prepared with PrepareConfig
+type SendableConfig[T any] interface {
+	Clone() T
+	Sanatize()
+	Validate() error
+}
+
+// PrepareConfig returns a sanatized copy of config that is ready to be sent to a device. Placeholders
+// are checked after Sanatize so that the ones Markup writes into fields that are never sent, such as
+// the data of a disabled TLS config, are dropped rather than rejected. Config is not modified.
+func PrepareConfig[T SendableConfig[T]](config T) (T, error) {
+
+	config = config.Clone()
+	config.Sanatize()
+
+	err := CheckPlaceholders(config)
+	if err != nil {
+		return config, err
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+// CheckPlaceholders returns an error listing every field of v that holds a placeholder string or an
+// unresolved ${secret:name} reference. It is called before a config is sent to a device.
+func CheckPlaceholders(v interface{}) error {
+
+	var paths []string
+
+	walkStrings("", reflect.ValueOf(v), func(path string, s reflect.Value) error {
+		if IsPlaceholder(s.String()) || secretRefRegex.MatchString(s.String()) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+
+	if len(paths) > 0 {
+		return fmt.Errorf("config contains placeholders or unresolved secrets that must be replaced: %s", strings.Join(paths, ", "))
+	}
+
+	return nil
+}
+
+// walkStrings calls fn for every settable string reachable from v. The path uses the JSON field names
+// and instances of components with an ID are named <component>:<id>, for example switch:0.name.
+func walkStrings(path string, v reflect.Value, fn func(path string, s reflect.Value) error) error {
+
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+
+	join := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	switch v.Kind() {
+
+	case reflect.String:
+		if v.CanSet() {
+			return fn(path, v)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+
+			name := jsonName(v.Type().Field(i))
+			if name == "" {
+				continue
+			}
+
+			field := v.Field(i)
+
+			if field.Kind() == reflect.Slice {
+				for j := 0; j < field.Len(); j++ {
+
+					elementPath := join(name) + "." + strconv.Itoa(j)
+					if id, ok := componentID(field.Index(j)); ok {
+						elementPath = fmt.Sprintf("%s:%d", join(name), id)
+					}
+
+					err := walkStrings(elementPath, field.Index(j), fn)
+					if err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			err := walkStrings(join(name), field, fn)
+			if err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
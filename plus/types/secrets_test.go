@@ -0,0 +1,68 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrepareConfig(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		config *ShellyConfig
+		paths  []string
+	}{
+		{
+			name:   "mqtt pass",
+			config: &ShellyConfig{Mqtt: &MqttConfig{Enable: true, Server: ptr("broker:1883"), User: ptr("u")}},
+			paths:  []string{"mqtt.pass"},
+		},
+		{
+			name:   "wifi ap and sta pass",
+			config: &ShellyConfig{Wifi: &WifiConfig{Ap: &WifiAPConfig{Enable: true}, Sta: &WifiSTAConfig{Enable: true, SSID: ptr("home")}}},
+			paths:  []string{"wifi.ap.pass", "wifi.sta.pass"},
+		},
+		{
+			name:   "open wifi ap",
+			config: &ShellyConfig{Wifi: &WifiConfig{Ap: &WifiAPConfig{Enable: true, IsOpen: ptr(true)}}},
+		},
+		{
+			name:   "disabled auth",
+			config: &ShellyConfig{Auth: &ShellyAuthConfig{}},
+		},
+		{
+			name:   "enabled auth",
+			config: &ShellyConfig{Auth: &ShellyAuthConfig{Enable: true}},
+			paths:  []string{"auth.pass"},
+		},
+		{
+			name:   "disabled user ca",
+			config: &ShellyConfig{UserCA: &ShellyUserCAConfig{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			config := tt.config.Clone()
+			config.Markup()
+
+			_, err := PrepareConfig(config)
+
+			if len(tt.paths) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("placeholders in %v were not found", tt.paths)
+			}
+
+			if !strings.HasSuffix(err.Error(), strings.Join(tt.paths, ", ")) {
+				t.Errorf("error = %v, want paths %v", err, tt.paths)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package types
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/jinzhu/copier"
@@ -182,6 +184,74 @@ func (t *ShellyConfig) Sanatize() {
 	}
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *ShellyConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *ShellyConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	t.Auth.validate(joinPath(path, "auth"), v)
+	t.TLSClientCert.validate(joinPath(path, "tls_client_cert"), v)
+	t.TLSClientKey.validate(joinPath(path, "tls_client_key"), v)
+	t.UserCA.validate(joinPath(path, "user_ca"), v)
+	t.Bluetooth.validate(joinPath(path, "ble"), v)
+	t.Cloud.validate(joinPath(path, "cloud"), v)
+	t.Mqtt.validate(joinPath(path, "mqtt"), v)
+	t.Ethernet.validate(joinPath(path, "eth"), v)
+	t.System.validate(joinPath(path, "sys"), v)
+	t.Wifi.validate(joinPath(path, "wifi"), v)
+	t.Websocket.validate(joinPath(path, "ws"), v)
+
+	seen := map[string]bool{}
+
+	instance := func(name string, id int) string {
+		p := joinPath(path, fmt.Sprintf("%s:%d", name, id))
+		if seen[p] {
+			v.add(p, "is defined more than once")
+		}
+		seen[p] = true
+		return p
+	}
+
+	for _, c := range t.Light {
+		c.validate(instance("light", c.ID), v)
+	}
+
+	for _, c := range t.Input {
+		c.validate(instance("input", c.ID), v)
+	}
+
+	for _, c := range t.Switch {
+		c.validate(instance("switch", c.ID), v)
+	}
+}
+
+// ResolveSecrets returns a copy of the config with secrets resolved. See ResolveSecrets for the rules.
+// Call this before SetConfig when the config was exported with Markup or contains ${secret:name}
+// references.
+func (t *ShellyConfig) ResolveSecrets(resolver SecretResolver) (*ShellyConfig, error) {
+
+	c := &ShellyConfig{}
+	err := copier.CopyWithOption(c, t, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, err
+	}
+
+	err = ResolveSecrets(c, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
 // DeviceInfo Shelly component top level device info
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellygetdeviceinfo
 type DeviceInfo struct {
@@ -241,6 +311,81 @@ func (t *ShellyUpdateConfig) Clone() *ShellyUpdateConfig {
 	return c
 }
 
+// ShellyUpdateOptions options for Shelly UpdateAndWait
+type ShellyUpdateOptions struct {
+	// Stage of the update, either stable or beta. Default is stable
+	Stage string `json:"stage,omitempty" yaml:"stage,omitempty"`
+	// Timeout maximum time to wait for the update to complete. Optional
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Progress is called for every ota event (ota_begin, ota_progress, ota_success, ota_error) reported
+	// by the device. Optional
+	Progress func(*UpdateProgress) `json:"-" yaml:"-"`
+}
+
+// UpdateProgress progress of a firmware update as reported by the device
+type UpdateProgress struct {
+	// Event ota_begin, ota_progress, ota_success or ota_error
+	Event string `json:"event" yaml:"event"`
+	// Percent progress in percent (only for ota_progress)
+	Percent *int `json:"percent,omitempty" yaml:"percent,omitempty"`
+	// Message reported by the device
+	Message *string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// UpdateStatus outcome of UpdateAndWait
+type UpdateStatus string
+
+const (
+	UpdateStatusUpdated UpdateStatus = "updated"
+	UpdateStatusSkipped UpdateStatus = "skipped"
+	UpdateStatusFailed  UpdateStatus = "failed"
+)
+
+// UpdateResult result of UpdateAndWait
+type UpdateResult struct {
+	// Status updated, skipped or failed
+	Status UpdateStatus `json:"status" yaml:"status"`
+	// Reason the update was skipped or failed
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// Stage of the update
+	Stage string `json:"stage" yaml:"stage"`
+	// FromVersion firmware version before the update
+	FromVersion *string `json:"from_version,omitempty" yaml:"from_version,omitempty"`
+	// ToVersion firmware version after the update or the version offered if the update failed
+	ToVersion *string `json:"to_version,omitempty" yaml:"to_version,omitempty"`
+}
+
+// Error returns an error if the update failed, otherwise nil
+func (t *UpdateResult) Error() error {
+
+	if t.Status != UpdateStatusFailed {
+		return nil
+	}
+
+	return fmt.Errorf("update failed: %s", t.Reason)
+}
+
+// ShellySetConfigOptions options for Shelly SetConfigWithOptions
+type ShellySetConfigOptions struct {
+	// Reboot if true and one or more components report that a reboot is required the device is rebooted
+	// once after all components have been set. The call then waits for the device to reconnect and for
+	// restart_required to clear before verifying the applied config.
+	Reboot bool `json:"reboot,omitempty" yaml:"reboot,omitempty"`
+	// RebootTimeout maximum time to wait for the device to come back after the reboot. Optional
+	RebootTimeout time.Duration `json:"reboot_timeout,omitempty" yaml:"reboot_timeout,omitempty"`
+	// Verify if true the config is read back from the device after it has been set (and after the reboot
+	// if one was done) and fields that differ from what was sent are recorded in the component reports.
+	// Verify is implied by Reboot.
+	Verify bool `json:"verify,omitempty" yaml:"verify,omitempty"`
+}
+
+// Clone return copy
+func (t *ShellySetConfigOptions) Clone() *ShellySetConfigOptions {
+	c := &ShellySetConfigOptions{}
+	copier.Copy(&c, &t)
+	return c
+}
+
 // ShellyAuthConfig Shelly Auth Config
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration
 type ShellyAuthConfig struct {
@@ -283,10 +428,26 @@ func (t *ShellyAuthConfig) Sanatize() {
 		return
 	}
 
-	if t.Pass != nil {
-		if *t.Pass == passwordIfNotEnabled {
-			t.Pass = nil
-		}
+	if !t.Enable {
+		t.Pass = nil
+	}
+}
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *ShellyAuthConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *ShellyAuthConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	if t.Enable {
+		v.required(joinPath(path, "pass"), t.Pass != nil && *t.Pass != "", "when enable is true")
 	}
 }
 
@@ -335,6 +496,26 @@ func (t *ShellyUserCAConfig) Sanatize() {
 	}
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *ShellyUserCAConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *ShellyUserCAConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	if t.Enable {
+		v.required(joinPath(path, "data"), t.Data != nil, "when enable is true")
+	}
+
+	v.pem(joinPath(path, "data"), t.Data)
+}
+
 // ShellyTLSClientCertConfig Shelly TLS Client Cert config
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration
 type ShellyTLSClientCertConfig struct {
@@ -380,6 +561,26 @@ func (t *ShellyTLSClientCertConfig) Sanatize() {
 	}
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *ShellyTLSClientCertConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *ShellyTLSClientCertConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	if t.Enable {
+		v.required(joinPath(path, "data"), t.Data != nil, "when enable is true")
+	}
+
+	v.pem(joinPath(path, "data"), t.Data)
+}
+
 // ShellyTLSClientKeyConfig Shelly TLS Client Key config
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#configuration
 type ShellyTLSClientKeyConfig struct {
@@ -425,6 +626,26 @@ func (t *ShellyTLSClientKeyConfig) Sanatize() {
 	}
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *ShellyTLSClientKeyConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *ShellyTLSClientKeyConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	if t.Enable {
+		v.required(joinPath(path, "data"), t.Data != nil, "when enable is true")
+	}
+
+	v.pem(joinPath(path, "data"), t.Data)
+}
+
 // UpdatesReport checks for new firmware version for the device and returns information about it.
 // If no update is available returns empty JSON object as result.
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Shelly#shellycheckforupdate
@@ -455,6 +676,10 @@ type ShellyReport struct {
 	Light         []*ComponentReport `json:"light,omitempty" yaml:"light,omitempty"`
 	Input         []*ComponentReport `json:"input,omitempty" yaml:"input,omitempty"`
 	Switch        []*ComponentReport `json:"switch,omitempty" yaml:"switch,omitempty"`
+	// Reboot is set if the device was rebooted after the config was set
+	Reboot *ComponentReport `json:"reboot,omitempty" yaml:"reboot,omitempty"`
+	// Verify is set if the config was verified after it was set. Mismatches are recorded on each component.
+	Verify *ComponentReport `json:"verify,omitempty" yaml:"verify,omitempty"`
 }
 
 // Clone return copy
@@ -464,8 +689,16 @@ func (t *ShellyReport) Clone() *ShellyReport {
 	return c
 }
 
+// RebootRequired returns true if one or more components reported that a reboot is required and the
+// device has not since been successfully rebooted
 func (t *ShellyReport) RebootRequired() bool {
 
+	if t.Reboot != nil {
+		if t.Reboot.Error == nil {
+			return false
+		}
+	}
+
 	if t.Bluetooth != nil {
 		if t.Bluetooth.RebootRequired != nil {
 			if *t.Bluetooth.RebootRequired {
@@ -522,6 +755,14 @@ func (t *ShellyReport) RebootRequired() bool {
 		}
 	}
 
+	for _, v := range append(append(append([]*ComponentReport{}, t.Light...), t.Input...), t.Switch...) {
+		if v.RebootRequired != nil {
+			if *v.RebootRequired {
+				return true
+			}
+		}
+	}
+
 	return false
 }
 
@@ -530,95 +771,107 @@ func (t *ShellyReport) Error() error {
 	var errors *multierror.Error
 
 	if t.Auth != nil {
-		if t.Auth.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Auth :: %v", t.Auth.Error))
+		if err := t.Auth.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Auth :: %v", err))
 		}
 	}
 
 	if t.TLSClientCert != nil {
-		if t.TLSClientCert.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("TLSClientCert :: %v", t.TLSClientCert.Error))
+		if err := t.TLSClientCert.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("TLSClientCert :: %v", err))
 		}
 	}
 
 	if t.TLSClientKey != nil {
-		if t.TLSClientKey.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("TLSClientKey :: %v", t.TLSClientKey.Error))
+		if err := t.TLSClientKey.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("TLSClientKey :: %v", err))
 		}
 	}
 
 	if t.UserCA != nil {
-		if t.UserCA.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("UserCA :: %v", t.UserCA.Error))
+		if err := t.UserCA.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("UserCA :: %v", err))
 		}
 	}
 
 	if t.Bluetooth != nil {
-		if t.Bluetooth.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Bluetooth :: %v", t.Bluetooth.Error))
+		if err := t.Bluetooth.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Bluetooth :: %v", err))
 		}
 	}
 
 	if t.Cloud != nil {
-		if t.Cloud.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Cloud :: %v", t.Cloud.Error))
+		if err := t.Cloud.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Cloud :: %v", err))
 		}
 	}
 
 	if t.Mqtt != nil {
-		if t.Mqtt.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Mqtt :: %v", t.Mqtt.Error))
+		if err := t.Mqtt.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Mqtt :: %v", err))
 		}
 	}
 
 	if t.Ethernet != nil {
-		if t.Ethernet.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Ethernet :: %v", t.Ethernet.Error))
+		if err := t.Ethernet.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Ethernet :: %v", err))
 		}
 	}
 
 	if t.System != nil {
-		if t.System.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("System :: %v", t.System.Error))
+		if err := t.System.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("System :: %v", err))
 		}
 	}
 
 	if t.Wifi != nil {
-		if t.Wifi.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Wifi :: %v", t.Wifi.Error))
+		if err := t.Wifi.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Wifi :: %v", err))
 		}
 	}
 
 	if t.Websocket != nil {
-		if t.Websocket.Error != nil {
-			errors = multierror.Append(errors, fmt.Errorf("Websocket :: %v", t.Websocket.Error))
+		if err := t.Websocket.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Websocket :: %v", err))
 		}
 	}
 
 	if t.Light != nil {
 		for _, v := range t.Light {
-			if v.Error != nil {
-				errors = multierror.Append(errors, fmt.Errorf("Light %d :: %v", v.ID, v.Error))
+			if err := v.err(); err != nil {
+				errors = multierror.Append(errors, fmt.Errorf("Light %d :: %v", *v.ID, err))
 			}
 		}
 	}
 
 	if t.Input != nil {
 		for _, v := range t.Input {
-			if v.Error != nil {
-				errors = multierror.Append(errors, fmt.Errorf("Input %d :: %v", v.ID, v.Error))
+			if err := v.err(); err != nil {
+				errors = multierror.Append(errors, fmt.Errorf("Input %d :: %v", *v.ID, err))
 			}
 		}
 	}
 
 	if t.Switch != nil {
 		for _, v := range t.Switch {
-			if v.Error != nil {
-				errors = multierror.Append(errors, fmt.Errorf("Switch %d :: %v", v.ID, v.Error))
+			if err := v.err(); err != nil {
+				errors = multierror.Append(errors, fmt.Errorf("Switch %d :: %v", *v.ID, err))
 			}
 		}
 	}
 
+	if t.Verify != nil {
+		if err := t.Verify.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Verify :: %v", err))
+		}
+	}
+
+	if t.Reboot != nil {
+		if err := t.Reboot.err(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Reboot :: %v", err))
+		}
+	}
+
 	return errors.ErrorOrNil()
 }
 
@@ -626,6 +879,28 @@ type ComponentReport struct {
 	RebootRequired *bool `json:"reboot_required,omitempty" yaml:"reboot_required,omitempty"`
 	Error          error `json:"error,omitempty" yaml:"error,omitempty"`
 	ID             *int  `json:"id,omitempty" yaml:"id,omitempty"`
+	// Mismatches fields that differ between the config sent and the config read back from the device.
+	// Old is the value on the device and New is the value sent. Only set if verify is enabled.
+	Mismatches []*Change `json:"mismatches,omitempty" yaml:"mismatches,omitempty"`
+}
+
+// err returns the error if set, otherwise an error describing the mismatches if any
+func (t *ComponentReport) err() error {
+
+	if t.Error != nil {
+		return t.Error
+	}
+
+	if len(t.Mismatches) == 0 {
+		return nil
+	}
+
+	var mismatches []string
+	for _, v := range t.Mismatches {
+		mismatches = append(mismatches, fmt.Sprintf("%s: sent %s, device has %s", v.Path, formatValue(v.New), formatValue(v.Old)))
+	}
+
+	return fmt.Errorf("config not applied; %s", strings.Join(mismatches, "; "))
 }
 
 // Clone return copy
@@ -136,3 +136,31 @@ func (t *SwitchConfig) Sanatize() {
 	}
 
 }
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *SwitchConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *SwitchConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	v.enum(joinPath(path, "in_mode"), t.InMode, "momentary", "follow", "flip", "detached")
+	v.enum(joinPath(path, "initial_state"), t.InitialState, "off", "on", "restore_last", "match_input")
+	v.nonNegative(joinPath(path, "auto_on_delay"), t.AutoOnDelay)
+	v.nonNegative(joinPath(path, "auto_off_delay"), t.AutoOffDelay)
+	v.rangeInt(joinPath(path, "input_id"), t.InputID, 0, 1)
+	v.nonNegative(joinPath(path, "power_limit"), t.PowerLimit)
+	v.nonNegative(joinPath(path, "voltage_limit"), t.VoltageLimit)
+	v.nonNegative(joinPath(path, "undervoltage_limit"), t.UndervoltageLimit)
+	v.nonNegative(joinPath(path, "current_limit"), t.CurrentLimit)
+
+	if t.UndervoltageLimit != nil && t.VoltageLimit != nil && *t.UndervoltageLimit >= *t.VoltageLimit {
+		v.add(joinPath(path, "undervoltage_limit"), "must be less than voltage_limit")
+	}
+}
@@ -140,6 +140,40 @@ func (t *SystemConfig) Sanatize() {
 	t.Device.Sanatize()
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *SystemConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *SystemConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	t.Device.validate(joinPath(path, "device"), v)
+
+	if t.Location != nil {
+		v.rangeFloat(joinPath(path, "location.lat"), t.Location.Lat, -90, 90)
+		v.rangeFloat(joinPath(path, "location.lon"), t.Location.Lon, -180, 180)
+	}
+
+	if t.Debug != nil && t.Debug.UDP != nil {
+		v.hostPort(joinPath(path, "debug.udp.addr"), t.Debug.UDP.Addr)
+	}
+
+	if t.RPCUDP != nil {
+		v.hostPort(joinPath(path, "rpc_udp.dst_addr"), t.RPCUDP.DstAddr)
+		v.port(joinPath(path, "rpc_udp.listen_port"), t.RPCUDP.ListenPort)
+	}
+
+	if t.Sntp != nil && t.Sntp.Server != nil && *t.Sntp.Server == "" {
+		v.add(joinPath(path, "sntp.server"), "must not be empty")
+	}
+}
+
 // SystemDevice information about the device
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration
 type SystemDevice struct {
@@ -232,6 +266,22 @@ func (t *SystemDevice) Sanatize() {
 	t.FwID = nil
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *SystemDevice) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *SystemDevice) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	v.enum(joinPath(path, "addon_type"), t.AddonType, "sensor")
+}
+
 // SystemLocationConfig Information about the current location of the device
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/Sys#configuration
 type SystemLocation struct {
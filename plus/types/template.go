@@ -0,0 +1,260 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/go-multierror"
+	"gopkg.in/yaml.v2"
+)
+
+// templateVarRegex matches ${var} references. Secret references (${secret:name}) do not match
+// because a colon is not allowed in a variable name; they are left for ResolveSecrets. A reference
+// can be escaped as $${var}.
+var templateVarRegex = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+// ShellyTemplate a ShellyConfig in YAML (or JSON) form with placeholders. Placeholders are either Go
+// templates such as {{ .name }} or {{ .name | quote }} or ${name} references. Go templates are
+// executed first as text, then ${name} references are replaced in the parsed YAML so that a value
+// such as "Kitchen #2" or "on" is never read as YAML. Rendering fails if a variable is missing.
+type ShellyTemplate struct {
+	name     string
+	template *template.Template
+}
+
+// ShellyTemplateVariables variables for rendering a ShellyTemplate for a number of devices.
+// Example:
+//
+//	vars:
+//	  mqtt_server: broker.local:1883
+//	devices:
+//	  plug-1:
+//	    name: Plug 1
+//	    ip: 192.168.1.21
+//
+// Vars are shared by all devices and can be overridden per device. The variable device is set to the
+// device key unless it is set explicitly.
+type ShellyTemplateVariables struct {
+	Vars    map[string]interface{}            `json:"vars,omitempty" yaml:"vars,omitempty"`
+	Devices map[string]map[string]interface{} `json:"devices,omitempty" yaml:"devices,omitempty"`
+}
+
+// ParseShellyTemplateVariables parses a variables file in YAML (or JSON) form
+func ParseShellyTemplateVariables(b []byte) (*ShellyTemplateVariables, error) {
+
+	variables := &ShellyTemplateVariables{}
+
+	err := yaml.UnmarshalStrict(b, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return variables, nil
+}
+
+// DeviceVars returns the merged variables for the device or nil if the device is not known
+func (t *ShellyTemplateVariables) DeviceVars(device string) map[string]interface{} {
+
+	deviceVars, ok := t.Devices[device]
+	if !ok {
+		return nil
+	}
+
+	vars := map[string]interface{}{
+		"device": device,
+	}
+
+	for k, v := range t.Vars {
+		vars[k] = v
+	}
+
+	for k, v := range deviceVars {
+		vars[k] = v
+	}
+
+	return vars
+}
+
+// NewShellyTemplate parses the template. The name is only used in error messages.
+func NewShellyTemplate(name string, b []byte) (*ShellyTemplate, error) {
+
+	funcs := template.FuncMap{
+		// quote returns the value as a double quoted YAML string
+		"quote": func(v interface{}) string {
+			return strconv.Quote(fmt.Sprint(v))
+		},
+		// default returns the value or def if the value is not set or empty
+		"default": func(def, v interface{}) interface{} {
+			if v == nil || fmt.Sprint(v) == "" {
+				return def
+			}
+			return v
+		},
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShellyTemplate{
+		name:     name,
+		template: tmpl,
+	}, nil
+}
+
+// Render renders the template with vars and returns the resulting config. The config is decoded
+// strictly (unknown fields are an error) and validated.
+func (t *ShellyTemplate) Render(vars map[string]interface{}) (*ShellyConfig, error) {
+
+	var buf bytes.Buffer
+
+	err := t.template.Execute(&buf, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+
+	err = yaml.UnmarshalStrict(buf.Bytes(), &doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.name, err)
+	}
+
+	missing := map[string]bool{}
+
+	doc = substituteVars(doc, vars, missing)
+
+	if len(missing) > 0 {
+		var names []string
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("%s: missing variables %s", t.name, strings.Join(names, ", "))
+	}
+
+	rendered, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.name, err)
+	}
+
+	config := &ShellyConfig{}
+
+	err = yaml.UnmarshalStrict(rendered, config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.name, err)
+	}
+
+	err = validateRendered(config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.name, err)
+	}
+
+	return config, nil
+}
+
+// RenderAll renders the template for every device in variables and returns the configs by device
+func (t *ShellyTemplate) RenderAll(variables *ShellyTemplateVariables) (map[string]*ShellyConfig, error) {
+
+	var devices []string
+	for k := range variables.Devices {
+		devices = append(devices, k)
+	}
+
+	sort.Strings(devices)
+
+	var errors *multierror.Error
+
+	configs := map[string]*ShellyConfig{}
+
+	for _, device := range devices {
+
+		config, err := t.Render(variables.DeviceVars(device))
+		if err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("%s :: %w", device, err))
+			continue
+		}
+
+		configs[device] = config
+	}
+
+	if err := errors.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// substituteVars replaces the ${name} references in the string values of v and records the
+// names of missing variables. A string that is a single reference is replaced by the value itself
+// so that numbers and bools keep their type.
+func substituteVars(v interface{}, vars map[string]interface{}, missing map[string]bool) interface{} {
+
+	switch v := v.(type) {
+
+	case string:
+		if ref := templateVarRegex.FindStringSubmatch(v); ref != nil && ref[0] == v && !strings.HasPrefix(v, "$$") {
+			value, ok := vars[ref[1]]
+			if !ok {
+				missing[ref[1]] = true
+				return v
+			}
+			return value
+		}
+
+		return templateVarRegex.ReplaceAllStringFunc(v, func(ref string) string {
+
+			if strings.HasPrefix(ref, "$$") {
+				return ref[1:]
+			}
+
+			name := templateVarRegex.FindStringSubmatch(ref)[1]
+
+			value, ok := vars[name]
+			if !ok {
+				missing[name] = true
+				return ref
+			}
+
+			return fmt.Sprint(value)
+		})
+
+	case map[interface{}]interface{}:
+		for k, value := range v {
+			v[k] = substituteVars(value, vars, missing)
+		}
+
+	case []interface{}:
+		for i, value := range v {
+			v[i] = substituteVars(value, vars, missing)
+		}
+	}
+
+	return v
+}
+
+// validateRendered validates the rendered config and checks for problems introduced by templating
+func validateRendered(config *ShellyConfig) error {
+
+	var errors *multierror.Error
+
+	if err := config.Validate(); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	walkStrings("", reflect.ValueOf(config), func(path string, s reflect.Value) error {
+		if strings.Contains(s.String(), "<no value>") {
+			errors = multierror.Append(errors, fmt.Errorf("%s: template rendered <no value>", path))
+		}
+		return nil
+	})
+
+	return errors.ErrorOrNil()
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShellyTemplateRender(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		template string
+		vars     map[string]interface{}
+		want     *ShellyConfig
+		// err substring of the expected error
+		err string
+	}{
+		{
+			name:     "go template",
+			template: "sys:\n  device:\n    name: {{ .name | quote }}\nmqtt:\n  enable: true\n  server: {{ .server }}\n",
+			vars:     map[string]interface{}{"name": "Plug 1", "server": "broker:1883"},
+			want: &ShellyConfig{
+				System: &SystemConfig{Device: &SystemDevice{Name: ptr("Plug 1")}},
+				Mqtt:   &MqttConfig{Enable: true, Server: ptr("broker:1883")},
+			},
+		},
+		{
+			name:     "var",
+			template: "mqtt:\n  enable: ${enable}\n  server: ${server}\n  client_id: ${device}-mqtt\n",
+			vars:     map[string]interface{}{"enable": true, "server": "broker:1883", "device": "plug-1"},
+			want:     &ShellyConfig{Mqtt: &MqttConfig{Enable: true, Server: ptr("broker:1883"), ClientID: ptr("plug-1-mqtt")}},
+		},
+		{
+			name:     "escape",
+			template: "sys:\n  device:\n    name: ${device} $${device}\n",
+			vars:     map[string]interface{}{"device": "plug-1"},
+			want:     &ShellyConfig{System: &SystemConfig{Device: &SystemDevice{Name: ptr("plug-1 ${device}")}}},
+		},
+		{
+			name:     "missing",
+			template: "mqtt:\n  enable: true\n  server: ${server}\n  client_id: ${client}-${device}\n",
+			vars:     map[string]interface{}{"device": "plug-1"},
+			err:      "missing variables client, server",
+		},
+		{
+			name:     "comment",
+			template: "sys:\n  device:\n    name: ${name}\n",
+			vars:     map[string]interface{}{"name": "Kitchen #2"},
+			want:     &ShellyConfig{System: &SystemConfig{Device: &SystemDevice{Name: ptr("Kitchen #2")}}},
+		},
+		{
+			name:     "bool like",
+			template: "sys:\n  device:\n    name: ${name}\n",
+			vars:     map[string]interface{}{"name": "on"},
+			want:     &ShellyConfig{System: &SystemConfig{Device: &SystemDevice{Name: ptr("on")}}},
+		},
+		{
+			name:     "mapping like",
+			template: "sys:\n  device:\n    name: ${name} plug\n",
+			vars:     map[string]interface{}{"name": "hall: upstairs"},
+			want:     &ShellyConfig{System: &SystemConfig{Device: &SystemDevice{Name: ptr("hall: upstairs plug")}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tmpl, err := NewShellyTemplate(tt.name, []byte(tt.template))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := tmpl.Render(tt.vars)
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(config, tt.want) {
+				t.Errorf("config = %+v, want %+v", config, tt.want)
+			}
+		})
+	}
+}
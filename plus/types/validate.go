@@ -0,0 +1,293 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Violation a single validation failure
+type Violation struct {
+	// Path of the field using the JSON names, for example wifi.sta.netmask or switch:0.in_mode
+	Path string `json:"path" yaml:"path"`
+	// Message describing the problem
+	Message string `json:"message" yaml:"message"`
+}
+
+// String returns the violation in human readable form
+func (t *Violation) String() string {
+	if t.Path == "" {
+		return t.Message
+	}
+	return t.Path + ": " + t.Message
+}
+
+// ValidationError is returned by Validate and holds all violations found
+type ValidationError struct {
+	Violations []*Violation `json:"violations" yaml:"violations"`
+}
+
+func (t *ValidationError) Error() string {
+
+	var s []string
+	for _, v := range t.Violations {
+		s = append(s, v.String())
+	}
+
+	return fmt.Sprintf("%d validation error(s): %s", len(t.Violations), strings.Join(s, "; "))
+}
+
+// validation collects violations
+type validation struct {
+	violations []*Violation
+}
+
+func (t *validation) err() error {
+
+	if len(t.violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		Violations: t.violations,
+	}
+}
+
+func (t *validation) add(path, format string, args ...interface{}) {
+	t.violations = append(t.violations, &Violation{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// required adds a violation if set is false. The reason is optional, for example "when enable is true".
+func (t *validation) required(path string, set bool, reason string) {
+
+	if set {
+		return
+	}
+
+	if reason == "" {
+		t.add(path, "is required")
+		return
+	}
+
+	t.add(path, "is required %s", reason)
+}
+
+func (t *validation) enum(path string, value *string, allowed ...string) {
+
+	if value == nil {
+		return
+	}
+
+	for _, v := range allowed {
+		if *value == v {
+			return
+		}
+	}
+
+	t.add(path, "%q is not one of %s", *value, strings.Join(allowed, ", "))
+}
+
+func (t *validation) rangeFloat(path string, value *float64, min, max float64) {
+
+	if value == nil {
+		return
+	}
+
+	if *value < min || *value > max {
+		t.add(path, "%v is out of range [%v..%v]", *value, min, max)
+	}
+}
+
+func (t *validation) rangeInt(path string, value *int, min, max int) {
+
+	if value == nil {
+		return
+	}
+
+	if *value < min || *value > max {
+		t.add(path, "%d is out of range [%d..%d]", *value, min, max)
+	}
+}
+
+func (t *validation) nonNegative(path string, value *float64) {
+
+	if value == nil {
+		return
+	}
+
+	if *value < 0 {
+		t.add(path, "%v must not be negative", *value)
+	}
+}
+
+func (t *validation) maxLength(path string, value *string, max int) {
+
+	if value == nil {
+		return
+	}
+
+	if len(*value) > max {
+		t.add(path, "length %d exceeds the maximum of %d", len(*value), max)
+	}
+}
+
+func (t *validation) ipv4(path string, value *string) {
+
+	if value == nil {
+		return
+	}
+
+	ip := net.ParseIP(*value)
+	if ip == nil || ip.To4() == nil {
+		t.add(path, "%q is not a valid IPv4 address", *value)
+	}
+}
+
+func (t *validation) netmask(path string, value *string) {
+
+	if value == nil {
+		return
+	}
+
+	ip := net.ParseIP(*value)
+	if ip == nil || ip.To4() == nil {
+		t.add(path, "%q is not a valid IPv4 netmask", *value)
+		return
+	}
+
+	if _, bits := net.IPMask(ip.To4()).Size(); bits == 0 {
+		t.add(path, "%q is not a valid IPv4 netmask", *value)
+	}
+}
+
+// port validates a port number in string form
+func (t *validation) port(path string, value *string) {
+
+	if value == nil {
+		return
+	}
+
+	port, err := strconv.Atoi(*value)
+	if err != nil || port < 1 || port > 65535 {
+		t.add(path, "%q is not a valid port", *value)
+	}
+}
+
+// hostPort validates host or host:port
+func (t *validation) hostPort(path string, value *string) {
+
+	if value == nil {
+		return
+	}
+
+	if *value == "" {
+		t.add(path, "must not be empty")
+		return
+	}
+
+	host, port, err := net.SplitHostPort(*value)
+	if err != nil {
+		// No port
+		if strings.ContainsAny(*value, " /") {
+			t.add(path, "%q is not a valid host", *value)
+		}
+		return
+	}
+
+	if host == "" {
+		t.add(path, "%q is missing the host", *value)
+	}
+
+	t.port(path, &port)
+}
+
+// pem validates that value holds PEM encoded data. Placeholders and secret references are skipped
+// as they are checked by CheckPlaceholders.
+func (t *validation) pem(path string, value *string) {
+
+	if value == nil || IsPlaceholder(*value) || secretRefRegex.MatchString(*value) {
+		return
+	}
+
+	if !strings.Contains(*value, "-----BEGIN ") {
+		t.add(path, "is not PEM encoded")
+	}
+}
+
+// wifiPassword validates the length of a WPA2 passphrase. Placeholders and secret references are
+// skipped as they are checked by CheckPlaceholders.
+func (t *validation) wifiPassword(path string, value *string) {
+
+	if value == nil || *value == "" || IsPlaceholder(*value) || secretRefRegex.MatchString(*value) {
+		return
+	}
+
+	if len(*value) < 8 || len(*value) > 63 {
+		t.add(path, "length must be between 8 and 63 characters")
+	}
+}
+
+// timeOfDay validates HH:MM with optional leading zeros
+func (t *validation) timeOfDay(path string, value string) {
+
+	hours, minutes, found := strings.Cut(value, ":")
+	if !found {
+		t.add(path, "%q is not in the format HH:MM", value)
+		return
+	}
+
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 || h > 23 || len(hours) > 2 {
+		t.add(path, "%q is not in the format HH:MM", value)
+		return
+	}
+
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 || len(minutes) > 2 {
+		t.add(path, "%q is not in the format HH:MM", value)
+	}
+}
+
+// activeBetween validates a list of exactly two HH:MM strings. An empty list is valid.
+func (t *validation) activeBetween(path string, value []string) {
+
+	if len(value) == 0 {
+		return
+	}
+
+	if len(value) != 2 {
+		t.add(path, "must contain exactly 2 elements (start and end), has %d", len(value))
+		return
+	}
+
+	for i, v := range value {
+		t.timeOfDay(fmt.Sprintf("%s.%d", path, i), v)
+	}
+}
+
+// ipv4Config validates the IPv4 settings shared by WiFi STA and Ethernet
+func (t *validation) ipv4Config(path string, mode, ip, netmask, gateway, nameserver *string) {
+
+	t.enum(joinPath(path, "ipv4mode"), mode, "dhcp", "static")
+	t.ipv4(joinPath(path, "ip"), ip)
+	t.netmask(joinPath(path, "netmask"), netmask)
+	t.ipv4(joinPath(path, "gw"), gateway)
+	t.ipv4(joinPath(path, "nameserver"), nameserver)
+
+	if mode != nil && *mode == "static" {
+		t.required(joinPath(path, "ip"), ip != nil, "when ipv4mode is static")
+		t.required(joinPath(path, "netmask"), netmask != nil, "when ipv4mode is static")
+		t.required(joinPath(path, "gw"), gateway != nil, "when ipv4mode is static")
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
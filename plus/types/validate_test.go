@@ -0,0 +1,168 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidationBounds(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		check func(v *validation)
+		valid bool
+	}{
+		{"rangeInt min", func(v *validation) { v.rangeInt("p", ptr(-127), -127, 0) }, true},
+		{"rangeInt max", func(v *validation) { v.rangeInt("p", ptr(0), -127, 0) }, true},
+		{"rangeInt below", func(v *validation) { v.rangeInt("p", ptr(-128), -127, 0) }, false},
+		{"rangeInt above", func(v *validation) { v.rangeInt("p", ptr(1), -127, 0) }, false},
+		{"rangeInt nil", func(v *validation) { v.rangeInt("p", nil, -127, 0) }, true},
+
+		{"rangeFloat min", func(v *validation) { v.rangeFloat("p", ptr(1.0), 1, 50) }, true},
+		{"rangeFloat max", func(v *validation) { v.rangeFloat("p", ptr(50.0), 1, 50) }, true},
+		{"rangeFloat below", func(v *validation) { v.rangeFloat("p", ptr(0.99), 1, 50) }, false},
+		{"rangeFloat above", func(v *validation) { v.rangeFloat("p", ptr(50.01), 1, 50) }, false},
+
+		{"nonNegative zero", func(v *validation) { v.nonNegative("p", ptr(0.0)) }, true},
+		{"nonNegative negative", func(v *validation) { v.nonNegative("p", ptr(-0.1)) }, false},
+
+		{"maxLength at max", func(v *validation) { v.maxLength("p", ptr(strings.Repeat("a", 300)), 300) }, true},
+		{"maxLength above", func(v *validation) { v.maxLength("p", ptr(strings.Repeat("a", 301)), 300) }, false},
+
+		{"enum allowed", func(v *validation) { v.enum("p", ptr("on"), "off", "on") }, true},
+		{"enum not allowed", func(v *validation) { v.enum("p", ptr("On"), "off", "on") }, false},
+
+		{"ipv4", func(v *validation) { v.ipv4("p", ptr("192.168.1.1")) }, true},
+		{"ipv4 out of range", func(v *validation) { v.ipv4("p", ptr("192.168.1.256")) }, false},
+		{"ipv4 is ipv6", func(v *validation) { v.ipv4("p", ptr("::1")) }, false},
+
+		{"netmask", func(v *validation) { v.netmask("p", ptr("255.255.255.0")) }, true},
+		{"netmask all", func(v *validation) { v.netmask("p", ptr("255.255.255.255")) }, true},
+		{"netmask not contiguous", func(v *validation) { v.netmask("p", ptr("255.0.255.0")) }, false},
+
+		{"port min", func(v *validation) { v.port("p", ptr("1")) }, true},
+		{"port max", func(v *validation) { v.port("p", ptr("65535")) }, true},
+		{"port zero", func(v *validation) { v.port("p", ptr("0")) }, false},
+		{"port above", func(v *validation) { v.port("p", ptr("65536")) }, false},
+
+		{"hostPort host", func(v *validation) { v.hostPort("p", ptr("broker")) }, true},
+		{"hostPort host and port", func(v *validation) { v.hostPort("p", ptr("broker:1883")) }, true},
+		{"hostPort empty", func(v *validation) { v.hostPort("p", ptr("")) }, false},
+		{"hostPort missing host", func(v *validation) { v.hostPort("p", ptr(":1883")) }, false},
+		{"hostPort bad port", func(v *validation) { v.hostPort("p", ptr("broker:99999")) }, false},
+		{"hostPort url", func(v *validation) { v.hostPort("p", ptr("mqtt://broker")) }, false},
+
+		{"pem", func(v *validation) { v.pem("p", ptr("-----BEGIN CERTIFICATE-----\n")) }, true},
+		{"pem placeholder", func(v *validation) { v.pem("p", ptr(genericPassword)) }, true},
+		{"pem not encoded", func(v *validation) { v.pem("p", ptr("MIIB")) }, false},
+
+		{"wifiPassword min", func(v *validation) { v.wifiPassword("p", ptr(strings.Repeat("a", 8))) }, true},
+		{"wifiPassword max", func(v *validation) { v.wifiPassword("p", ptr(strings.Repeat("a", 63))) }, true},
+		{"wifiPassword short", func(v *validation) { v.wifiPassword("p", ptr(strings.Repeat("a", 7))) }, false},
+		{"wifiPassword long", func(v *validation) { v.wifiPassword("p", ptr(strings.Repeat("a", 64))) }, false},
+		{"wifiPassword empty", func(v *validation) { v.wifiPassword("p", ptr("")) }, true},
+		{"wifiPassword placeholder", func(v *validation) { v.wifiPassword("p", ptr(genericPassword)) }, true},
+
+		{"timeOfDay midnight", func(v *validation) { v.timeOfDay("p", "0:00") }, true},
+		{"timeOfDay last minute", func(v *validation) { v.timeOfDay("p", "23:59") }, true},
+		{"timeOfDay hour", func(v *validation) { v.timeOfDay("p", "24:00") }, false},
+		{"timeOfDay minute", func(v *validation) { v.timeOfDay("p", "12:60") }, false},
+		{"timeOfDay format", func(v *validation) { v.timeOfDay("p", "1200") }, false},
+
+		{"activeBetween empty", func(v *validation) { v.activeBetween("p", nil) }, true},
+		{"activeBetween pair", func(v *validation) { v.activeBetween("p", []string{"22:00", "06:00"}) }, true},
+		{"activeBetween one", func(v *validation) { v.activeBetween("p", []string{"22:00"}) }, false},
+
+		{"ipv4Config dhcp", func(v *validation) { v.ipv4Config("p", ptr("dhcp"), nil, nil, nil, nil) }, true},
+		{"ipv4Config static", func(v *validation) {
+			v.ipv4Config("p", ptr("static"), ptr("10.0.0.2"), ptr("255.0.0.0"), ptr("10.0.0.1"), nil)
+		}, true},
+		{"ipv4Config static without ip", func(v *validation) { v.ipv4Config("p", ptr("static"), nil, nil, nil, nil) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			v := &validation{}
+			tt.check(v)
+
+			if valid := v.err() == nil; valid != tt.valid {
+				t.Errorf("valid = %v, want %v: %v", valid, tt.valid, v.err())
+			}
+		})
+	}
+}
+
+func TestShellyConfigValidatePaths(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		config *ShellyConfig
+		paths  []string
+	}{
+		{
+			name:   "valid",
+			config: &ShellyConfig{Switch: []*SwitchConfig{{ID: 0, InputID: ptr(1)}}},
+		},
+		{
+			name:   "switch input_id",
+			config: &ShellyConfig{Switch: []*SwitchConfig{{ID: 1, InputID: ptr(2)}}},
+			paths:  []string{"switch:1.input_id"},
+		},
+		{
+			name: "switch undervoltage",
+			config: &ShellyConfig{Switch: []*SwitchConfig{{ID: 0, VoltageLimit: ptr(200.0),
+				UndervoltageLimit: ptr(200.0)}}},
+			paths: []string{"switch:0.undervoltage_limit"},
+		},
+		{
+			name:   "light brightness",
+			config: &ShellyConfig{Light: []*LightConfig{{ID: 0, DefaultBrightness: ptr(101.0)}}},
+			paths:  []string{"light:0.default.brightness"},
+		},
+		{
+			name:   "input report_thr",
+			config: &ShellyConfig{Input: []*InputConfig{{ID: 0, Type: ptr("analog"), ReportThreshold: ptr(0.5)}}},
+			paths:  []string{"input:0.report_thr"},
+		},
+		{
+			name:   "mqtt server required",
+			config: &ShellyConfig{Mqtt: &MqttConfig{Enable: true}},
+			paths:  []string{"mqtt.server"},
+		},
+		{
+			name:   "wifi roam and sta",
+			config: &ShellyConfig{Wifi: &WifiConfig{Roam: &WifiRoamConfig{RSSIThreshold: ptr(-128)}, Sta: &WifiSTAConfig{Enable: true}}},
+			paths:  []string{"wifi.sta.ssid", "wifi.roam.rssi_thr"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := tt.config.Validate()
+
+			if len(tt.paths) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("error = %v, want *ValidationError", err)
+			}
+
+			var paths []string
+			for _, v := range validationErr.Violations {
+				paths = append(paths, v.Path)
+			}
+
+			if strings.Join(paths, ",") != strings.Join(tt.paths, ",") {
+				t.Errorf("paths = %v, want %v", paths, tt.paths)
+			}
+		})
+	}
+}
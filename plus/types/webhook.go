@@ -1,6 +1,8 @@
 package types
 
 import (
+	"fmt"
+
 	"github.com/jinzhu/copier"
 )
 
@@ -139,6 +141,38 @@ func (t *Webhook) Sanatize() {
 
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *Webhook) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *Webhook) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	v.required(joinPath(path, "event"), t.Event != nil && *t.Event != "", "")
+	v.required(joinPath(path, "cid"), t.Cid != nil, "")
+	v.enum(joinPath(path, "ssl_ca"), t.SslCa, "user_ca.pem", "ca.pem")
+
+	if len(t.URLs) == 0 {
+		v.add(joinPath(path, "urls"), "at least one url is required")
+	}
+
+	if len(t.URLs) > 5 {
+		v.add(joinPath(path, "urls"), "has %d urls, the maximum is 5", len(t.URLs))
+	}
+
+	for i := range t.URLs {
+		v.maxLength(fmt.Sprintf("%s.%d", joinPath(path, "urls"), i), &t.URLs[i], 300)
+	}
+
+	v.activeBetween(joinPath(path, "active_between"), t.ActiveBetween)
+}
+
 type WebhookConfig struct {
 	Webhooks []*Webhook `json:"hooks" yaml:"hooks"`
 	Revision *int       `json:"rev,omitempty" yaml:"rev,omitempty"`
@@ -189,3 +223,21 @@ func (t *WebhookConfig) Sanatize() {
 		v.Sanatize()
 	}
 }
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *WebhookConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *WebhookConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	for i, w := range t.Webhooks {
+		w.validate(fmt.Sprintf("%s.%d", joinPath(path, "hooks"), i), v)
+	}
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"strings"
+
 	"github.com/jinzhu/copier"
 )
 
@@ -59,3 +61,27 @@ func (t *WebsocketConfig) Sanatize() {
 	}
 
 }
+
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *WebsocketConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *WebsocketConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	if t.Enable {
+		v.required(joinPath(path, "server"), t.Server != nil, "when enable is true")
+	}
+
+	if t.Server != nil && !strings.HasPrefix(*t.Server, "ws://") && !strings.HasPrefix(*t.Server, "wss://") {
+		v.add(joinPath(path, "server"), "%q must start with ws:// or wss://", *t.Server)
+	}
+
+	v.enum(joinPath(path, "ssl_ca"), t.SslCa, "user_ca.pem", "ca.pem")
+}
@@ -120,6 +120,25 @@ func (t *WifiConfig) Sanatize() {
 	t.Roam.Sanatize()
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *WifiConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *WifiConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	t.Ap.validate(joinPath(path, "ap"), v)
+	t.Sta.validate(joinPath(path, "sta"), v)
+	t.Sta1.validate(joinPath(path, "sta1"), v)
+	t.Roam.validate(joinPath(path, "roam"), v)
+}
+
 // WifiAPConfig WiFi component object
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration
 type WifiAPConfig struct {
@@ -152,7 +171,8 @@ func (t *WifiAPConfig) Markup() {
 	t.SSID = nil
 
 	if t.Enable {
-		if t.Pass == nil {
+		// An open network has no password
+		if t.Pass == nil && !isOpen(t.IsOpen) {
 			tmp := genericPassword
 			t.Pass = &tmp
 		}
@@ -174,6 +194,9 @@ func (t *WifiAPConfig) Sanatize() {
 	}
 
 	if t.Enable {
+		if isOpen(t.IsOpen) {
+			t.Pass = nil
+		}
 		t.RangeExtender.Sanatize()
 		return
 	}
@@ -182,6 +205,23 @@ func (t *WifiAPConfig) Sanatize() {
 	t.RangeExtender = nil
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *WifiAPConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *WifiAPConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	v.wifiPassword(joinPath(path, "pass"), t.Pass)
+	t.RangeExtender.validate(joinPath(path, "range_extender"), v)
+}
+
 // WifiRangeExtenderConfig Range extender configuration object, available only when range extender functionality is present.
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration
 type WifiRangeExtenderConfig struct {
@@ -213,6 +253,21 @@ func (t *WifiRangeExtenderConfig) Sanatize() {
 
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *WifiRangeExtenderConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *WifiRangeExtenderConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+}
+
 // WifiSTAConfig WiFi component object
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration
 type WifiSTAConfig struct {
@@ -251,7 +306,7 @@ func (t *WifiSTAConfig) Markup() {
 	}
 
 	if t.Enable {
-		if t.Pass == nil {
+		if t.Pass == nil && !isOpen(t.IsOpen) {
 			tmp := genericPassword
 			t.Pass = &tmp
 		}
@@ -275,12 +330,6 @@ func (t *WifiSTAConfig) Sanatize() {
 		return
 	}
 
-	if t.Pass != nil {
-		if *t.Pass == genericPassword {
-			t.Pass = nil
-		}
-	}
-
 	if t.Ipv4Mode != nil {
 		if *t.Ipv4Mode == "dhcp" {
 			t.IP = nil
@@ -292,6 +341,27 @@ func (t *WifiSTAConfig) Sanatize() {
 
 }
 
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *WifiSTAConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
+}
+
+func (t *WifiSTAConfig) validate(path string, v *validation) {
+
+	if t == nil {
+		return
+	}
+
+	if t.Enable {
+		v.required(joinPath(path, "ssid"), t.SSID != nil, "when enable is true")
+	}
+
+	v.wifiPassword(joinPath(path, "pass"), t.Pass)
+	v.ipv4Config(path, t.Ipv4Mode, t.IP, t.Netmask, t.Gateway, t.Nameserver)
+}
+
 // WifiRoamConfig WiFi roaming configuration
 // https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi#configuration
 type WifiRoamConfig struct {
@@ -327,61 +397,27 @@ func (t *WifiRoamConfig) Sanatize() {
 
 }
 
-// Scan WiFi component object
-// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi
-type WifiNet struct {
-	SSID    *string `json:"ssid" yaml:"ssid"`
-	BSSID   *string `json:"bssid" yaml:"bssid"`
-	Auth    *int    `json:"auth" yaml:"auth"`
-	Channel *int    `json:"channel" yaml:"channel"`
-	RSSI    *int    `json:"rssi" yaml:"rssi"`
-}
-
-// Clone return copy
-func (t *WifiNet) Clone() *WifiNet {
-	c := &WifiNet{}
-	copier.Copy(&c, &t)
-	return c
-}
-
-// WifiAPClient WiFi component object
-// https://shelly-api-docs.shelly.cloud/gen2/ComponentsAndServices/WiFi
-type WifiAPClient struct {
-	MAC      *string `json:"mac" yaml:"mac"`
-	IP       *string `json:"ip" yaml:"ip"`
-	IPStatic *bool   `json:"ip_static" yaml:"ip_static"`
-	Mport    *int    `json:"mport" yaml:"mport"`
-	Since    *int    `json:"since" yaml:"since"`
+// Validate returns a ValidationError listing all violations or nil if the config is valid
+func (t *WifiRoamConfig) Validate() error {
+	v := &validation{}
+	t.validate("", v)
+	return v.err()
 }
 
-// Clone return copy
-func (t *WifiAPClient) Clone() *WifiAPClient {
-	c := &WifiAPClient{}
-	copier.Copy(&c, &t)
-	return c
-}
+func (t *WifiRoamConfig) validate(path string, v *validation) {
 
-// WifiScanResults Wifi Scan Results
-type WifiScanResults struct {
-	Results []WifiNet `json:"results"`
-}
+	if t == nil {
+		return
+	}
 
-// Clone return copy
-func (t *WifiScanResults) Clone() *WifiScanResults {
-	c := &WifiScanResults{}
-	copier.Copy(&c, &t)
-	return c
-}
+	v.rangeInt(joinPath(path, "rssi_thr"), t.RSSIThreshold, -127, 0)
 
-// WifiAPClients Wifi AP Clients
-type WifiAPClients struct {
-	Ts      *int           `json:"ts"`
-	Clients []WifiAPClient `json:"ap_clients"`
+	if t.Interval != nil && *t.Interval < 0 {
+		v.add(joinPath(path, "interval"), "%d must not be negative", *t.Interval)
+	}
 }
 
-// Clone return copy
-func (t *WifiAPClients) Clone() *WifiAPClients {
-	c := &WifiAPClients{}
-	copier.Copy(&c, &t)
-	return c
+// isOpen returns true if the network is known to be open, without a password
+func isOpen(open *bool) bool {
+	return open != nil && *open
 }
@@ -1,5 +0,0 @@
-package websocket
-
-const (
-	Component = "Ws"
-)
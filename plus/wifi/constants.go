@@ -1,5 +0,0 @@
-package wifi
-
-const (
-	Component = "Wifi"
-)
@@ -1,17 +1,23 @@
+// Code generated by gen_components.go; DO NOT EDIT.
+
 package wifi
 
 import (
 	"github.com/jodydadescott/shelly-go-sdk/plus/types"
 )
 
+const (
+	Component = "Wifi"
+)
+
 type Request = types.Request
 type Response = types.Response
 type Error = types.Error
 type MessageHandlerFactory = types.MessageHandlerFactory
 type MessageHandler = types.MessageHandler
 
-type Config = types.WifiConfig
 type Status = types.WifiStatus
+type Config = types.WifiConfig
 type WifiScanResults = types.WifiScanResults
 type APClients = types.WifiAPClients
 type WifiNet = types.WifiNet
@@ -21,15 +27,14 @@ type StaConfig = types.WifiSTAConfig
 type RoamConfig = types.WifiRoamConfig
 type RangeExtenderConfig = types.WifiRangeExtenderConfig
 
-// Result internal use only
-type Result struct {
-	RestartRequired *bool  `json:"restart_required,omitempty"`
-	Error           *Error `json:"error,omitempty"`
-}
-
 // Params internal use only
 type Params struct {
-	Config *Config `json:"config,omitempty"`
+	Config *Config `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// Result internal use only
+type Result struct {
+	RestartRequired *bool `json:"restart_required,omitempty"`
 }
 
 // GetConfigResponse internal use only
@@ -0,0 +1,33 @@
+//go:build ignore
+
+// gen writes the JSON Schema documents returned by types.JSONSchemas to the current directory
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/jodydadescott/shelly-go-sdk/plus/types"
+)
+
+func main() {
+
+	schemas, err := types.JSONSchemas()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for name, schema := range schemas {
+
+		b, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = os.WriteFile(name, append(b, '\n'), 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
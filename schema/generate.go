@@ -0,0 +1,10 @@
+// Package schema holds the JSON Schema documents for the Shelly config and status types. The documents
+// can be used to validate and autocomplete YAML configs in editors and CI, for example with
+// yaml-language-server:
+//
+//	# yaml-language-server: $schema=https://raw.githubusercontent.com/jodydadescott/shelly-go-sdk/main/schema/shelly_config.json
+//
+// Run go generate after changing the types to regenerate the documents.
+package schema
+
+//go:generate go run gen.go
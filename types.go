@@ -7,3 +7,19 @@ import (
 
 type MessageHandlerFactory = types.MessageHandlerFactory
 type PlusClient = plus.Client
+type RetryPolicy = types.RetryPolicy
+type Interceptor = types.Interceptor
+type SendFunc = types.SendFunc
+type Metrics = types.Metrics
+
+// WithIdempotent marks the requests sent with the returned context as safe to retry
+var WithIdempotent = types.WithIdempotent
+
+// Errors returned by the SDK. Use errors.Is to test for them.
+var (
+	ErrTimeout           = types.ErrTimeout
+	ErrNotConnected      = types.ErrNotConnected
+	ErrClosed            = types.ErrClosed
+	ErrAuthRequired      = types.ErrAuthRequired
+	ErrMalformedResponse = types.ErrMalformedResponse
+)